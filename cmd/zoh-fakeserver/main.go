@@ -0,0 +1,32 @@
+// Command zoh-fakeserver serves the in-memory fake Zoho API for offline testing.
+//
+// Point the CLI at it with:
+//
+//	ZOH_API_BASE=http://127.0.0.1:8787 ZOH_ACCESS_TOKEN=test zoh mail folders list
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8787", "listen address")
+	token := flag.String("token", "", "require this bearer token on API requests (default: accept any)")
+	empty := flag.Bool("empty", false, "start without sample data")
+	flag.Parse()
+
+	srv := zohotest.New()
+	srv.AccessToken = *token
+	if !*empty {
+		srv.LoadSampleData()
+	}
+
+	fmt.Fprintf(os.Stderr, "fake Zoho API listening on http://%s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
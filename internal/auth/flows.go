@@ -19,7 +19,7 @@ import (
 
 // newOAuth2Config creates an oauth2.Config for Zoho authentication.
 func newOAuth2Config(cfg *config.Config, redirectURL string) (*oauth2.Config, error) {
-	region, err := config.GetRegion(cfg.Region)
+	region, err := cfg.GetRegionConfig()
 	if err != nil {
 		return nil, err
 	}
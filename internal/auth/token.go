@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/adrg/xdg"
@@ -24,6 +25,7 @@ type TokenCache struct {
 	cachePath    string        // Path to cached access token file
 	lockPath     string        // Path to lock file
 	store        secrets.Store // Secret store for refresh token
+	profile      string        // Profile name
 	region       string        // Region code
 	clientID     string
 	clientSecret string
@@ -39,12 +41,17 @@ type cachedToken struct {
 
 // NewTokenCache creates a new token cache for the given configuration.
 func NewTokenCache(cfg *config.Config, store secrets.Store) (*TokenCache, error) {
-	region, err := config.GetRegion(cfg.Region)
+	region, err := cfg.GetRegionConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	cachePath := filepath.Join(xdg.CacheHome, "zoh", fmt.Sprintf("token-%s.json", cfg.Region))
+	profile := cfg.Profile
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+
+	cachePath := TokenCachePath(profile, cfg.Region)
 	lockPath := cachePath + ".lock"
 
 	// Ensure cache directory exists
@@ -57,6 +64,7 @@ func NewTokenCache(cfg *config.Config, store secrets.Store) (*TokenCache, error)
 		cachePath:    cachePath,
 		lockPath:     lockPath,
 		store:        store,
+		profile:      profile,
 		region:       cfg.Region,
 		clientID:     cfg.ClientID,
 		clientSecret: cfg.ClientSecret,
@@ -64,6 +72,49 @@ func NewTokenCache(cfg *config.Config, store secrets.Store) (*TokenCache, error)
 	}, nil
 }
 
+// refreshTokenPrefix is the secrets-store key prefix for refresh tokens
+const refreshTokenPrefix = "refresh_token_"
+
+// profileKeyPrefix namespaces secrets-store keys belonging to named profiles
+const profileKeyPrefix = "profile."
+
+// RefreshTokenKey returns the secrets-store key for a profile's refresh token.
+// The default profile keeps the legacy "refresh_token_<region>" key.
+func RefreshTokenKey(profile, region string) string {
+	if profile == "" || profile == config.DefaultProfile {
+		return refreshTokenPrefix + region
+	}
+	return profileKeyPrefix + profile + "." + refreshTokenPrefix + region
+}
+
+// ParseRefreshTokenKey extracts the profile and region from a secrets-store key.
+// Returns ok=false for keys that are not refresh tokens.
+func ParseRefreshTokenKey(key string) (profile, region string, ok bool) {
+	if strings.HasPrefix(key, refreshTokenPrefix) {
+		return config.DefaultProfile, strings.TrimPrefix(key, refreshTokenPrefix), true
+	}
+
+	if !strings.HasPrefix(key, profileKeyPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(key, profileKeyPrefix), ".", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], refreshTokenPrefix) {
+		return "", "", false
+	}
+
+	return parts[0], strings.TrimPrefix(parts[1], refreshTokenPrefix), true
+}
+
+// TokenCachePath returns the access-token cache file for a profile and region.
+// The default profile keeps the legacy "token-<region>.json" location.
+func TokenCachePath(profile, region string) string {
+	if profile == "" || profile == config.DefaultProfile {
+		return filepath.Join(xdg.CacheHome, "zoh", fmt.Sprintf("token-%s.json", region))
+	}
+	return filepath.Join(xdg.CacheHome, "zoh", "profiles", profile, fmt.Sprintf("token-%s.json", region))
+}
+
 // Token implements oauth2.TokenSource.Token().
 // Returns a valid access token, refreshing if necessary.
 func (tc *TokenCache) Token() (*oauth2.Token, error) {
@@ -143,12 +194,16 @@ func (tc *TokenCache) writeCachedToken(token *oauth2.Token) error {
 // refreshToken exchanges the refresh token for a new access token.
 func (tc *TokenCache) refreshToken() (*oauth2.Token, error) {
 	// Get refresh token from secrets store
-	refreshTokenKey := fmt.Sprintf("refresh_token_%s", tc.region)
+	refreshTokenKey := RefreshTokenKey(tc.profile, tc.region)
 	refreshToken, err := tc.store.Get(refreshTokenKey)
 	if err != nil {
 		if err == secrets.ErrNotFound {
+			msg := "No refresh token found. Run: zoh auth login"
+			if tc.profile != config.DefaultProfile {
+				msg = fmt.Sprintf("No refresh token found for profile %s. Run: zoh --profile %s auth login", tc.profile, tc.profile)
+			}
 			return nil, &output.CLIError{
-				Message:  "No refresh token found. Run: zoh auth login",
+				Message:  msg,
 				ExitCode: output.ExitAuth,
 			}
 		}
@@ -247,7 +302,7 @@ func (tc *TokenCache) SaveInitialTokens(token *oauth2.Token) error {
 	defer lock.Unlock()
 
 	// Store refresh token in secrets store
-	refreshTokenKey := fmt.Sprintf("refresh_token_%s", tc.region)
+	refreshTokenKey := RefreshTokenKey(tc.profile, tc.region)
 	if err := tc.store.Set(refreshTokenKey, token.RefreshToken); err != nil {
 		return fmt.Errorf("failed to store refresh token: %w", err)
 	}
@@ -277,7 +332,7 @@ func (tc *TokenCache) ClearTokens() error {
 	defer lock.Unlock()
 
 	// Delete refresh token from secrets store
-	refreshTokenKey := fmt.Sprintf("refresh_token_%s", tc.region)
+	refreshTokenKey := RefreshTokenKey(tc.profile, tc.region)
 	if err := tc.store.Delete(refreshTokenKey); err != nil && err != secrets.ErrNotFound {
 		return fmt.Errorf("failed to delete refresh token: %w", err)
 	}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenKey(t *testing.T) {
+	// Default profile keeps the legacy key so existing logins survive upgrades
+	assert.Equal(t, "refresh_token_eu", RefreshTokenKey("default", "eu"))
+	assert.Equal(t, "refresh_token_us", RefreshTokenKey("", "us"))
+
+	assert.Equal(t, "profile.ops-eu.refresh_token_eu", RefreshTokenKey("ops-eu", "eu"))
+}
+
+func TestParseRefreshTokenKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		profile string
+		region  string
+		ok      bool
+	}{
+		{name: "legacy key", key: "refresh_token_us", profile: "default", region: "us", ok: true},
+		{name: "profile key", key: "profile.ops-eu.refresh_token_eu", profile: "ops-eu", region: "eu", ok: true},
+		{name: "profile with underscore", key: "profile.acme_1.refresh_token_in", profile: "acme_1", region: "in", ok: true},
+		{name: "unrelated key", key: "client_secret", ok: false},
+		{name: "profile non-token key", key: "profile.ops-eu.something", ok: false},
+		{name: "truncated profile key", key: "profile.ops-eu", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, region, ok := ParseRefreshTokenKey(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.profile, profile)
+			assert.Equal(t, tt.region, region)
+		})
+	}
+
+	// Round trip
+	profile, region, ok := ParseRefreshTokenKey(RefreshTokenKey("ops", "jp"))
+	assert.True(t, ok)
+	assert.Equal(t, "ops", profile)
+	assert.Equal(t, "jp", region)
+}
+
+func TestTokenCachePath(t *testing.T) {
+	assert.Equal(t, "token-eu.json", filepath.Base(TokenCachePath("default", "eu")))
+
+	named := TokenCachePath("ops-eu", "eu")
+	assert.Equal(t, "token-eu.json", filepath.Base(named))
+	assert.Equal(t, "ops-eu", filepath.Base(filepath.Dir(named)))
+	assert.NotEqual(t, TokenCachePath("default", "eu"), named)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/SeMmyT/zohcli/internal/output"
 	"github.com/SeMmyT/zohcli/internal/zoho"
@@ -406,3 +407,109 @@ func (cmd *AdminUsersDeleteCmd) Run(sp *ServiceProvider, fp *FormatterProvider,
 
 	return nil
 }
+
+// AdminUsersVacationSetCmd sets a vacation auto-reply on a user's mailbox
+type AdminUsersVacationSetCmd struct {
+	Identifier string `arg:"" help:"User ID (zuid) or email address"`
+	VacationFlags
+}
+
+// Run executes the admin set vacation command
+func (cmd *AdminUsersVacationSetCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	adminClient, err := sp.Admin()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Resolve identifier to zuid
+	zuid, user, err := resolveUserID(ctx, adminClient, cmd.Identifier)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to find user: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Zoho reads the dates in the user's mailbox time zone, which only the
+	// user's details carry
+	if user.TimeZone == "" {
+		if user, err = adminClient.GetUser(ctx, zuid); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch user details: %v", err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+	}
+	mailboxTZ := user.TimeZone
+	if _, err := time.LoadLocation(mailboxTZ); err != nil {
+		mailboxTZ = ""
+	}
+	if mailboxTZ == "" && cmd.TZ == "" {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("The time zone of %s's mailbox is unknown; pass --tz with it", user.PrimaryEmail()),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	vacation, err := cmd.reply(time.Now(), mailboxTZ, user.DisplayName, user.PrimaryEmail())
+	if err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would enable vacation reply for %s (%s to %s): subject=%s\n", user.PrimaryEmail(), vacation.FromDate, vacation.ToDate, vacation.Subject)
+		return nil
+	}
+
+	if err := adminClient.SetUserVacationReply(ctx, zuid, vacation); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to enable vacation reply: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Vacation reply enabled for %s (%s to %s)\n", user.PrimaryEmail(), vacation.FromDate, vacation.ToDate)
+	return nil
+}
+
+// AdminUsersVacationDisableCmd turns off a user's vacation auto-reply
+type AdminUsersVacationDisableCmd struct {
+	Identifier string `arg:"" help:"User ID (zuid) or email address"`
+}
+
+// Run executes the admin disable vacation command
+func (cmd *AdminUsersVacationDisableCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would disable vacation reply for: %s\n", cmd.Identifier)
+		return nil
+	}
+
+	adminClient, err := sp.Admin()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Resolve identifier to zuid
+	zuid, user, err := resolveUserID(ctx, adminClient, cmd.Identifier)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to find user: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	if err := adminClient.DisableUserVacationReply(ctx, zuid); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to disable vacation reply: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Vacation reply disabled for %s\n", user.PrimaryEmail())
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/SeMmyT/zohcli/internal/config"
 	"github.com/SeMmyT/zohcli/internal/output"
 	"github.com/SeMmyT/zohcli/internal/secrets"
+	"github.com/SeMmyT/zohcli/internal/zoho"
 )
 
 // AuthLoginCmd implements the auth login command
@@ -24,10 +26,14 @@ type AuthLoginCmd struct {
 func (cmd *AuthLoginCmd) Run(cfg *config.Config, fp *FormatterProvider, globals *Globals) error {
 	// Validate required config
 	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		zohCmd := "zoh"
+		if cfg.Profile != config.DefaultProfile {
+			zohCmd = "zoh --profile " + cfg.Profile
+		}
 		return &output.CLIError{
 			Message: "Client ID and Client Secret required.\n\n" +
-				"Run: zoh config set client_id YOUR_CLIENT_ID\n" +
-				"Run: zoh config set client_secret YOUR_CLIENT_SECRET\n\n" +
+				"Run: " + zohCmd + " config set client_id YOUR_CLIENT_ID\n" +
+				"Run: " + zohCmd + " config set client_secret YOUR_CLIENT_SECRET\n\n" +
 				"Get credentials at: https://api-console.zoho.com/",
 			ExitCode: output.ExitConfigError,
 		}
@@ -80,14 +86,30 @@ func (cmd *AuthLoginCmd) Run(cfg *config.Config, fp *FormatterProvider, globals
 	// Persist region to config if it was set via global flag
 	if globals.Region != "" {
 		cfg.Region = globals.Region
+	}
+
+	// Record which Zoho identity this profile is bound to
+	identity, err := zoho.ResolveIdentity(ctx, cfg, tokenCache)
+	if err != nil {
+		// Non-fatal - login succeeded, only the identity lookup failed
+		fmt.Fprintf(os.Stderr, "Warning: failed to resolve account identity: %v\n", err)
+	} else {
+		bindIdentity(cfg, identity)
+	}
+
+	if globals.Region != "" || identity != nil {
 		if err := cfg.Save(); err != nil {
 			// Non-fatal - log warning
-			fmt.Fprintf(os.Stderr, "Warning: failed to save region to config: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to save profile config: %v\n", err)
 		}
 	}
 
 	// Output success
 	fmt.Fprintf(os.Stderr, "✓ Authenticated successfully\n")
+	fmt.Fprintf(os.Stderr, "Profile: %s\n", cfg.Profile)
+	if cfg.Email != "" {
+		fmt.Fprintf(os.Stderr, "Account: %s\n", cfg.Email)
+	}
 	fmt.Fprintf(os.Stderr, "Region: %s\n", cfg.Region)
 	fmt.Fprintf(os.Stderr, "Token expires: %s\n", token.Expiry.Format(time.RFC3339))
 
@@ -101,9 +123,24 @@ func (cmd *AuthLoginCmd) Run(cfg *config.Config, fp *FormatterProvider, globals
 	return nil
 }
 
+// bindIdentity records the identity a profile logged in as. A configured
+// account or organization ID is kept when the identity is unchanged, so a
+// profile pointed at a shared or secondary mailbox survives re-login. An
+// identity without an organization ID never clears a configured one.
+func bindIdentity(cfg *config.Config, identity *zoho.Identity) {
+	sameIdentity := cfg.Email != "" && strings.EqualFold(cfg.Email, identity.Email)
+	if !sameIdentity || cfg.AccountID == "" {
+		cfg.AccountID = identity.AccountID
+	}
+	if identity.OrgID != "" && (!sameIdentity || cfg.OrgID == "") {
+		cfg.OrgID = identity.OrgID
+	}
+	cfg.Email = identity.Email
+}
+
 // AuthLogoutCmd implements the auth logout command
 type AuthLogoutCmd struct {
-	All bool `help:"Remove all stored accounts" short:"a"`
+	All bool `help:"Remove stored credentials for every profile" short:"a"`
 }
 
 // Run executes the logout command
@@ -117,24 +154,38 @@ func (cmd *AuthLogoutCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 	}
 
 	if cmd.All {
-		// Clear tokens for all regions
-		regions := []string{"us", "eu", "in", "au", "jp", "ca", "sa", "uk"}
-		for _, region := range regions {
-			// Create temp config for this region
+		accounts, err := storedAccounts(store)
+		if err != nil {
+			return err
+		}
+
+		// Walk every stored refresh token, whichever profile and region it belongs to
+		var failed []string
+		for _, acct := range accounts {
 			tempCfg := &config.Config{
-				Region:       region,
-				ClientID:     cfg.ClientID,
-				ClientSecret: cfg.ClientSecret,
+				Profile: acct.profile,
+				Region:  acct.region,
 			}
 			tokenCache, err := auth.NewTokenCache(tempCfg, store)
+			if err == nil {
+				err = tokenCache.ClearTokens()
+			}
 			if err != nil {
-				continue // Skip regions with errors
+				failed = append(failed, fmt.Sprintf("%s/%s: %v", acct.profile, acct.region, err))
+				continue
 			}
-			tokenCache.ClearTokens() // Ignore errors for missing tokens
+			fmt.Fprintf(os.Stderr, "Logged out profile %s (region: %s)\n", acct.profile, acct.region)
 		}
-		fmt.Fprintf(os.Stderr, "Logged out all accounts\n")
+
+		if len(failed) > 0 {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to clear tokens:\n  %s", strings.Join(failed, "\n  ")),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Logged out all accounts (%d)\n", len(accounts))
 	} else {
-		// Clear tokens for current region
+		// Clear tokens for current profile and region
 		tokenCache, err := auth.NewTokenCache(cfg, store)
 		if err != nil {
 			return &output.CLIError{
@@ -150,18 +201,63 @@ func (cmd *AuthLogoutCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 			}
 		}
 
-		fmt.Fprintf(os.Stderr, "Logged out from region: %s\n", cfg.Region)
+		fmt.Fprintf(os.Stderr, "Logged out profile %s (region: %s)\n", cfg.Profile, cfg.Region)
 	}
 
 	fmt.Fprintf(os.Stderr, "Credentials removed\n")
 	return nil
 }
 
+// storedAccount identifies a refresh token in the secrets store
+type storedAccount struct {
+	profile string
+	region  string
+}
+
+// storedAccounts returns every profile/region pair that has a stored refresh token
+func storedAccounts(store secrets.Store) ([]storedAccount, error) {
+	items, err := store.List()
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to list stored credentials: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	var accounts []storedAccount
+	for _, item := range items {
+		profile, region, ok := auth.ParseRefreshTokenKey(item)
+		if !ok {
+			continue
+		}
+		accounts = append(accounts, storedAccount{profile: profile, region: region})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].profile != accounts[j].profile {
+			return accounts[i].profile < accounts[j].profile
+		}
+		return accounts[i].region < accounts[j].region
+	})
+
+	return accounts, nil
+}
+
 // AuthListCmd implements the auth list command
 type AuthListCmd struct {
 	Check bool `help:"Validate stored tokens are still valid" short:"c"`
 }
 
+// AuthAccountRow is a display struct for a stored profile credential
+type AuthAccountRow struct {
+	Profile string `json:"profile"`
+	Email   string `json:"email"`
+	Region  string `json:"region"`
+	Status  string `json:"status"`
+	Valid   string `json:"valid"` // "yes", "no", "unknown" (if not checked)
+	Expiry  string `json:"expiry"`
+}
+
 // Run executes the list command
 func (cmd *AuthListCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 	store, err := secrets.NewStore()
@@ -172,69 +268,72 @@ func (cmd *AuthListCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 		}
 	}
 
-	// List all stored refresh tokens
-	items, err := store.List()
+	accounts, err := storedAccounts(store)
 	if err != nil {
-		return &output.CLIError{
-			Message:  fmt.Sprintf("Failed to list stored credentials: %v", err),
-			ExitCode: output.ExitGeneral,
-		}
-	}
-
-	// Parse refresh token keys to extract regions
-	type accountInfo struct {
-		Region string
-		Active bool
-		Valid  string // "yes", "no", "unknown" (if not checked)
-		Expiry string
+		return err
 	}
 
-	var accounts []accountInfo
+	// Load each profile's config once to show the identity it is bound to
+	profileConfigs := make(map[string]*config.Config)
+	var rows []AuthAccountRow
 
-	for _, item := range items {
-		if strings.HasPrefix(item, "refresh_token_") {
-			region := strings.TrimPrefix(item, "refresh_token_")
-
-			account := accountInfo{
-				Region: region,
-				Active: region == cfg.Region,
-				Valid:  "unknown",
-				Expiry: "n/a",
+	for _, acct := range accounts {
+		profileCfg, ok := profileConfigs[acct.profile]
+		if !ok {
+			profileCfg, err = config.LoadProfile(acct.profile)
+			if err != nil {
+				profileCfg = &config.Config{Profile: acct.profile}
 			}
+			profileConfigs[acct.profile] = profileCfg
+		}
 
-			// Optionally check if token is valid
-			if cmd.Check {
-				tempCfg := &config.Config{
-					Region:       region,
-					ClientID:     cfg.ClientID,
-					ClientSecret: cfg.ClientSecret,
-				}
-				tokenCache, err := auth.NewTokenCache(tempCfg, store)
+		row := AuthAccountRow{
+			Profile: acct.profile,
+			Region:  acct.region,
+			Valid:   "unknown",
+			Expiry:  "n/a",
+		}
+		if profileCfg.Region == "" || profileCfg.Region == acct.region {
+			row.Email = profileCfg.Email
+		}
+		if acct.profile == cfg.Profile && acct.region == cfg.Region {
+			row.Status = "active"
+		}
+
+		// Optionally check if token is valid
+		if cmd.Check {
+			tempCfg := &config.Config{
+				Profile:      acct.profile,
+				Region:       acct.region,
+				ClientID:     profileCfg.ClientID,
+				ClientSecret: profileCfg.ClientSecret,
+			}
+			tokenCache, err := auth.NewTokenCache(tempCfg, store)
+			if err != nil {
+				row.Valid = "error"
+			} else {
+				token, err := tokenCache.Token()
 				if err != nil {
-					account.Valid = "error"
+					row.Valid = "no"
 				} else {
-					token, err := tokenCache.Token()
-					if err != nil {
-						account.Valid = "no"
-					} else {
-						account.Valid = "yes"
-						account.Expiry = token.Expiry.Format(time.RFC3339)
-					}
+					row.Valid = "yes"
+					row.Expiry = token.Expiry.Format(time.RFC3339)
 				}
 			}
-
-			accounts = append(accounts, account)
 		}
+
+		rows = append(rows, row)
 	}
 
-	if len(accounts) == 0 {
+	if len(rows) == 0 {
 		fmt.Fprintf(os.Stderr, "No stored accounts found\n")
 		fmt.Fprintf(os.Stderr, "Run 'zoh auth login' to authenticate\n")
 		return nil
 	}
 
-	// Output as list using PrintList
 	cols := []output.Column{
+		{Name: "Profile", Key: "Profile"},
+		{Name: "Email", Key: "Email"},
 		{Name: "Region", Key: "Region"},
 		{Name: "Status", Key: "Status"},
 	}
@@ -245,6 +344,40 @@ func (cmd *AuthListCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 		)
 	}
 
-	fp.Formatter.PrintList(accounts, cols)
+	return fp.Formatter.PrintList(rows, cols)
+}
+
+// AuthSwitchCmd implements the auth switch command
+type AuthSwitchCmd struct {
+	Profile string `arg:"" help:"Profile name to make active"`
+}
+
+// Run executes the switch command
+func (cmd *AuthSwitchCmd) Run(cfg *config.Config) error {
+	if err := config.ValidateProfileName(cmd.Profile); err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	if cmd.Profile != config.DefaultProfile && !config.ProfileExists(cmd.Profile) {
+		profiles, _ := config.ListProfiles()
+		return &output.CLIError{
+			Message: fmt.Sprintf("Profile not found: %s (available: %s)\n\n"+
+				"Create it with: zoh --profile %s config set client_id YOUR_CLIENT_ID",
+				cmd.Profile, strings.Join(profiles, ", "), cmd.Profile),
+			ExitCode: output.ExitNotFound,
+		}
+	}
+
+	if err := config.SetActiveProfile(cmd.Profile); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to switch profile: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Switched to profile %s\n", cmd.Profile)
 	return nil
 }
@@ -46,8 +46,14 @@ func (c *CLI) AfterApply(ctx *kong.Context) error {
 		return fmt.Errorf("--results-only requires --output=json")
 	}
 
-	// Load config from XDG path (returns defaults if missing)
-	cfg, err := config.Load()
+	// Resolve profile: CLI flag > active profile > "default"
+	profile := c.Profile
+	if profile == "" {
+		profile = config.ActiveProfile()
+	}
+
+	// Load profile config from XDG path (returns defaults if missing)
+	cfg, err := config.LoadProfile(profile)
 	if err != nil {
 		return err
 	}
@@ -62,6 +68,13 @@ func (c *CLI) AfterApply(ctx *kong.Context) error {
 	}
 	cfg.Region = region
 
+	// Endpoint, token and cassette overrides (used for offline testing and bug reports)
+	cfg.APIBase = c.APIBase
+	cfg.AccessToken = c.AccessToken
+	cfg.Cassette = c.Cassette
+	cfg.CassetteMode = c.CassetteMode
+	cfg.TransferTimeout = c.TransferTimeout
+
 	// Create output formatter
 	var formatter *FormatterProvider
 	outputMode := c.ResolvedOutput()
@@ -90,6 +103,7 @@ type AuthCmd struct {
 	Login  AuthLoginCmd  `cmd:"" help:"Log in to Zoho account"`
 	Logout AuthLogoutCmd `cmd:"" help:"Log out and remove stored credentials"`
 	List   AuthListCmd   `cmd:"" help:"List stored accounts"`
+	Switch AuthSwitchCmd `cmd:"" help:"Switch the active profile"`
 }
 
 // ConfigCmd holds configuration subcommands
@@ -118,6 +132,13 @@ type AdminUsersCmd struct {
 	Activate   AdminUsersActivateCmd   `cmd:"" help:"Activate a user account"`
 	Deactivate AdminUsersDeactivateCmd `cmd:"" help:"Deactivate a user account"`
 	Delete     AdminUsersDeleteCmd     `cmd:"" help:"Delete a user permanently"`
+	Vacation   AdminUsersVacationCmd   `cmd:"" help:"Manage a user's vacation auto-reply"`
+}
+
+// AdminUsersVacationCmd holds subcommands for managing a user's vacation auto-reply
+type AdminUsersVacationCmd struct {
+	Set     AdminUsersVacationSetCmd     `cmd:"" help:"Enable vacation auto-reply for a user"`
+	Disable AdminUsersVacationDisableCmd `cmd:"" help:"Disable a user's vacation auto-reply"`
 }
 
 // AdminGroupsCmd holds group subcommands
@@ -160,19 +181,38 @@ type MailCmd struct {
 	Labels      MailLabelsCmd      `cmd:"" help:"Manage mail labels"`
 	Messages    MailMessagesCmd    `cmd:"" help:"Manage messages"`
 	Attachments MailAttachmentsCmd `cmd:"" help:"Manage attachments"`
+	Search      MailSearchCmd      `cmd:"" help:"Manage saved searches, usable as @name in place of a folder"`
+	Drafts      MailDraftsCmd      `cmd:"" help:"Manage draft messages"`
+	Scheduled   MailScheduledCmd   `cmd:"" help:"Manage scheduled messages"`
 	Send        MailSendCmd        `cmd:"" help:"Send email messages"`
+	Export      MailExportCmd      `cmd:"" help:"Export a folder's messages to mbox, Maildir or .eml files"`
+	Import      MailImportCmd      `cmd:"" help:"Import messages from mbox, Maildir or .eml files into a folder"`
+	Sync        MailSyncCmd        `cmd:"" help:"Keep a local mirror of every folder up to date"`
+	Index       MailIndexCmd       `cmd:"" help:"Offline full-text search index"`
+	Watch       MailWatchCmd       `cmd:"" help:"Watch a folder and emit new messages as they arrive"`
+	Rules       MailRulesCmd       `cmd:"" help:"Apply client-side rules from a file to a folder's messages"`
 	Settings    MailSettingsCmd    `cmd:"" help:"Manage mail settings"`
 	Admin       MailAdminCmd       `cmd:"" help:"Mail administration operations"`
 }
 
 // MailFoldersCmd holds folder subcommands
 type MailFoldersCmd struct {
-	List MailFoldersListCmd `cmd:"" help:"List all folders"`
+	List        MailFoldersListCmd        `cmd:"" help:"List all folders"`
+	Create      MailFoldersCreateCmd      `cmd:"" help:"Create a folder, including missing parents"`
+	Rename      MailFoldersRenameCmd      `cmd:"" help:"Rename a folder"`
+	Move        MailFoldersMoveCmd        `cmd:"" help:"Move a folder under another folder"`
+	Empty       MailFoldersEmptyCmd       `cmd:"" help:"Permanently delete every message in a folder"`
+	Delete      MailFoldersDeleteCmd      `cmd:"" help:"Delete a folder with its subfolders and messages"`
+	MarkAllRead MailFoldersMarkAllReadCmd `cmd:"mark-all-read" help:"Mark every message in a folder as read"`
 }
 
 // MailLabelsCmd holds label subcommands
 type MailLabelsCmd struct {
-	List MailLabelsListCmd `cmd:"" help:"List all labels"`
+	List    MailLabelsListCmd    `cmd:"" help:"List all labels"`
+	Create  MailLabelsCreateCmd  `cmd:"" help:"Create a label"`
+	Rename  MailLabelsRenameCmd  `cmd:"" help:"Rename a label"`
+	Recolor MailLabelsRecolorCmd `cmd:"" help:"Change the color of a label"`
+	Delete  MailLabelsDeleteCmd  `cmd:"" help:"Delete a label (messages are kept)"`
 }
 
 // MailMessagesCmd holds message subcommands
@@ -181,12 +221,38 @@ type MailMessagesCmd struct {
 	Get    MailMessagesGetCmd    `cmd:"" help:"Get full message details"`
 	Search MailMessagesSearchCmd `cmd:"" help:"Search messages with query filters"`
 	Thread MailMessagesThreadCmd `cmd:"" help:"View all messages in a thread"`
+
+	MarkRead   MailMessagesMarkReadCmd   `cmd:"mark-read" help:"Mark messages as read"`
+	MarkUnread MailMessagesMarkUnreadCmd `cmd:"mark-unread" help:"Mark messages as unread"`
+	Flag       MailMessagesFlagCmd       `cmd:"" help:"Flag messages (or clear the flag)"`
+	Move       MailMessagesMoveCmd       `cmd:"" help:"Move messages to another folder"`
+	Archive    MailMessagesArchiveCmd    `cmd:"" help:"Archive messages"`
+	Delete     MailMessagesDeleteCmd     `cmd:"" help:"Move messages to Trash or delete them permanently"`
+	Spam       MailMessagesSpamCmd       `cmd:"" help:"Mark messages as spam"`
+	NotSpam    MailMessagesNotSpamCmd    `cmd:"not-spam" help:"Mark messages as not spam"`
+	Label      MailMessagesLabelCmd      `cmd:"" help:"Add or remove labels on messages"`
+	Apply      MailMessagesApplyCmd      `cmd:"" help:"Apply an action to every message matching a search"`
+}
+
+// MailMessagesLabelCmd holds message label subcommands
+type MailMessagesLabelCmd struct {
+	Add    MailMessagesLabelAddCmd    `cmd:"" help:"Apply a label to messages"`
+	Remove MailMessagesLabelRemoveCmd `cmd:"" help:"Remove a label from messages"`
+}
+
+// MailSearchCmd holds saved search subcommands
+type MailSearchCmd struct {
+	Save   MailSearchSaveCmd   `cmd:"" help:"Save a search under a name"`
+	List   MailSearchListCmd   `cmd:"" help:"List saved searches"`
+	Run    MailSearchRunCmd    `cmd:"" help:"Run a saved search"`
+	Delete MailSearchDeleteCmd `cmd:"" help:"Delete saved searches"`
 }
 
 // MailAttachmentsCmd holds attachment subcommands
 type MailAttachmentsCmd struct {
-	List     MailAttachmentsListCmd     `cmd:"" help:"List attachments for a message"`
-	Download MailAttachmentsDownloadCmd `cmd:"" help:"Download an attachment"`
+	List        MailAttachmentsListCmd        `cmd:"" help:"List attachments for a message"`
+	Download    MailAttachmentsDownloadCmd    `cmd:"" help:"Download an attachment"`
+	DownloadAll MailAttachmentsDownloadAllCmd `cmd:"" help:"Download the attachments of several messages, skipping duplicates"`
 }
 
 // MailSendCmd holds send subcommands
@@ -194,6 +260,30 @@ type MailSendCmd struct {
 	Compose MailSendComposeCmd `cmd:"" help:"Compose and send a new email"`
 	Reply   MailSendReplyCmd   `cmd:"" help:"Reply to a message"`
 	Forward MailSendForwardCmd `cmd:"" help:"Forward a message"`
+	Raw     MailSendRawCmd     `cmd:"" help:"Send an RFC 5322 message (.eml file or stdin)"`
+	Merge   MailSendMergeCmd   `cmd:"" help:"Send one templated message per CSV row"`
+}
+
+// MailDraftsCmd holds draft subcommands
+type MailDraftsCmd struct {
+	List   MailDraftsListCmd   `cmd:"" help:"List saved drafts"`
+	Get    MailDraftsGetCmd    `cmd:"" help:"Show a draft"`
+	Create MailDraftsCreateCmd `cmd:"" help:"Save a new draft"`
+	Update MailDraftsUpdateCmd `cmd:"" help:"Edit a saved draft"`
+	Send   MailDraftsSendCmd   `cmd:"" help:"Send a saved draft"`
+	Delete MailDraftsDeleteCmd `cmd:"" help:"Delete a saved draft"`
+}
+
+// MailScheduledCmd holds scheduled-send subcommands
+type MailScheduledCmd struct {
+	List   MailScheduledListCmd   `cmd:"" help:"List messages scheduled to be sent"`
+	Cancel MailScheduledCancelCmd `cmd:"" help:"Cancel scheduled messages (they are kept as drafts)"`
+}
+
+// MailRulesCmd holds client-side rules subcommands
+type MailRulesCmd struct {
+	Run   MailRulesRunCmd   `cmd:"" help:"Apply the rules once, or keep applying them with --watch"`
+	Check MailRulesCheckCmd `cmd:"" help:"Validate a rules file and list its rules"`
 }
 
 // MailSettingsCmd holds settings subcommands
@@ -202,6 +292,19 @@ type MailSettingsCmd struct {
 	Vacation    MailSettingsVacationCmd    `cmd:"" help:"Manage vacation auto-reply"`
 	DisplayName MailSettingsDisplayNameCmd `cmd:"display-name" help:"Manage account display name"`
 	Forwarding  MailSettingsForwardingCmd  `cmd:"" help:"View forwarding settings"`
+	Filters     MailSettingsFiltersCmd     `cmd:"" help:"Manage incoming mail filters"`
+}
+
+// MailSettingsFiltersCmd holds incoming mail filter subcommands
+type MailSettingsFiltersCmd struct {
+	List    MailSettingsFiltersListCmd    `cmd:"" help:"List filters in the order they run"`
+	Get     MailSettingsFiltersGetCmd     `cmd:"" help:"Show a filter"`
+	Create  MailSettingsFiltersCreateCmd  `cmd:"" help:"Create a filter, run after the existing ones"`
+	Update  MailSettingsFiltersUpdateCmd  `cmd:"" help:"Update a filter"`
+	Delete  MailSettingsFiltersDeleteCmd  `cmd:"" help:"Delete a filter"`
+	Reorder MailSettingsFiltersReorderCmd `cmd:"" help:"Change the order filters run in"`
+	Export  MailSettingsFiltersExportCmd  `cmd:"" help:"Write the filters as a portable JSON document"`
+	Apply   MailSettingsFiltersApplyCmd   `cmd:"" help:"Make the filters match an exported document, previewing the changes"`
 }
 
 // MailSettingsSignaturesCmd holds signature subcommands
@@ -0,0 +1,1417 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+// newFakeEnv starts a fake Zoho server and points the CLI at it with
+// isolated XDG directories, so tests never touch real config or credentials
+func newFakeEnv(t *testing.T) *zohotest.Server {
+	t.Helper()
+
+	fake := zohotest.New()
+	fake.AccessToken = "test-token"
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("ZOH_API_BASE", srv.URL)
+	t.Setenv("ZOH_ACCESS_TOKEN", "test-token")
+	isolateXDG(t)
+
+	// The fake server has no rate limits
+	orig := zoho.ClientRateLimiter
+	zoho.ClientRateLimiter = func() *rate.Limiter { return rate.NewLimiter(rate.Inf, 0) }
+	t.Cleanup(func() { zoho.ClientRateLimiter = orig })
+
+	return fake
+}
+
+// runCLI parses and runs a zoh command line, returning what it wrote to stdout
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	parser, err := kong.New(&CLI{}, kong.Name("zoh"), kong.Vars{"version": "test"}, kong.Exit(func(int) {}))
+	require.NoError(t, err)
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	ctx, err := parser.Parse(args)
+	if err == nil {
+		err = ctx.Run()
+	}
+
+	w.Close()
+	return <-done, err
+}
+
+func TestCLIMessagesListJSON(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+
+	out, err := runCLI(t, "mail", "messages", "list", "--output", "json", "--results-only")
+	require.NoError(t, err)
+
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Re: Welcome to Zoho Mail", rows[0]["Subject"])
+}
+
+func TestCLISendCompose(t *testing.T) {
+	fake := newFakeEnv(t)
+
+	_, err := runCLI(t, "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Hi", "--body", "Hello")
+	require.NoError(t, err)
+
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "bob@example.com", sent[0].ToAddress)
+	assert.Equal(t, "plaintext", sent[0].MailFormat)
+
+	// --text sends a plain-text alternative of the HTML body
+	_, err = runCLI(t, "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Hi", "--html",
+		"--body", "<p><b>Hello</b></p>", "--text", "Hello")
+	require.NoError(t, err)
+	sent = fake.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "html", sent[1].MailFormat)
+	assert.Equal(t, "<p><b>Hello</b></p>", sent[1].Content)
+	assert.Equal(t, "Hello", sent[1].TextContent)
+
+	_, err = runCLI(t, "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Hi", "--body", "Hello", "--text", "Hello")
+	assert.ErrorContains(t, err, "--text requires --html")
+}
+
+func TestCLIDryRunMakesNoRequests(t *testing.T) {
+	fake := newFakeEnv(t)
+
+	_, err := runCLI(t, "--dry-run", "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Hi", "--body", "Hello")
+	require.NoError(t, err)
+	assert.Empty(t, fake.Requests())
+}
+
+func TestCLIAdminUsersList(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+
+	out, err := runCLI(t, "admin", "users", "list", "--output", "plain")
+	require.NoError(t, err)
+	assert.Contains(t, out, zohotest.Email)
+	assert.Contains(t, out, "alice@example.com")
+}
+
+func TestReadMessageIDs(t *testing.T) {
+	ids, err := readMessageIDs([]string{"1", "2", "1"}, strings.NewReader("ignored"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids)
+
+	ids, err = readMessageIDs([]string{"-"}, strings.NewReader("3\n4 5\n\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "4", "5"}, ids)
+
+	_, err = readMessageIDs(nil, strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestCLIMessagesMoveAndMarkRead(t *testing.T) {
+	fake := newFakeEnv(t)
+	a := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+	b := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "b"}})
+	projects := fake.AddFolder("Projects")
+
+	_, err := runCLI(t, "mail", "messages", "move", a, b, "--to", "Projects")
+	require.NoError(t, err)
+	assert.Equal(t, projects, fake.Message(a).FolderID)
+	assert.Equal(t, projects, fake.Message(b).FolderID)
+
+	_, err = runCLI(t, "mail", "messages", "mark-read", a)
+	require.NoError(t, err)
+	assert.Equal(t, "1", fake.Message(a).Status)
+	assert.Equal(t, "0", fake.Message(b).Status)
+}
+
+func TestCLIMessagesDeletePermanentNeedsConfirm(t *testing.T) {
+	fake := newFakeEnv(t)
+	id := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+
+	_, err := runCLI(t, "mail", "messages", "delete", id, "--permanent")
+	require.Error(t, err)
+	assert.NotNil(t, fake.Message(id))
+
+	_, err = runCLI(t, "--dry-run", "mail", "messages", "delete", id, "--permanent")
+	require.NoError(t, err)
+	assert.NotNil(t, fake.Message(id))
+
+	_, err = runCLI(t, "--force", "mail", "messages", "delete", id, "--permanent")
+	require.NoError(t, err)
+	assert.Nil(t, fake.Message(id))
+}
+
+func TestCLIMessagesApply(t *testing.T) {
+	fake := newFakeEnv(t)
+	var alerts []string
+	for i := 0; i < 3; i++ {
+		alerts = append(alerts, fake.AddMessage(zohotest.Message{
+			MessageMetadata: zoho.MessageMetadata{Subject: "Alert", FromAddress: "alerts@example.com"},
+		}))
+	}
+	keep := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Hello", FromAddress: "bob@example.com"},
+	})
+	archive := fake.AddFolder("Alerts")
+
+	// Preview and unconfirmed runs change nothing
+	_, err := runCLI(t, "--dry-run", "mail", "messages", "apply", "move", "--to", "Alerts", "--from", "alerts@")
+	require.NoError(t, err)
+	_, err = runCLI(t, "mail", "messages", "apply", "move", "--to", "Alerts", "--from", "alerts@")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "move 3 message(s)")
+	assert.Equal(t, fake.FolderID("Inbox"), fake.Message(alerts[0]).FolderID)
+
+	out, err := runCLI(t, "--force", "-o", "json", "--results-only", "mail", "messages", "apply", "move", "--to", "Alerts", "--from", "alerts@")
+	require.NoError(t, err)
+
+	var rows []ApplyResultRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.Equal(t, "ok", row.Result)
+	}
+	for _, id := range alerts {
+		assert.Equal(t, archive, fake.Message(id).FolderID)
+	}
+	assert.Equal(t, fake.FolderID("Inbox"), fake.Message(keep).FolderID)
+}
+
+func TestCLIMessagesApplyRequiresFilter(t *testing.T) {
+	newFakeEnv(t)
+
+	_, err := runCLI(t, "--force", "mail", "messages", "apply", "mark-read")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search criterion")
+}
+
+func TestCLIFoldersCreateNested(t *testing.T) {
+	fake := newFakeEnv(t)
+
+	out, err := runCLI(t, "-o", "json", "mail", "folders", "create", "Projects/2025/Q1")
+	require.NoError(t, err)
+
+	var folder zoho.Folder
+	require.NoError(t, json.Unmarshal([]byte(out), &folder))
+	assert.Equal(t, "/Projects/2025/Q1", folder.Path)
+	assert.NotEmpty(t, fake.FolderID("2025"))
+
+	// Creating again is a no-op that returns the existing folder
+	out, err = runCLI(t, "-o", "json", "mail", "folders", "create", "/Projects/2025/Q1")
+	require.NoError(t, err)
+	var again zoho.Folder
+	require.NoError(t, json.Unmarshal([]byte(out), &again))
+	assert.Equal(t, folder.FolderID, again.FolderID)
+
+	_, err = runCLI(t, "mail", "folders", "delete", "Projects")
+	require.Error(t, err)
+	_, err = runCLI(t, "mail", "folders", "delete", "Projects", "--confirm")
+	require.NoError(t, err)
+	assert.Empty(t, fake.FolderID("Q1"))
+}
+
+func TestCLILabelsManageAndFilter(t *testing.T) {
+	fake := newFakeEnv(t)
+	tagged := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "tagged"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "plain"}})
+
+	_, err := runCLI(t, "mail", "labels", "create", "Urgent", "--color", "red")
+	require.Error(t, err)
+	_, err = runCLI(t, "mail", "labels", "create", "Urgent", "--color", "#FF0000")
+	require.NoError(t, err)
+	labelID := fake.LabelID("Urgent")
+	require.NotEmpty(t, labelID)
+
+	_, err = runCLI(t, "mail", "messages", "label", "add", "Urgent", tagged)
+	require.NoError(t, err)
+	assert.Equal(t, []string{labelID}, fake.Message(tagged).LabelIDs)
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "messages", "list", "--label", "Urgent")
+	require.NoError(t, err)
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "tagged", rows[0]["Subject"])
+
+	_, err = runCLI(t, "mail", "labels", "rename", "Urgent", "Critical")
+	require.NoError(t, err)
+	assert.Equal(t, labelID, fake.LabelID("Critical"))
+
+	_, err = runCLI(t, "mail", "labels", "delete", "Critical")
+	require.Error(t, err)
+	_, err = runCLI(t, "mail", "labels", "delete", "Critical", "--confirm")
+	require.NoError(t, err)
+	assert.Empty(t, fake.Message(tagged).LabelIDs)
+}
+
+func TestCLIDrafts(t *testing.T) {
+	fake := newFakeEnv(t)
+
+	notes := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(notes, []byte("draft notes"), 0o600))
+
+	out, err := runCLI(t, "-o", "json", "mail", "send", "compose", "--draft", "--to", "bob@example.com",
+		"--bcc", "audit@example.com", "--subject", "Review me", "--body", "Hello", "--attach", notes)
+	require.NoError(t, err)
+	assert.Empty(t, fake.Sent())
+
+	var saved DraftResult
+	require.NoError(t, json.Unmarshal([]byte(out), &saved))
+	require.NotEmpty(t, saved.DraftID)
+
+	// Updating only the subject keeps the Bcc recipients and attachments
+	_, err = runCLI(t, "mail", "drafts", "update", saved.DraftID, "--subject", "Reviewed")
+	require.NoError(t, err)
+	draft := fake.Drafts()[saved.DraftID]
+	assert.Equal(t, "Reviewed", draft.Subject)
+	assert.Equal(t, "bob@example.com", draft.ToAddress)
+	assert.Equal(t, "audit@example.com", draft.BccAddress)
+	assert.Equal(t, "Hello", draft.Content)
+	atts := fake.Message(saved.DraftID).Attachments
+	require.Len(t, atts, 1)
+	assert.Equal(t, "notes.txt", atts[0].AttachmentName)
+	assert.Equal(t, "draft notes", string(atts[0].Data))
+
+	out, err = runCLI(t, "-o", "plain", "mail", "drafts", "list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Reviewed")
+
+	_, err = runCLI(t, "mail", "drafts", "send", saved.DraftID)
+	require.NoError(t, err)
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "Reviewed", sent[0].Subject)
+	assert.Empty(t, fake.Drafts())
+}
+
+func TestCLIScheduledSend(t *testing.T) {
+	fake := newFakeEnv(t)
+	sendAt := time.Now().Add(48 * time.Hour).Format("2006-01-02 15:04")
+
+	_, err := runCLI(t, "mail", "send", "compose", "--to", "team@example.com", "--subject", "Release notes",
+		"--body", "v2.1", "--send-at", sendAt, "--timezone", "Europe/Berlin")
+	require.NoError(t, err)
+	assert.Empty(t, fake.Sent())
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "scheduled", "list")
+	require.NoError(t, err)
+	var rows []ScheduledListRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Release notes", rows[0].Subject)
+
+	_, err = runCLI(t, "mail", "scheduled", "cancel", rows[0].MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, fake.FolderID("Drafts"), fake.Message(rows[0].MessageID).FolderID)
+
+	// A cancelled message can still be sent as a draft
+	_, err = runCLI(t, "mail", "drafts", "send", rows[0].MessageID)
+	require.NoError(t, err)
+	require.Len(t, fake.Sent(), 1)
+
+	_, err = runCLI(t, "mail", "send", "compose", "--to", "a@example.com", "--subject", "x", "--body", "y",
+		"--draft", "--send-at", sendAt)
+	assert.ErrorContains(t, err, "--draft")
+}
+
+func TestCLISendRaw(t *testing.T) {
+	fake := newFakeEnv(t)
+
+	eml := "To: ops@example.com\r\n" +
+		"Subject: Report\r\n" +
+		"Content-Type: multipart/related; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<img src=\"cid:logo\">\r\n" +
+		"--b\r\n" +
+		"Content-Type: image/gif\r\n" +
+		"Content-ID: <logo>\r\n" +
+		"\r\n" +
+		"GIF89a\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"Content-Disposition: attachment; filename=notes.txt\r\n" +
+		"\r\n" +
+		"notes\r\n" +
+		"--b--\r\n"
+	path := filepath.Join(t.TempDir(), "report.eml")
+	require.NoError(t, os.WriteFile(path, []byte(eml), 0o600))
+
+	_, err := runCLI(t, "mail", "send", "raw", "--file", path)
+	require.NoError(t, err)
+
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "ops@example.com", sent[0].ToAddress)
+	assert.Equal(t, "html", sent[0].MailFormat)
+	assert.NotContains(t, sent[0].Content, "cid:logo")
+	require.Len(t, sent[0].Attachments, 1)
+	assert.Equal(t, "notes.txt", sent[0].Attachments[0].AttachmentName)
+}
+
+func TestCLISendMergeResumes(t *testing.T) {
+	fake := newFakeEnv(t)
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "onboarding.tmpl")
+	csvPath := filepath.Join(dir, "hires.csv")
+	require.NoError(t, os.WriteFile(tmpl, []byte("Subject: Welcome {{.name}}\n\nHi {{.name}}, you start on {{.start}}.\n"), 0o600))
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,email,start\nAda,ada@example.com,Monday\nBob,bob-at-example,Tuesday\nCy,cy@example.com,Friday\n"), 0o600))
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "send", "merge", "--template", tmpl, "--csv", csvPath)
+	require.Error(t, err)
+	var rows []MergeResultRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"sent", "failed", "sent"}, []string{rows[0].Result, rows[1].Result, rows[2].Result})
+	require.Len(t, fake.Sent(), 2)
+	assert.Equal(t, "Hi Ada, you start on Monday.\n", fake.Sent()[0].Content)
+
+	// Fix the bad row and re-run: only that row is sent
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,email,start\nAda,ada@example.com,Monday\nBob,bob@example.com,Tuesday\nCy,cy@example.com,Friday\n"), 0o600))
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "send", "merge", "--template", tmpl, "--csv", csvPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	assert.Equal(t, []string{"skipped", "sent", "skipped"}, []string{rows[0].Result, rows[1].Result, rows[2].Result})
+	sent := fake.Sent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "bob@example.com", sent[2].ToAddress)
+	assert.Equal(t, "Welcome Bob", sent[2].Subject)
+
+	// Rows are recognized by content, so inserting one sends only the new row
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,email,start\nAda,ada@example.com,Monday\nDee,dee@example.com,Monday\nBob,bob@example.com,Tuesday\nCy,cy@example.com,Friday\n"), 0o600))
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "send", "merge", "--template", tmpl, "--csv", csvPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	assert.Equal(t, []string{"skipped", "sent", "skipped", "skipped"}, []string{rows[0].Result, rows[1].Result, rows[2].Result, rows[3].Result})
+	sent = fake.Sent()
+	require.Len(t, sent, 4)
+	assert.Equal(t, "dee@example.com", sent[3].ToAddress)
+
+	// An identical row is a message of its own, sent once per run like the rest
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,email,start\nAda,ada@example.com,Monday\nDee,dee@example.com,Monday\nBob,bob@example.com,Tuesday\nCy,cy@example.com,Friday\nDee,dee@example.com,Monday\n"), 0o600))
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "send", "merge", "--template", tmpl, "--csv", csvPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 5)
+	assert.Equal(t, "sent", rows[4].Result)
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "send", "merge", "--template", tmpl, "--csv", csvPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	assert.Equal(t, "skipped", rows[4].Result)
+	assert.Len(t, fake.Sent(), 5)
+}
+
+func TestCLIComposeTemplate(t *testing.T) {
+	fake := newFakeEnv(t)
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "note.tmpl")
+	vars := filepath.Join(dir, "vars.json")
+	require.NoError(t, os.WriteFile(tmpl, []byte("To: {{.to}}\nSubject: Build {{.version}}\n\nVersion {{.version}} is live.\n"), 0o600))
+	require.NoError(t, os.WriteFile(vars, []byte(`{"to": "team@example.com", "version": "2.1"}`), 0o600))
+
+	_, err := runCLI(t, "mail", "send", "compose", "--template", tmpl, "--vars", vars)
+	require.NoError(t, err)
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "team@example.com", sent[0].ToAddress)
+	assert.Equal(t, "Build 2.1", sent[0].Subject)
+	assert.Equal(t, "Version 2.1 is live.\n", sent[0].Content)
+
+	_, err = runCLI(t, "mail", "send", "compose", "--to", "a@example.com", "--subject", "x")
+	assert.ErrorContains(t, err, "--body")
+}
+
+func TestCLIExportResumes(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Quoting", FromAddress: "a@example.com"},
+		Raw:             []byte("From: a@example.com\r\nSubject: Quoting\r\n\r\nFrom here on\r\n>From there\r\n"),
+	})
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Second", FromAddress: "b@example.com"},
+		Content:         "<p>two</p>",
+	})
+	out := filepath.Join(t.TempDir(), "inbox")
+
+	stdout, err := runCLI(t, "-o", "json", "--results-only", "mail", "export", "--folder", "Inbox", "--format", "mbox", "--out", out)
+	require.NoError(t, err)
+	var summary ExportSummary
+	require.NoError(t, json.Unmarshal([]byte(stdout), &summary))
+	assert.Equal(t, 2, summary.Exported)
+	assert.Equal(t, 2, summary.FolderCount)
+
+	mbox, err := os.ReadFile(filepath.Join(out, "Inbox.mbox"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(mbox), "\nFrom ")+1)
+	assert.Contains(t, string(mbox), "\n>From here on\n>>From there\n")
+
+	// A re-run only fetches messages that arrived since
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Third", FromAddress: "c@example.com"},
+	})
+	stdout, err = runCLI(t, "-o", "json", "--results-only", "mail", "export", "--folder", "Inbox", "--format", "mbox", "--out", out)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(stdout), &summary))
+	assert.Equal(t, 1, summary.Exported)
+	assert.Equal(t, 2, summary.Skipped)
+
+	_, err = runCLI(t, "mail", "export", "--folder", "Inbox", "--format", "eml", "--out", out)
+	assert.ErrorContains(t, err, "--restart")
+}
+
+func TestCLIExportFolderChangesMidRun(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	// A message arrives while the first one is being fetched
+	var once sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/originalmessage") {
+			once.Do(func() {
+				fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Late arrival"}})
+			})
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("ZOH_API_BASE", srv.URL)
+	out := t.TempDir()
+
+	stdout, err := runCLI(t, "-o", "json", "--results-only", "mail", "export", "--folder", "Inbox", "--format", "eml", "--out", out)
+	assert.ErrorContains(t, err, "Inbox reports 4 messages but 3 were listed")
+	var summary ExportSummary
+	require.NoError(t, json.Unmarshal([]byte(stdout), &summary))
+	assert.Equal(t, 3, summary.Exported)
+	assert.Equal(t, 4, summary.FolderCount)
+	assert.NotEmpty(t, summary.Warning)
+
+	// A re-run exports the newcomer and finds the counts agree
+	stdout, err = runCLI(t, "-o", "json", "--results-only", "mail", "export", "--folder", "Inbox", "--format", "eml", "--out", out)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(stdout), &summary))
+	assert.Equal(t, 1, summary.Exported)
+	assert.Empty(t, summary.Warning)
+}
+
+func TestCLIExportMaildir(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	out := t.TempDir()
+
+	_, err := runCLI(t, "mail", "export", "--folder", "Inbox", "--format", "maildir", "--out", out)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(out, "cur"))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	seen := 0
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ":2,S") {
+			seen++
+		}
+	}
+	assert.Equal(t, 1, seen)
+}
+
+func TestCLIImportMboxResumes(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.AddFolder("Imported")
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "archive.mbox")
+	require.NoError(t, os.WriteFile(archive, []byte(
+		"From a@example.com Mon Jan  7 10:00:00 2019\n"+
+			"From: a@example.com\nTo: me@example.com\nSubject: Old news\nDate: Mon, 07 Jan 2019 10:00:00 +0000\nMessage-ID: <1@example.com>\nStatus: RO\n\n"+
+			">From the archive\n\n"+
+			"From b@example.com Tue Jan  8 10:00:00 2019\n"+
+			"From: b@example.com\nTo: me@example.com\nSubject: Unread\nDate: Tue, 08 Jan 2019 10:00:00 +0000\nMessage-ID: <2@example.com>\n\nhello\n"), 0o600))
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "import", "--file", archive, "--folder", "Imported")
+	require.NoError(t, err)
+	var summary ImportSummary
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.Equal(t, 2, summary.Imported)
+
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "messages", "list", "--folder", "Imported")
+	require.NoError(t, err)
+	var rows []MessageListRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Unread", rows[0].Subject)
+	assert.Equal(t, "0", rows[0].Status)
+	assert.Equal(t, "1", rows[1].Status)
+	assert.Equal(t, "2019-01-07", formatReceivedTime(fake.Message(rows[1].MessageID).ReceivedTime)[:10])
+	assert.Contains(t, string(fake.Message(rows[1].MessageID).Raw), "\nFrom the archive")
+
+	// A re-run skips both messages by Message-ID
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "import", "--file", archive, "--folder", "Imported")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.Equal(t, 0, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+
+	_, err = runCLI(t, "mail", "import", "--file", archive, "--folder", "Missing")
+	assert.ErrorContains(t, err, "folders create")
+}
+
+func TestCLIImportMaildirRoundTrip(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	fake.AddFolder("Restored")
+	dir := t.TempDir()
+
+	_, err := runCLI(t, "mail", "export", "--folder", "Inbox", "--format", "maildir", "--out", dir)
+	require.NoError(t, err)
+	_, err = runCLI(t, "mail", "import", "--format", "maildir", "--file", dir, "--folder", "Restored", "--state", filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "folders", "list")
+	require.NoError(t, err)
+	var folders []zoho.Folder
+	require.NoError(t, json.Unmarshal([]byte(out), &folders))
+	for _, f := range folders {
+		if f.FolderName == "Restored" {
+			assert.Equal(t, 3, f.MessageCount)
+			assert.Equal(t, 2, f.UnreadCount)
+		}
+	}
+}
+
+func TestCLISyncTracksMovesAndDeletions(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	out := t.TempDir()
+
+	sync := func(args ...string) SyncSummary {
+		t.Helper()
+		stdout, err := runCLI(t, append([]string{"-o", "json", "--results-only", "mail", "sync", "--out", out}, args...)...)
+		require.NoError(t, err)
+		var summary SyncSummary
+		require.NoError(t, json.Unmarshal([]byte(stdout), &summary))
+		return summary
+	}
+
+	summary := sync()
+	assert.Equal(t, 4, summary.New)
+	assert.Equal(t, 4, summary.Total)
+	assert.FileExists(t, filepath.Join(out, "Projects", fake.FolderMessages("Projects")[0]+".eml"))
+
+	// Nothing changed: nothing is downloaded again
+	summary = sync()
+	assert.Equal(t, 0, summary.New)
+
+	inbox := fake.FolderMessages("Inbox")
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "New", FromAddress: "n@example.com"}})
+	_, err := runCLI(t, "mail", "messages", "move", inbox[0], "--to", "Projects")
+	require.NoError(t, err)
+	_, err = runCLI(t, "mail", "messages", "delete", inbox[1], "--folder", "Inbox", "--permanent", "--confirm")
+	require.NoError(t, err)
+
+	summary = sync()
+	assert.Equal(t, 1, summary.New)
+	assert.Equal(t, 1, summary.Moved)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.Equal(t, 4, summary.Total)
+	assert.FileExists(t, filepath.Join(out, "Projects", inbox[0]+".eml"))
+	assert.FileExists(t, filepath.Join(out, "Inbox", inbox[1]+".eml"), "deleted messages are kept without --prune")
+
+	summary = sync("--prune")
+	assert.Equal(t, 0, summary.Deleted)
+	assert.NoFileExists(t, filepath.Join(out, "Inbox", inbox[1]+".eml"))
+
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Newer", FromAddress: "n@example.com"}})
+	summary = sync("--quick")
+	assert.Equal(t, 1, summary.New)
+}
+
+func TestCLIIndexBuildAndSearch(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+
+	_, err := runCLI(t, "mail", "index", "search", "report")
+	assert.ErrorContains(t, err, "index build")
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "index", "build", "--folder", "Inbox")
+	require.NoError(t, err)
+	var summary IndexBuildSummary
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.Equal(t, 3, summary.Added)
+
+	// Searching makes no requests
+	before := len(fake.Requests())
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "index", "search", "from:alice has:attachment report.csv")
+	require.NoError(t, err)
+	var rows []IndexResultRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Q3 report", rows[0].Subject)
+	assert.Equal(t, before, len(fake.Requests()))
+
+	// A second build only fetches what is new
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Tacos", FromAddress: "bob@example.com"}, Content: "<p>noon</p>"})
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "index", "build", "--folder", "Inbox")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.Equal(t, 1, summary.Added)
+	assert.Equal(t, 4, summary.Total)
+
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "index", "search", "noon", "--folder", "Inbox")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+
+	// The query language is the one mail messages search uses
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "index", "search", "(noon OR report.csv) -from:bob in:Inbox")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Q3 report", rows[0].Subject)
+
+	_, err = runCLI(t, "mail", "index", "search", "from:alice OR")
+	assert.ErrorContains(t, err, "at column 12")
+	_, err = runCLI(t, "mail", "index", "search", "larger:1M")
+	assert.ErrorContains(t, err, "cannot be searched in the local index")
+}
+
+func TestCLIIndexFromMirror(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	out := t.TempDir()
+
+	_, err := runCLI(t, "mail", "sync", "--out", out)
+	require.NoError(t, err)
+	_, err = runCLI(t, "mail", "index", "build", "--mirror", out)
+	require.NoError(t, err)
+
+	stdout, err := runCLI(t, "-o", "json", "--results-only", "mail", "index", "search", "dkim")
+	require.NoError(t, err)
+	var rows []IndexResultRow
+	require.NoError(t, json.Unmarshal([]byte(stdout), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "/Projects", rows[0].Folder)
+}
+
+func TestCLIWatchEmitsNewMessagesOnce(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.LoadSampleData()
+	hookLog := filepath.Join(t.TempDir(), "hook.log")
+	watch := []string{"-o", "json", "mail", "watch", "--once", "--search", "from:pager -subject:test",
+		"--exec", `echo "$ZOH_SUBJECT" >> ` + hookLog}
+
+	// The first run only records where the folder is
+	out, err := runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "DB down", FromAddress: "pager@example.com"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Pager test", FromAddress: "pager@example.com"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Lunch?", FromAddress: "bob@example.com"}})
+
+	out, err = runCLI(t, watch...)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 1)
+	var event WatchEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, "DB down", event.Subject)
+	assert.Equal(t, "/Inbox", event.Folder)
+
+	// The persisted cursor keeps a restart from emitting it again
+	out, err = runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	hooked, err := os.ReadFile(hookLog)
+	require.NoError(t, err)
+	assert.Equal(t, "DB down\n", string(hooked))
+}
+
+func TestCLIWatchSearchByRecipient(t *testing.T) {
+	fake := newFakeEnv(t)
+	watch := []string{"mail", "watch", "--once", "--search", "to:oncall@example.com"}
+
+	_, err := runCLI(t, watch...)
+	require.NoError(t, err)
+
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Disk full", FromAddress: "pager@example.com", ToAddress: "oncall@example.com"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "CPU high", FromAddress: "pager@example.com", ToAddress: "ops@example.com", CcAddress: "oncall@example.com"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Lunch?", FromAddress: "bob@example.com", ToAddress: "team@example.com"}})
+
+	out, err := runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Disk full")
+	assert.Contains(t, out, "CPU high")
+	assert.NotContains(t, out, "Lunch?")
+}
+
+func TestCLIThreadFull(t *testing.T) {
+	fake := newFakeEnv(t)
+	first := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Lunch?", FromAddress: "bob@example.com", ReceivedTime: "1735722000000"},
+		Content:         "<p>Tacos at noon?</p>",
+	})
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Re: Lunch?", FromAddress: "ann@example.com", ThreadID: first, ReceivedTime: "1735725600000"},
+		Content:         "<p>Sure!</p><blockquote><p>Tacos at noon?</p></blockquote>",
+	})
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "messages", "thread", first, "--full")
+	require.NoError(t, err)
+	var entries []ConversationEntry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Tacos at noon?", entries[0].Body)
+	assert.Equal(t, "ann@example.com", entries[1].From)
+	assert.Equal(t, "Sure!", entries[1].Body)
+
+	out, err = runCLI(t, "-o", "plain", "mail", "messages", "thread", first, "--full")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Lunch? (2 messages)\n")
+	assert.Contains(t, out, "[2/2] ann@example.com")
+	assert.Less(t, strings.Index(out, "Tacos"), strings.Index(out, "Sure!"))
+	assert.Equal(t, 1, strings.Count(out, "Tacos"))
+}
+
+func TestCLIMessageGetBodyFormat(t *testing.T) {
+	fake := newFakeEnv(t)
+	id := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Status"},
+		Content:         `<style>p{color:red}</style><p>All <b>green</b> &mdash; see <a href="https://status.example.com">status</a></p>`,
+	})
+
+	get := func(args ...string) MessageDetail {
+		out, err := runCLI(t, append([]string{"-o", "json", "mail", "messages", "get", id, "--folder", "Inbox"}, args...)...)
+		require.NoError(t, err)
+		var detail MessageDetail
+		require.NoError(t, json.Unmarshal([]byte(out), &detail))
+		return detail
+	}
+
+	assert.Contains(t, get().Body, "<style>")
+	assert.Equal(t, "All green — see status[1]\n\n[1] https://status.example.com", get("--body-format", "text").Body)
+	assert.Equal(t, "All **green** — see [status](https://status.example.com)", get("--body-format", "markdown").Body)
+}
+
+func TestCLIAttachmentsDownloadAll(t *testing.T) {
+	fake := newFakeEnv(t)
+	invoice := []byte("%PDF-1.4 invoice")
+	pdf := func(name string, data []byte) zohotest.StoredAttachment {
+		return zohotest.StoredAttachment{Attachment: zoho.Attachment{AttachmentName: name, AttachmentType: "application/pdf", AttachmentSize: int64(len(data))}, Data: data}
+	}
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "March invoice", FromAddress: "billing@acme.example", ReceivedTime: "1740787200000", HasAttachment: "1"},
+		Attachments:     []zohotest.StoredAttachment{pdf("../invoice.pdf", invoice), {Attachment: zoho.Attachment{AttachmentName: "logo.png", AttachmentType: "image/png"}, Data: []byte("png")}},
+	})
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Fwd: March invoice", FromAddress: "boss@acme.example", ReceivedTime: "1740873600000", HasAttachment: "1"},
+		Attachments:     []zohotest.StoredAttachment{pdf("invoice.pdf", invoice)},
+	})
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "April invoice", FromAddress: "billing@acme.example", ReceivedTime: "1743465600000", HasAttachment: "1"},
+		Attachments:     []zohotest.StoredAttachment{pdf("invoice.pdf", []byte("%PDF-1.4 april"))},
+	})
+	out := t.TempDir()
+
+	args := []string{"-o", "json", "--results-only", "mail", "attachments", "download-all",
+		"--subject", "invoice", "--ext", "pdf", "--out", out, "--name", "{date}_{name}"}
+	stdout, err := runCLI(t, args...)
+	require.NoError(t, err)
+	var rows []AttachmentDownloadRow
+	require.NoError(t, json.Unmarshal([]byte(stdout), &rows))
+	require.Len(t, rows, 3)
+
+	results := map[string]int{}
+	for _, row := range rows {
+		results[row.Result]++
+	}
+	assert.Equal(t, map[string]int{"downloaded": 2, "duplicate": 1}, results)
+
+	files, err := filepath.Glob(filepath.Join(out, "*.pdf"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	data, err := os.ReadFile(filepath.Join(out, "2025-03-01_.._invoice.pdf"))
+	require.NoError(t, err)
+	assert.Equal(t, invoice, data)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(out), "invoice.pdf"))
+
+	// A second run downloads nothing
+	before := len(fake.Requests())
+	stdout, err = runCLI(t, args...)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(stdout), &rows))
+	for _, row := range rows {
+		assert.Equal(t, "already downloaded", row.Result)
+	}
+	for _, req := range fake.Requests()[before:] {
+		assert.NotRegexp(t, `/attachments/[^/]+$`, req.Path)
+	}
+}
+
+func TestCLISendRejectsOversizedAttachment(t *testing.T) {
+	fake := newFakeEnv(t)
+	dir := t.TempDir()
+	small := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(small, []byte("notes"), 0o600))
+	big := filepath.Join(dir, "backup.tar")
+	f, err := os.Create(big)
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(zoho.MaxAttachmentSize+1))
+	require.NoError(t, f.Close())
+
+	// Rejected before anything is uploaded, even though the small file comes first
+	_, err = runCLI(t, "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Backup",
+		"--body", "attached", "--attach", small, "--attach", big)
+	assert.ErrorContains(t, err, "Zoho Mail accepts attachments up to 20 MB")
+	assert.Empty(t, fake.Requests())
+	assert.Empty(t, fake.Sent())
+
+	_, err = runCLI(t, "--dry-run", "mail", "send", "compose", "--to", "bob@example.com", "--subject", "Backup",
+		"--body", "attached", "--attach", big)
+	assert.ErrorContains(t, err, "backup.tar is 20.1 MB")
+}
+
+func TestCLISearchQueryLanguage(t *testing.T) {
+	fake := newFakeEnv(t)
+	support := fake.AddFolder("Support")
+	add := func(from, subject, folder, size string) {
+		fake.AddMessage(zohotest.Message{
+			MessageMetadata: zoho.MessageMetadata{FromAddress: from, Subject: subject, FolderID: folder, MessageSize: size},
+			Content:         "<p>" + subject + "</p>",
+		})
+	}
+	add("alice@example.com", "Outage report", support, "6000000")
+	add("alice@example.com", "Weekly digest", support, "7000000")
+	add("bob@example.com", "Disk full", support, "100")
+	add("bob@example.com", "Lunch?", "", "8000000")
+	add("carol@example.com", "Outage report", support, "9000000")
+
+	search := func(query string) []string {
+		t.Helper()
+		out, err := runCLI(t, "-o", "json", "--results-only", "mail", "messages", "search", query)
+		require.NoError(t, err)
+		var rows []MessageListRow
+		require.NoError(t, json.Unmarshal([]byte(out), &rows))
+		var subjects []string
+		for _, row := range rows {
+			subjects = append(subjects, row.FromAddress+" "+row.Subject)
+		}
+		return subjects
+	}
+
+	// OR runs one search per sender; negation, folder and size are checked client-side
+	assert.Equal(t, []string{"bob@example.com Disk full", "alice@example.com Outage report"},
+		search("from:alice OR from:bob -subject:digest in:Support"))
+	assert.Equal(t, []string{"alice@example.com Outage report"},
+		search("from:alice OR from:bob -subject:digest in:Support larger:5M"))
+
+	// Only a folder scope: the folder is listed instead of searched
+	assert.Equal(t, []string{"carol@example.com Outage report", "alice@example.com Weekly digest", "alice@example.com Outage report"},
+		search("in:Support larger:5M"))
+
+	// Without a scope, a query with nothing to search for filters the Inbox
+	assert.Equal(t, []string{"bob@example.com Lunch?"}, search("larger:5M"))
+
+	// A filter stops paging after searchScanLimit messages
+	orig := searchScanLimit
+	searchScanLimit = searchPageSize
+	t.Cleanup(func() { searchScanLimit = orig })
+	for range searchPageSize + 50 {
+		add("dave@example.com", "Filler", "", "100")
+	}
+	before := len(fake.Requests())
+	assert.Empty(t, search("larger:1G"))
+	var listed int
+	for _, req := range fake.Requests()[before:] {
+		if strings.HasSuffix(req.Path, "/messages/view") {
+			listed++
+		}
+	}
+	assert.Equal(t, 1, listed)
+
+	_, err := runCLI(t, "mail", "messages", "search", "from:alice OR")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OR needs a term on both sides at column 12\n\n  from:alice OR\n             ^")
+}
+
+func TestCLISavedSearches(t *testing.T) {
+	fake := newFakeEnv(t)
+	support := fake.AddFolder("Support")
+	var ids []string
+	for _, m := range []struct{ from, subject, folder string }{
+		{"alice@example.com", "Outage report", support},
+		{"alice@example.com", "Weekly digest", support},
+		{"bob@example.com", "Outage report", ""},
+	} {
+		ids = append(ids, fake.AddMessage(zohotest.Message{
+			MessageMetadata: zoho.MessageMetadata{FromAddress: m.from, Subject: m.subject, FolderID: m.folder},
+		}))
+	}
+
+	subjects := func(out string) []string {
+		t.Helper()
+		var rows []MessageListRow
+		require.NoError(t, json.Unmarshal([]byte(out), &rows))
+		var subjects []string
+		for _, row := range rows {
+			subjects = append(subjects, row.FromAddress+" "+row.Subject)
+		}
+		return subjects
+	}
+
+	_, err := runCLI(t, "mail", "search", "save", "outages", "outage -subject:digest", "--from", "alice")
+	require.NoError(t, err)
+
+	// Saving over an existing name needs --force
+	_, err = runCLI(t, "mail", "search", "save", "outages", "outage")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "search", "list")
+	require.NoError(t, err)
+	var saved []SavedSearchRow
+	require.NoError(t, json.Unmarshal([]byte(out), &saved))
+	require.Len(t, saved, 1)
+	assert.Equal(t, "from:alice outage -subject:digest", saved[0].Query)
+
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "search", "run", "outages")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com Outage report"}, subjects(out))
+
+	// A saved search stands in for a folder when listing
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "messages", "list", "--folder", "@outages")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com Outage report"}, subjects(out))
+
+	// ... and wherever messages are listed by folder
+	out, err = runCLI(t, "mail", "watch", "--folder", "@outages", "--once")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	ids = append(ids, fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{FromAddress: "alice@example.com", Subject: "Outage resolved", FolderID: support},
+	}))
+	out, err = runCLI(t, "mail", "watch", "--folder", "@outages", "--once")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Outage resolved")
+
+	searches := func() int {
+		n := 0
+		for _, req := range fake.Requests() {
+			if strings.HasSuffix(req.Path, "/messages/search") {
+				n++
+			}
+		}
+		return n
+	}
+	before := searches()
+	exportDir := t.TempDir()
+	_, err = runCLI(t, "mail", "export", "--folder", "@outages", "--format", "eml", "--out", exportDir)
+	require.NoError(t, err)
+	exported, err := filepath.Glob(filepath.Join(exportDir, "*.eml"))
+	require.NoError(t, err)
+	assert.Len(t, exported, 2)
+	// The export runs the search once rather than once per page
+	assert.Equal(t, 1, searches()-before)
+
+	_, err = runCLI(t, "--force", "mail", "messages", "apply", "mark-read", "-q", "in:@outages")
+	require.NoError(t, err)
+	var read []string
+	for _, id := range ids {
+		if fake.Message(id).Status == "1" {
+			read = append(read, fake.Message(id).Subject)
+		}
+	}
+	assert.Equal(t, []string{"Outage report", "Outage resolved"}, read)
+
+	// An unknown saved search is not taken for a folder ID
+	_, err = runCLI(t, "mail", "messages", "list", "--folder", "@outgaes")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Saved search not found: outgaes")
+
+	// Commands that need a real folder reject it
+	_, err = runCLI(t, "--force", "mail", "folders", "empty", "@outages")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "@outages is a saved search, not a folder")
+
+	_, err = runCLI(t, "mail", "search", "delete", "@outages")
+	require.NoError(t, err)
+	_, err = runCLI(t, "mail", "search", "run", "outages")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Saved search not found: outages")
+}
+
+func TestCLIRulesRun(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.AddFolder("Alerts")
+	fake.AddLabel("Ops", "#FF0000")
+	dir := t.TempDir()
+	hookLog := filepath.Join(dir, "hook.log")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ack.tmpl"), []byte("Subject: Got it: {{.Subject}}\n\nThanks, we are on it.\n"), 0o600))
+	rulesFile := filepath.Join(dir, "rules.json5")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`{rules: [
+		{name: "prod", query: "from:pager", match: [{field: "subject", matches: "^\\[PROD\\]"}],
+		 actions: [{label: "Ops"}, {run: "echo \"$ZOH_SUBJECT\" >> `+hookLog+`"}, {move: "Alerts"}], stop: true},
+		{name: "invoices", query: "subject:invoice", actions: [{reply: "ack.tmpl"}, {markRead: true}]},
+	]}`), 0o600))
+
+	prod := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "[PROD] DB down", FromAddress: "pager@example.com"}})
+	invoice := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Invoice 42", FromAddress: "billing@example.com"}})
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Lunch?", FromAddress: "bob@example.com"}})
+
+	results := func(out string) []string {
+		t.Helper()
+		var rows []RuleResultRow
+		require.NoError(t, json.Unmarshal([]byte(out), &rows))
+		var got []string
+		for _, row := range rows {
+			got = append(got, row.Rule+": "+row.Action+" = "+row.Result)
+		}
+		return got
+	}
+
+	// A dry run previews every action without touching anything
+	out, err := runCLI(t, "--dry-run", "-o", "json", "--results-only", "mail", "rules", "run", "--file", rulesFile)
+	require.NoError(t, err)
+	assert.Len(t, results(out), 5)
+	assert.Equal(t, fake.FolderID("Inbox"), fake.Message(prod).FolderID)
+	assert.Empty(t, fake.Sent())
+
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "rules", "run", "--file", rulesFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"prod: label Ops = ok",
+		"prod: run echo \"$ZOH_SUBJECT\" >> " + hookLog + " = ok",
+		"prod: move to Alerts = ok",
+		"invoices: reply with ack.tmpl = ok",
+		"invoices: mark read = ok",
+	}, results(out))
+
+	assert.Equal(t, fake.FolderID("Alerts"), fake.Message(prod).FolderID)
+	assert.Contains(t, fake.Message(prod).LabelIDs, fake.LabelID("Ops"))
+	assert.Equal(t, "1", fake.Message(invoice).Status)
+	require.Len(t, fake.Sent(), 1)
+	assert.Equal(t, "Got it: Invoice 42", fake.Sent()[0].Subject)
+	hooked, err := os.ReadFile(hookLog)
+	require.NoError(t, err)
+	assert.Equal(t, "[PROD] DB down\n", string(hooked))
+
+	// Running again skips the messages already processed, unless forced
+	out, err = runCLI(t, "-o", "json", "--results-only", "mail", "rules", "run", "--file", rulesFile)
+	require.NoError(t, err)
+	assert.Empty(t, results(out))
+	assert.Len(t, fake.Sent(), 1)
+
+	out, err = runCLI(t, "--force", "-o", "json", "--results-only", "mail", "rules", "run", "--file", rulesFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoices: reply with ack.tmpl = ok", "invoices: mark read = ok"}, results(out))
+	assert.Len(t, fake.Sent(), 2)
+
+	// With --watch only messages arriving after the first run are processed, once
+	watch := []string{"-o", "json", "mail", "rules", "run", "--file", rulesFile, "--watch", "--once"}
+	out, err = runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "[PROD] API errors", FromAddress: "pager@example.com"}})
+	out, err = runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 3)
+
+	out, err = runCLI(t, watch...)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Len(t, fake.FolderMessages("Alerts"), 2)
+
+	_, err = runCLI(t, "mail", "rules", "check", filepath.Join(dir, "missing.json5"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Rules file not found")
+}
+
+func TestCLIRulesRetry(t *testing.T) {
+	fake := newFakeEnv(t)
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ack.tmpl"), []byte("Subject: Got it\n\nThanks.\n"), 0o600))
+	rulesFile := filepath.Join(dir, "rules.json5")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`{rules: [
+		{name: "ack", query: "from:example.com", actions: [{reply: "ack.tmpl"}, {run: "test -f `+ready+`"}]},
+	]}`), 0o600))
+	run := []string{"-o", "json", "--results-only", "mail", "rules", "run", "--file", rulesFile, "--limit", "1"}
+
+	results := func(out string) []string {
+		t.Helper()
+		var rows []RuleResultRow
+		require.NoError(t, json.Unmarshal([]byte(out), &rows))
+		var got []string
+		for _, row := range rows {
+			got = append(got, row.Subject+": "+row.Action+" = "+row.Result)
+		}
+		return got
+	}
+
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "First", FromAddress: "alice@example.com"}})
+	out, err := runCLI(t, run...)
+	require.Error(t, err)
+	assert.Equal(t, []string{"First: reply with ack.tmpl = ok", "First: run test -f " + ready + " = failed"}, results(out))
+
+	// The next run retries only the failed action, and takes every message
+	// since the last run however many there are
+	for _, subject := range []string{"Second", "Third"} {
+		fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: subject, FromAddress: "bob@example.com"}})
+	}
+	require.NoError(t, os.WriteFile(ready, nil, 0o600))
+	out, err = runCLI(t, run...)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"First: run test -f " + ready + " = ok",
+		"Second: reply with ack.tmpl = ok",
+		"Second: run test -f " + ready + " = ok",
+		"Third: reply with ack.tmpl = ok",
+		"Third: run test -f " + ready + " = ok",
+	}, results(out))
+	assert.Len(t, fake.Sent(), 3)
+
+	out, err = runCLI(t, run...)
+	require.NoError(t, err)
+	assert.Empty(t, results(out))
+
+	// A failure is retried a limited number of times
+	require.NoError(t, os.Remove(ready))
+	fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Fourth", FromAddress: "carol@example.com"}})
+	for range ruleMaxAttempts {
+		_, err = runCLI(t, run...)
+		require.Error(t, err)
+	}
+	out, err = runCLI(t, run...)
+	require.NoError(t, err)
+	assert.Empty(t, results(out))
+	assert.Len(t, fake.Sent(), 4)
+}
+
+func TestCLIFilters(t *testing.T) {
+	fake := newFakeEnv(t)
+	alerts := fake.AddFolder("Alerts")
+	fake.AddLabel("Ops", "#FF0000")
+	fake.AddFilter(zoho.MailFilter{
+		FilterName:    "Newsletters",
+		Enabled:       true,
+		ConditionType: "any",
+		Conditions:    []zoho.FilterCondition{{Field: "sender", Comparison: "contains", Value: "news@"}},
+		Actions:       []zoho.FilterAction{{ActionType: "markAsRead"}},
+	})
+
+	_, err := runCLI(t, "mail", "settings", "filters", "create", "--name", "Pager",
+		"-c", "subject startsWith [ALERT]", "-a", "moveToFolder Alerts", "-a", "addLabel Ops")
+	require.NoError(t, err)
+	filters := fake.Filters()
+	require.Len(t, filters, 2)
+	assert.Equal(t, alerts, filters[1].Actions[0].ActionValue)
+	assert.Equal(t, fake.LabelID("Ops"), filters[1].Actions[1].ActionValue)
+
+	// Export names folders and labels and leaves out IDs
+	out, err := runCLI(t, "mail", "settings", "filters", "export")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "filterId")
+	var doc filtersFile
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	require.Len(t, doc.Filters, 2)
+	assert.Equal(t, "/Alerts", doc.Filters[1].Actions[0].ActionValue)
+	assert.Equal(t, "Ops", doc.Filters[1].Actions[1].ActionValue)
+
+	// Disable one filter and add another to run first
+	doc.Filters[0].Enabled = false
+	doc.Filters = append([]zoho.MailFilter{{
+		FilterName:    "VIP",
+		Enabled:       true,
+		ConditionType: "all",
+		Conditions:    []zoho.FilterCondition{{Field: "sender", Comparison: "is", Value: "ceo@example.com"}},
+		Actions:       []zoho.FilterAction{{ActionType: "addLabel", ActionValue: "Ops"}},
+	}}, doc.Filters...)
+	file := filepath.Join(t.TempDir(), "filters.json")
+	writeDoc := func() {
+		t.Helper()
+		data, err := json.MarshalIndent(doc, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(file, data, 0o600))
+	}
+	writeDoc()
+
+	out, err = runCLI(t, "--dry-run", "mail", "settings", "filters", "apply", file)
+	require.NoError(t, err)
+	assert.Contains(t, out, "+ create VIP\n")
+	assert.Contains(t, out, "~ update Newsletters\n")
+	assert.Contains(t, out, `-   "isEnabled": true,`)
+	assert.Contains(t, out, `+   "isEnabled": false,`)
+	assert.Contains(t, out, "= reorder VIP, Newsletters, Pager\n")
+	assert.Len(t, fake.Filters(), 2)
+
+	_, err = runCLI(t, "mail", "settings", "filters", "apply", file)
+	require.Error(t, err)
+
+	_, err = runCLI(t, "mail", "settings", "filters", "apply", "--confirm", file)
+	require.NoError(t, err)
+	filters = fake.Filters()
+	assert.Equal(t, []string{"VIP", "Newsletters", "Pager"}, filterNames(filters))
+	assert.False(t, filters[1].Enabled)
+	assert.Equal(t, fake.LabelID("Ops"), filters[0].Actions[0].ActionValue)
+
+	// Applying again changes nothing
+	out, err = runCLI(t, "mail", "settings", "filters", "apply", "--confirm", file)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	// Filters the document does not list are kept unless pruned
+	doc.Filters = doc.Filters[:2]
+	writeDoc()
+	out, err = runCLI(t, "--dry-run", "mail", "settings", "filters", "apply", file)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	out, err = runCLI(t, "--force", "mail", "settings", "filters", "apply", "--prune", file)
+	require.NoError(t, err)
+	assert.Equal(t, "- delete Pager\n", out)
+	assert.Equal(t, []string{"VIP", "Newsletters"}, filterNames(fake.Filters()))
+
+	_, err = runCLI(t, "mail", "settings", "filters", "reorder", "newsletters")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Newsletters", "VIP"}, filterNames(fake.Filters()))
+
+	_, err = runCLI(t, "mail", "settings", "filters", "update", "VIP", "--disable")
+	require.NoError(t, err)
+	assert.False(t, fake.Filters()[1].Enabled)
+
+	_, err = runCLI(t, "mail", "settings", "filters", "delete", "VIP")
+	require.Error(t, err)
+	_, err = runCLI(t, "mail", "settings", "filters", "delete", "VIP", "--confirm")
+	require.NoError(t, err)
+	assert.Len(t, fake.Filters(), 1)
+
+	// Unknown folders are reported before anything changes
+	doc.Filters[0].Actions = []zoho.FilterAction{{ActionType: "moveToFolder", ActionValue: "Nowhere"}}
+	writeDoc()
+	_, err = runCLI(t, "--force", "mail", "settings", "filters", "apply", file)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "folder not found: Nowhere")
+	assert.Len(t, fake.Filters(), 1)
+}
+
+func TestCLIVacation(t *testing.T) {
+	fake := newFakeEnv(t)
+	fake.SetTimeZone("Europe/Berlin")
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "ooo.tmpl")
+	require.NoError(t, os.WriteFile(tmpl, []byte("Subject: {{.Name}} is away\n\nBack on {{.Back}}. Urgent: {{.Backup}}\n"), 0o600))
+	vars := filepath.Join(dir, "vars.json")
+	require.NoError(t, os.WriteFile(vars, []byte(`{"Backup": "ops@example.com"}`), 0o600))
+
+	from := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	until := time.Now().AddDate(0, 0, 9).Format("2006-01-02")
+	_, err := runCLI(t, "mail", "settings", "vacation", "set", "--from", from, "--until", until, "--tz", "UTC",
+		"--template", tmpl, "--vars", vars, "--send-to", "contacts")
+	require.NoError(t, err)
+
+	var vacation zoho.VacationReply
+	require.NoError(t, json.Unmarshal(fake.Vacation(), &vacation))
+	assert.Equal(t, "Admin User is away", vacation.Subject)
+	assert.Contains(t, vacation.Content, "Urgent: ops@example.com")
+	assert.Equal(t, "contacts", vacation.SendTo)
+	// Midnight UTC is 01:00 or 02:00 in Berlin
+	assert.Contains(t, []string{"01:00:00", "02:00:00"}, vacation.FromDate[11:])
+
+	out, err := runCLI(t, "-o", "json", "--results-only", "mail", "settings", "vacation", "get")
+	require.NoError(t, err)
+	var status VacationStatus
+	require.NoError(t, json.Unmarshal([]byte(out), &status))
+	assert.Equal(t, "scheduled", status.Status)
+	assert.Equal(t, "Europe/Berlin", status.TimeZone)
+	assert.Equal(t, 1440, status.IntervalMinutes)
+	start, err := time.Parse(time.RFC3339, status.From)
+	require.NoError(t, err)
+	assert.Equal(t, from, start.UTC().Format("2006-01-02"))
+
+	_, err = runCLI(t, "mail", "settings", "vacation", "set", "--until", "yesterday-ish", "--subject", "Away", "--content", "Gone")
+	require.Error(t, err)
+
+	// The old --to flag and MM/DD/YYYY dates still work
+	_, err = runCLI(t, "--dry-run", "mail", "settings", "vacation", "set", "--to", time.Now().AddDate(0, 0, 3).Format("01/02/2006 15:04:05"),
+		"--subject", "Away", "--content", "Gone")
+	require.NoError(t, err)
+
+	// Admins can set a reply on a user's behalf, in the user's time zone
+	zuid := fake.AddUser(zoho.User{PrimaryEmailID: "leaver@example.com", FirstName: "Lee", LastName: "Ver", TimeZone: "Asia/Tokyo"})
+	_, err = runCLI(t, "admin", "users", "vacation", "set", "leaver@example.com", "--from", from, "--until", "+2w", "--tz", "UTC",
+		"--subject", "No longer with us", "--content", "Please write to ops@example.com")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(fake.UserVacation(zuid), &vacation))
+	assert.Equal(t, "No longer with us", vacation.Subject)
+	assert.Equal(t, "all", vacation.SendTo)
+	// Midnight UTC is 09:00 in Tokyo
+	assert.Equal(t, "09:00:00", vacation.FromDate[11:])
+
+	// Without the mailbox's time zone, --tz must say which one the dates are in
+	fake.AddUser(zoho.User{PrimaryEmailID: "nomad@example.com"})
+	_, err = runCLI(t, "admin", "users", "vacation", "set", "nomad@example.com", "--until", "+2w",
+		"--subject", "Away", "--content", "Gone")
+	assert.ErrorContains(t, err, "pass --tz")
+
+	_, err = runCLI(t, "admin", "users", "vacation", "disable", "leaver@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, fake.UserVacation(zuid))
+}
+
+func TestBindIdentityKeepsConfiguredMailbox(t *testing.T) {
+	identity := &zoho.Identity{AccountID: "111", OrgID: "9", Email: "me@example.com"}
+
+	// First login fills everything in
+	cfg := &config.Config{}
+	bindIdentity(cfg, identity)
+	assert.Equal(t, "111", cfg.AccountID)
+	assert.Equal(t, "9", cfg.OrgID)
+	assert.Equal(t, "me@example.com", cfg.Email)
+
+	// Re-login as the same user keeps a shared mailbox's account ID
+	cfg.AccountID = "222"
+	bindIdentity(cfg, identity)
+	assert.Equal(t, "222", cfg.AccountID)
+
+	// Logging in as someone else rebinds the profile
+	bindIdentity(cfg, &zoho.Identity{AccountID: "333", OrgID: "9", Email: "other@example.com"})
+	assert.Equal(t, "333", cfg.AccountID)
+	assert.Equal(t, "other@example.com", cfg.Email)
+
+	// An identity whose organization could not be looked up keeps the configured one
+	bindIdentity(cfg, &zoho.Identity{AccountID: "333", Email: "other@example.com"})
+	assert.Equal(t, "9", cfg.OrgID)
+}
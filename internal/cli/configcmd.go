@@ -118,11 +118,13 @@ func (cmd *ConfigListConfigCmd) Run(cfg *config.Config, fp *FormatterProvider) e
 	}
 
 	items := []ConfigItem{
+		{Key: "profile", Value: cfg.Profile},
 		{Key: "region", Value: cfg.Region},
 		{Key: "client_id", Value: cfg.ClientID},
 		{Key: "client_secret", Value: maskSecret(cfg.ClientSecret)},
 		{Key: "org_id", Value: cfg.OrgID},
 		{Key: "account_id", Value: cfg.AccountID},
+		{Key: "email", Value: cfg.Email},
 		{Key: "default_output", Value: cfg.DefaultOutput},
 	}
 
@@ -151,7 +153,7 @@ type ConfigPathCmd struct{}
 
 // Run executes the path command
 func (cmd *ConfigPathCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
-	path := config.ConfigPath()
+	path := config.ProfilePath(cfg.Profile)
 
 	// Print path via formatter (respects --output flag)
 	fp.Formatter.Print(path)
@@ -2,19 +2,26 @@ package cli
 
 import (
 	"os"
+	"time"
 
 	"golang.org/x/term"
 )
 
 // Globals holds global flags available to all commands
 type Globals struct {
-	Region      string `help:"Zoho region" default:"" enum:"us,eu,in,au,jp,ca,sa,uk," env:"ZOH_REGION"`
-	Output      string `help:"Output format" default:"auto" enum:"json,plain,rich,auto" short:"o" env:"ZOH_OUTPUT"`
-	Verbose     bool   `help:"Verbose output" short:"v" env:"ZOH_VERBOSE"`
-	ResultsOnly bool   `help:"Strip JSON envelope, return data array only" env:"ZOH_RESULTS_ONLY"`
-	NoInput     bool   `help:"Disable interactive prompts (fail instead)" env:"ZOH_NO_INPUT"`
-	Force       bool   `help:"Skip confirmation prompts for destructive operations" env:"ZOH_FORCE"`
-	DryRun      bool   `help:"Preview operation without executing" name:"dry-run" env:"ZOH_DRY_RUN"`
+	Profile         string        `help:"Named account profile (default: active profile)" env:"ZOH_PROFILE"`
+	Region          string        `help:"Zoho region" default:"" enum:"us,eu,in,au,jp,ca,sa,uk," env:"ZOH_REGION"`
+	Output          string        `help:"Output format" default:"auto" enum:"json,plain,rich,auto" short:"o" env:"ZOH_OUTPUT"`
+	Verbose         bool          `help:"Verbose output" short:"v" env:"ZOH_VERBOSE"`
+	ResultsOnly     bool          `help:"Strip JSON envelope, return data array only" env:"ZOH_RESULTS_ONLY"`
+	NoInput         bool          `help:"Disable interactive prompts (fail instead)" env:"ZOH_NO_INPUT"`
+	Force           bool          `help:"Skip confirmation prompts for destructive operations" env:"ZOH_FORCE"`
+	DryRun          bool          `help:"Preview operation without executing" name:"dry-run" env:"ZOH_DRY_RUN"`
+	TransferTimeout time.Duration `help:"Time limit for each attachment upload or download" name:"transfer-timeout" default:"30m" env:"ZOH_TRANSFER_TIMEOUT"`
+	APIBase         string        `help:"Override all Zoho endpoints with a single base URL" name:"api-base" hidden:"" env:"ZOH_API_BASE"`
+	AccessToken     string        `help:"Use a static access token instead of stored credentials" name:"access-token" hidden:"" env:"ZOH_ACCESS_TOKEN"`
+	Cassette        string        `help:"Record HTTP traffic to, or replay it from, a cassette file" hidden:"" env:"ZOH_CASSETTE"`
+	CassetteMode    string        `help:"Cassette mode" name:"cassette-mode" default:"replay" enum:"record,replay" hidden:"" env:"ZOH_CASSETTE_MODE"`
 }
 
 // ResolvedOutput returns the effective output mode
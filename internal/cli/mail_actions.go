@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// readMessageIDs returns the message IDs given as arguments, or reads them from
+// stdin (whitespace or newline separated) when none are given or the only argument is "-"
+func readMessageIDs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) == 1 && args[0] == "-" {
+		args = nil
+	}
+
+	if len(args) == 0 {
+		if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			return nil, &output.CLIError{
+				Message:  "No message IDs given (pass them as arguments or pipe them on stdin)",
+				ExitCode: output.ExitUsage,
+			}
+		}
+
+		scanner := bufio.NewScanner(stdin)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			args = append(args, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to read message IDs from stdin: %v", err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+	}
+
+	// Deduplicate, keeping the original order
+	seen := make(map[string]bool, len(args))
+	ids := make([]string, 0, len(args))
+	for _, id := range args {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, &output.CLIError{
+			Message:  "No message IDs given",
+			ExitCode: output.ExitUsage,
+		}
+	}
+	return ids, nil
+}
+
+// runMessageAction applies a bulk action to messages, honoring --dry-run.
+// verb and done are formats whose %s is replaced with "N message(s)",
+// e.g. "mark %s as read" and "Marked %s as read".
+func runMessageAction(sp *ServiceProvider, globals *Globals, args []string, verb, done string, action func(ctx context.Context, mc zoho.MailService, ids []string) error) error {
+	ids, err := readMessageIDs(args, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	count := fmt.Sprintf("%d message(s)", len(ids))
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would %s: %s\n", fmt.Sprintf(verb, count), strings.Join(ids, ", "))
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	if err := action(context.Background(), mailClient, ids); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to %s: %v", fmt.Sprintf(verb, count), err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, done+"\n", count)
+	return nil
+}
+
+// MailMessagesMarkReadCmd marks messages as read
+type MailMessagesMarkReadCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the mark-read command
+func (cmd *MailMessagesMarkReadCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "mark %s as read", "Marked %s as read",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.MarkRead(ctx, ids)
+		})
+}
+
+// MailMessagesMarkUnreadCmd marks messages as unread
+type MailMessagesMarkUnreadCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the mark-unread command
+func (cmd *MailMessagesMarkUnreadCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "mark %s as unread", "Marked %s as unread",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.MarkUnread(ctx, ids)
+		})
+}
+
+// MailMessagesFlagCmd sets or clears the flag on messages
+type MailMessagesFlagCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+	Flag       string   `help:"Flag to set (none clears it)" default:"important" enum:"info,important,followup,none"`
+}
+
+// Run executes the flag command
+func (cmd *MailMessagesFlagCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	flag := cmd.Flag
+	if flag == "none" {
+		flag = zoho.FlagNone
+	}
+
+	verb, done := "flag %s as "+cmd.Flag, "Flagged %s as "+cmd.Flag
+	if cmd.Flag == "none" {
+		verb, done = "clear the flag on %s", "Cleared the flag on %s"
+	}
+
+	return runMessageAction(sp, globals, cmd.MessageIDs, verb, done,
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.SetFlag(ctx, ids, flag)
+		})
+}
+
+// MailMessagesMoveCmd moves messages to another folder
+type MailMessagesMoveCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+	To         string   `help:"Destination folder name or ID" required:""`
+}
+
+// Run executes the move command
+func (cmd *MailMessagesMoveCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "move %s to "+cmd.To, "Moved %s to "+cmd.To,
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			folderID, err := resolveFolderID(ctx, mc, cmd.To)
+			if err != nil {
+				return err
+			}
+			return mc.MoveMessages(ctx, ids, folderID)
+		})
+}
+
+// MailMessagesArchiveCmd archives messages
+type MailMessagesArchiveCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the archive command
+func (cmd *MailMessagesArchiveCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "archive %s", "Archived %s",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.ArchiveMessages(ctx, ids)
+		})
+}
+
+// MailMessagesSpamCmd marks messages as spam
+type MailMessagesSpamCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the spam command
+func (cmd *MailMessagesSpamCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "mark %s as spam", "Marked %s as spam",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.MarkSpam(ctx, ids)
+		})
+}
+
+// MailMessagesNotSpamCmd marks messages as not spam
+type MailMessagesNotSpamCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the not-spam command
+func (cmd *MailMessagesNotSpamCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "mark %s as not spam", "Marked %s as not spam",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			return mc.MarkNotSpam(ctx, ids)
+		})
+}
+
+// MailMessagesDeleteCmd moves messages to Trash or deletes them permanently
+type MailMessagesDeleteCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+	Folder     string   `help:"Folder name or ID containing the messages" default:"Inbox" short:"f"`
+	Permanent  bool     `help:"Delete permanently instead of moving to Trash"`
+	Confirm    bool     `help:"Confirm permanent deletion"`
+}
+
+// Run executes the delete command
+func (cmd *MailMessagesDeleteCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement for permanent deletion (unless --force or --dry-run)
+	if cmd.Permanent && !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Permanent deletion requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	verb, done := "move %s to Trash", "Moved %s to Trash"
+	if cmd.Permanent {
+		verb, done = "permanently delete %s", "Permanently deleted %s"
+	}
+
+	return runMessageAction(sp, globals, cmd.MessageIDs, verb, done,
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			folderID, err := resolveFolderID(ctx, mc, cmd.Folder)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err := mc.DeleteMessage(ctx, folderID, id, cmd.Permanent); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+			}
+			return nil
+		})
+}
+
+// MailMessagesLabelAddCmd applies a label to messages
+type MailMessagesLabelAddCmd struct {
+	Label      string   `arg:"" help:"Label name or ID"`
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the label add command
+func (cmd *MailMessagesLabelAddCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "label %s with "+cmd.Label, "Labeled %s with "+cmd.Label,
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			labelID, err := resolveLabelID(ctx, mc, cmd.Label)
+			if err != nil {
+				return err
+			}
+			return mc.ApplyLabels(ctx, ids, []string{labelID})
+		})
+}
+
+// MailMessagesLabelRemoveCmd removes a label from messages
+type MailMessagesLabelRemoveCmd struct {
+	Label      string   `arg:"" help:"Label name or ID"`
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the label remove command
+func (cmd *MailMessagesLabelRemoveCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "remove label "+cmd.Label+" from %s", "Removed label "+cmd.Label+" from %s",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			labelID, err := resolveLabelID(ctx, mc, cmd.Label)
+			if err != nil {
+				return err
+			}
+			return mc.RemoveLabels(ctx, ids, []string{labelID})
+		})
+}
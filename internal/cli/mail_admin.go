@@ -8,32 +8,19 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/SeMmyT/zohcli/internal/auth"
 	"github.com/SeMmyT/zohcli/internal/config"
 	"github.com/SeMmyT/zohcli/internal/output"
-	"github.com/SeMmyT/zohcli/internal/secrets"
 	"github.com/SeMmyT/zohcli/internal/zoho"
 )
 
-// newMailAdminClient creates a MailAdminClient with token cache
+// newMailAdminClient creates a MailAdminClient using the configured token source
 func newMailAdminClient(cfg *config.Config) (*zoho.MailAdminClient, error) {
-	store, err := secrets.NewStore()
+	tokenSource, err := newTokenSource(cfg)
 	if err != nil {
-		return nil, &output.CLIError{
-			Message:  fmt.Sprintf("Failed to initialize secrets store: %v", err),
-			ExitCode: output.ExitGeneral,
-		}
-	}
-
-	tokenCache, err := auth.NewTokenCache(cfg, store)
-	if err != nil {
-		return nil, &output.CLIError{
-			Message:  fmt.Sprintf("Failed to initialize token cache: %v", err),
-			ExitCode: output.ExitGeneral,
-		}
+		return nil, err
 	}
 
-	mac, err := zoho.NewMailAdminClient(cfg, tokenCache)
+	mac, err := zoho.NewMailAdminClient(cfg, tokenSource)
 	if err != nil {
 		// Check if it's an authentication error
 		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "unauthorized") {
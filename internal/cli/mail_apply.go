@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// applyBatchSize is the number of message IDs sent per bulk update request
+const applyBatchSize = 50
+
+// ApplyResultRow is a display struct for the per-message result of a bulk action
+type ApplyResultRow struct {
+	MessageID   string
+	FromAddress string
+	Subject     string
+	Result      string
+	Error       string
+}
+
+// MailMessagesApplyCmd applies an action to every message matching a search
+type MailMessagesApplyCmd struct {
+	Action string `arg:"" help:"Action to apply" enum:"move,label,mark-read,delete"`
+	Query  string `help:"Search query (see mail messages search); in:@name scopes it to a saved search" short:"q"`
+	MessageFilterFlags
+	To        string `help:"Destination folder name or ID (move)"`
+	Label     string `help:"Label name or ID to apply (label)"`
+	Permanent bool   `help:"Delete permanently instead of moving to Trash (delete)"`
+	Limit     int    `help:"Maximum messages to act on (0 = all matches)" short:"l" default:"0"`
+	Confirm   bool   `help:"Confirm the bulk action"`
+}
+
+// Run executes the apply command
+func (cmd *MailMessagesApplyCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals, cfg *config.Config) error {
+	switch {
+	case cmd.Action == "move" && cmd.To == "":
+		return &output.CLIError{Message: "move requires --to", ExitCode: output.ExitUsage}
+	case cmd.Action == "label" && cmd.Label == "":
+		return &output.CLIError{Message: "label requires --label", ExitCode: output.ExitUsage}
+	case cmd.Permanent && cmd.Action != "delete":
+		return &output.CLIError{Message: "--permanent only applies to delete", ExitCode: output.ExitUsage}
+	}
+
+	expr, err := cmd.searchExpr(cmd.Query)
+	if err != nil {
+		return err
+	}
+	if expr, err = expandSavedSearches(cfg.Profile, expr); err != nil {
+		return err
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Collect every match up front, since moving or deleting shifts later pages
+	plan, err := compileSearch(ctx, mailClient, expr)
+	if err != nil {
+		return err
+	}
+	messages, err := searchMessages(ctx, mailClient, plan, cmd.Limit)
+	if err != nil {
+		return err
+	}
+
+	if len(messages) == 0 {
+		fmt.Fprintln(os.Stderr, "No messages match the search")
+		return nil
+	}
+
+	description := cmd.describe(len(messages))
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would %s\n", description)
+		return nil
+	}
+
+	// Check confirmation requirement (unless --force)
+	if !cmd.Confirm && !globals.Force {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("This would %s. Re-run with --confirm or --force to proceed", description),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	action, batchSize, err := cmd.resolveAction(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	// Apply in batches; a failed batch is retried per message to isolate failures
+	failures := make(map[string]error)
+	for start := 0; start < len(messages); start += batchSize {
+		batch := messages[start:min(start+batchSize, len(messages))]
+		err := action(batch)
+		if err == nil {
+			continue
+		}
+		if len(batch) == 1 {
+			failures[batch[0].MessageID] = err
+			continue
+		}
+		for i := range batch {
+			if err := action(batch[i : i+1]); err != nil {
+				failures[batch[i].MessageID] = err
+			}
+		}
+	}
+
+	rows := make([]ApplyResultRow, len(messages))
+	for i, msg := range messages {
+		rows[i] = ApplyResultRow{
+			MessageID:   msg.MessageID,
+			FromAddress: msg.FromAddress,
+			Subject:     msg.Subject,
+			Result:      "ok",
+		}
+		if err, ok := failures[msg.MessageID]; ok {
+			rows[i].Result = "failed"
+			rows[i].Error = err.Error()
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "ID", Key: "MessageID"},
+		{Name: "From", Key: "FromAddress"},
+		{Name: "Subject", Key: "Subject", Width: 50},
+		{Name: "Result", Key: "Result"},
+		{Name: "Error", Key: "Error"},
+	}
+	if err := fp.Formatter.PrintList(rows, columns); err != nil {
+		return err
+	}
+
+	// Print summary to stderr
+	fmt.Fprintf(os.Stderr, "%d succeeded, %d failed\n", len(messages)-len(failures), len(failures))
+
+	if len(failures) > 0 {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to %s: %d message(s) failed", cmd.Action, len(failures)),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// describe returns a human-readable description of the pending action
+func (cmd *MailMessagesApplyCmd) describe(count int) string {
+	switch cmd.Action {
+	case "move":
+		return fmt.Sprintf("move %d message(s) to %s", count, cmd.To)
+	case "label":
+		return fmt.Sprintf("label %d message(s) with %s", count, cmd.Label)
+	case "mark-read":
+		return fmt.Sprintf("mark %d message(s) as read", count)
+	case "delete":
+		if cmd.Permanent {
+			return fmt.Sprintf("permanently delete %d message(s)", count)
+		}
+		return fmt.Sprintf("move %d message(s) to Trash", count)
+	}
+	return fmt.Sprintf("%s %d message(s)", cmd.Action, count)
+}
+
+// resolveAction resolves folder and label names and returns a function applying
+// the action to a batch of messages, together with the batch size to use
+func (cmd *MailMessagesApplyCmd) resolveAction(ctx context.Context, mc zoho.MailService) (func([]zoho.MessageSummary) error, int, error) {
+	switch cmd.Action {
+	case "move":
+		folderID, err := resolveFolderID(ctx, mc, cmd.To)
+		if err != nil {
+			return nil, 0, err
+		}
+		return func(batch []zoho.MessageSummary) error {
+			return mc.MoveMessages(ctx, messageIDs(batch), folderID)
+		}, applyBatchSize, nil
+
+	case "label":
+		labelID, err := resolveLabelID(ctx, mc, cmd.Label)
+		if err != nil {
+			return nil, 0, err
+		}
+		return func(batch []zoho.MessageSummary) error {
+			return mc.ApplyLabels(ctx, messageIDs(batch), []string{labelID})
+		}, applyBatchSize, nil
+
+	case "mark-read":
+		return func(batch []zoho.MessageSummary) error {
+			return mc.MarkRead(ctx, messageIDs(batch))
+		}, applyBatchSize, nil
+
+	case "delete":
+		if cmd.Permanent {
+			// Permanent deletion has no bulk endpoint
+			return func(batch []zoho.MessageSummary) error {
+				return mc.DeleteMessage(ctx, batch[0].FolderID, batch[0].MessageID, true)
+			}, 1, nil
+		}
+
+		trashID, err := resolveFolderID(ctx, mc, "Trash")
+		if err != nil {
+			return nil, 0, err
+		}
+		return func(batch []zoho.MessageSummary) error {
+			return mc.MoveMessages(ctx, messageIDs(batch), trashID)
+		}, applyBatchSize, nil
+	}
+
+	return nil, 0, &output.CLIError{
+		Message:  fmt.Sprintf("Unknown action: %s", cmd.Action),
+		ExitCode: output.ExitUsage,
+	}
+}
+
+// messageIDs returns the IDs of the given messages
+func messageIDs(msgs []zoho.MessageSummary) []string {
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.MessageID
+	}
+	return ids
+}
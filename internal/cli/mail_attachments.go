@@ -0,0 +1,362 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// attachmentStateFile records what download-all has fetched into a directory
+const attachmentStateFile = ".zoh-attachments.json"
+
+// AttachmentDownloadRow is a display struct for bulk download results
+type AttachmentDownloadRow struct {
+	MessageID string
+	Name      string
+	Size      string
+	File      string
+	Result    string
+}
+
+// attachmentState lets download-all skip attachments fetched by earlier runs
+// and deduplicate identical files across messages
+type attachmentState struct {
+	Files      map[string]string `json:"files"`      // SHA-256 -> file written
+	Downloaded map[string]string `json:"downloaded"` // message ID/attachment ID -> SHA-256
+}
+
+// MailAttachmentsDownloadAllCmd downloads every attachment of several messages
+type MailAttachmentsDownloadAllCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Message IDs (\"-\" reads them from stdin; default: every message matching the search filters)"`
+	Folder     string   `help:"Folder of the given message IDs (name, path or ID)" default:"Inbox"`
+	Query      string   `help:"Search query (see mail messages search)" short:"q"`
+	MessageFilterFlags
+	Limit int      `help:"Maximum messages to search (0 = all matches)" short:"l" default:"0"`
+	Out   string   `help:"Output directory (created if missing)" default:"." predictor:"file"`
+	Name  string   `help:"File name template; placeholders: {date} {from} {subject} {name} {message}" default:"{name}"`
+	Mime  []string `help:"Only attachments of this MIME type, e.g. application/pdf or image/* (repeatable)"`
+	Ext   []string `help:"Only attachments with this extension, e.g. pdf (repeatable)"`
+}
+
+// Run executes the download-all command
+func (cmd *MailAttachmentsDownloadAllCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	messages, err := cmd.messages(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		fmt.Fprintln(os.Stderr, "No messages match the search")
+		return nil
+	}
+
+	if !globals.DryRun {
+		if err := os.MkdirAll(cmd.Out, 0o755); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to create %s: %v", cmd.Out, err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+	}
+	statePath := filepath.Join(cmd.Out, attachmentStateFile)
+	state, err := loadAttachmentState(statePath)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]AttachmentDownloadRow, 0)
+	failed := 0
+	for _, msg := range messages {
+		attachments, err := mailClient.ListAttachments(ctx, msg.FolderID, msg.MessageID)
+		if err != nil {
+			rows = append(rows, AttachmentDownloadRow{MessageID: msg.MessageID, Result: "failed: " + err.Error()})
+			failed++
+			continue
+		}
+
+		for _, att := range attachments {
+			if !cmd.wanted(att) {
+				continue
+			}
+			row := AttachmentDownloadRow{
+				MessageID: msg.MessageID,
+				Name:      att.AttachmentName,
+				Size:      formatBytes(att.AttachmentSize),
+			}
+
+			key := msg.MessageID + "/" + att.AttachmentID
+			switch sum, done := state.Downloaded[key]; {
+			case done && fileExists(filepath.Join(cmd.Out, state.Files[sum])):
+				row.File, row.Result = state.Files[sum], "already downloaded"
+			case globals.DryRun:
+				row.File, row.Result = cmd.fileName(msg, att), "would download"
+			default:
+				downloadCtx, finish := withProgress(ctx, showProgress(globals), "Downloading "+att.AttachmentName)
+				row.File, row.Result, err = cmd.download(downloadCtx, mailClient, msg, att, state)
+				finish()
+				if err != nil {
+					row.Result = "failed: " + err.Error()
+					failed++
+				} else if err := saveAttachmentState(statePath, state); err != nil {
+					return err
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Message", Key: "MessageID"},
+		{Name: "Attachment", Key: "Name"},
+		{Name: "Size", Key: "Size"},
+		{Name: "File", Key: "File"},
+		{Name: "Result", Key: "Result"},
+	}
+	if err := fp.Formatter.PrintList(rows, columns); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("%d attachment download(s) failed; re-run to retry", failed),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// messages returns the given messages, or every message matching the search filters
+func (cmd *MailAttachmentsDownloadAllCmd) messages(ctx context.Context, mc zoho.MailService) ([]zoho.MessageSummary, error) {
+	if len(cmd.MessageIDs) > 0 {
+		ids, err := readMessageIDs(cmd.MessageIDs, os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		folder, err := resolveFolder(ctx, mc, cmd.Folder)
+		if err != nil {
+			return nil, err
+		}
+
+		// Metadata is only needed for the name template
+		needsMetadata := metadataPlaceholders.MatchString(cmd.Name)
+		messages := make([]zoho.MessageSummary, len(ids))
+		for i, id := range ids {
+			messages[i] = zoho.MessageSummary{MessageID: id, FolderID: folder.FolderID}
+			if !needsMetadata {
+				continue
+			}
+			meta, err := mc.GetMessageMetadata(ctx, folder.FolderID, id)
+			if err != nil {
+				return nil, &output.CLIError{
+					Message:  fmt.Sprintf("Failed to fetch message %s: %v", id, err),
+					ExitCode: output.ExitAPIError,
+				}
+			}
+			messages[i].FromAddress, messages[i].Subject, messages[i].ReceivedTime = meta.FromAddress, meta.Subject, meta.ReceivedTime
+		}
+		return messages, nil
+	}
+
+	// Only messages with attachments are worth listing
+	cmd.HasAttachment = true
+	expr, err := cmd.searchExpr(cmd.Query)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := compileSearch(ctx, mc, expr)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := searchMessages(ctx, mc, plan, cmd.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first, so the original of a forwarded file keeps its name
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// wanted reports whether an attachment passes the --mime and --ext filters
+func (cmd *MailAttachmentsDownloadAllCmd) wanted(att zoho.Attachment) bool {
+	mime := strings.ToLower(strings.TrimSpace(strings.Split(att.AttachmentType, ";")[0]))
+	if len(cmd.Mime) > 0 && !slices.ContainsFunc(cmd.Mime, func(pattern string) bool {
+		ok, _ := path.Match(strings.ToLower(pattern), mime)
+		return ok
+	}) {
+		return false
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(att.AttachmentName)), ".")
+	if len(cmd.Ext) > 0 && !slices.ContainsFunc(cmd.Ext, func(want string) bool {
+		return strings.TrimPrefix(strings.ToLower(want), ".") == ext
+	}) {
+		return false
+	}
+	return true
+}
+
+// fileName expands the --name template for an attachment
+func (cmd *MailAttachmentsDownloadAllCmd) fileName(msg zoho.MessageSummary, att zoho.Attachment) string {
+	date := ""
+	if msg.ReceivedTime != "" {
+		date = receivedAt(msg.ReceivedTime).Format("2006-01-02")
+	}
+	name := strings.NewReplacer(
+		"{date}", date,
+		"{from}", msg.FromAddress,
+		"{subject}", msg.Subject,
+		"{name}", att.AttachmentName,
+		"{message}", msg.MessageID,
+	).Replace(cmd.Name)
+	return safeAttachmentName(name)
+}
+
+// download fetches one attachment into the output directory. Content already
+// downloaded, from this or another message, is not written again.
+func (cmd *MailAttachmentsDownloadAllCmd) download(ctx context.Context, mc zoho.MailService, msg zoho.MessageSummary, att zoho.Attachment, state *attachmentState) (file, result string, err error) {
+	tmp, err := os.CreateTemp(cmd.Out, ".zoh-download-*")
+	if err != nil {
+		return "", "", err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	defer os.Remove(tmp.Name() + ".part") // left behind by a failed download, with its record
+	defer os.Remove(tmp.Name() + ".part.json")
+
+	if err := mc.DownloadAttachment(ctx, msg.FolderID, msg.MessageID, att.AttachmentID, tmp.Name()); err != nil {
+		return "", "", err
+	}
+	sum, err := fileSHA256(tmp.Name())
+	if err != nil {
+		return "", "", err
+	}
+
+	key := msg.MessageID + "/" + att.AttachmentID
+	if existing, ok := state.Files[sum]; ok && fileExists(filepath.Join(cmd.Out, existing)) {
+		state.Downloaded[key] = sum
+		return existing, "duplicate", nil
+	}
+
+	file = uniqueFileName(cmd.Out, cmd.fileName(msg, att))
+	if err := os.Rename(tmp.Name(), filepath.Join(cmd.Out, file)); err != nil {
+		return "", "", err
+	}
+	state.Files[sum] = file
+	state.Downloaded[key] = sum
+	return file, "downloaded", nil
+}
+
+// metadataPlaceholders matches the name template placeholders filled from message metadata
+var metadataPlaceholders = regexp.MustCompile(`\{(date|from|subject)\}`)
+
+// unsafeNameChars matches characters that are unsafe in file names on common platforms
+var unsafeNameChars = regexp.MustCompile(`[\x00-\x1f\x7f/\\:*?"<>|]+`)
+
+// safeAttachmentName makes an attachment name safe to use as a file name in
+// the output directory: no path separators, no leading dots, bounded length
+func safeAttachmentName(name string) string {
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(name, "_"), " .")
+	if name == "" {
+		return "attachment"
+	}
+
+	// Keep the extension when shortening overlong names
+	const maxLen = 200
+	if len(name) > maxLen {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		base := strings.ToValidUTF8(name[:maxLen-len(ext)], "")
+		name = base + ext
+	}
+	return name
+}
+
+// uniqueFileName returns name, or name with a " (n)" suffix, so no existing file in dir is replaced
+func uniqueFileName(dir, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for n := 2; fileExists(filepath.Join(dir, candidate)); n++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+	return candidate
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's contents
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadAttachmentState reads recorded downloads; a missing file means none
+func loadAttachmentState(path string) (*attachmentState, error) {
+	state := &attachmentState{Files: make(map[string]string), Downloaded: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, state)
+	}
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read download record %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]string)
+	}
+	if state.Downloaded == nil {
+		state.Downloaded = make(map[string]string)
+	}
+	return state, nil
+}
+
+// saveAttachmentState writes the download record
+func saveAttachmentState(path string, state *attachmentState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = fileutil.WriteAtomic(path, append(data, '\n'))
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save download record %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return nil
+}
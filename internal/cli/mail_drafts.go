@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// DraftResult is the output of saving a draft
+type DraftResult struct {
+	DraftID string `json:"draftId"`
+}
+
+// DraftListRow is a display struct for draft list output
+type DraftListRow struct {
+	Subject    string
+	Date       string
+	Attachment string
+	DraftID    string
+}
+
+// MailDraftsListCmd lists saved drafts
+type MailDraftsListCmd struct {
+	Limit int  `help:"Maximum drafts to show" short:"l" default:"50"`
+	All   bool `help:"Fetch all drafts (no pagination limit)" short:"a"`
+}
+
+// Run executes the list drafts command
+func (cmd *MailDraftsListCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	folderID, err := resolveFolderID(ctx, mailClient, "Drafts")
+	if err != nil {
+		return err
+	}
+
+	var drafts []zoho.MessageSummary
+	if cmd.All {
+		iterator := zoho.NewPageIterator(func(start, limit int) ([]zoho.MessageSummary, error) {
+			return mailClient.ListMessages(ctx, folderID, start, limit)
+		}, 50)
+		drafts, err = iterator.FetchAll()
+	} else {
+		drafts, err = mailClient.ListMessages(ctx, folderID, 0, cmd.Limit)
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch drafts: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	rows := make([]DraftListRow, len(drafts))
+	for i, d := range drafts {
+		attachment := ""
+		if d.HasAttachment == "1" || d.HasAttachment == "true" {
+			attachment = "Yes"
+		}
+		rows[i] = DraftListRow{
+			Subject:    d.Subject,
+			Date:       formatReceivedTime(d.ReceivedTime),
+			Attachment: attachment,
+			DraftID:    d.MessageID,
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Subject", Key: "Subject"},
+		{Name: "Date", Key: "Date"},
+		{Name: "Attachment", Key: "Attachment"},
+		{Name: "ID", Key: "DraftID"},
+	}
+
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// MailDraftsGetCmd shows a saved draft
+type MailDraftsGetCmd struct {
+	DraftID string `arg:"" help:"Draft ID"`
+}
+
+// Run executes the get draft command
+func (cmd *MailDraftsGetCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	get := MailMessagesGetCmd{MessageID: cmd.DraftID, Folder: "Drafts"}
+	return get.Run(sp, fp, globals)
+}
+
+// MailDraftsCreateCmd saves a new draft
+type MailDraftsCreateCmd struct {
+	To      string   `help:"Recipient email address"`
+	Cc      string   `help:"CC recipient(s)" short:"c"`
+	Bcc     string   `help:"BCC recipient(s)" short:"b"`
+	Subject string   `help:"Email subject"`
+	Body    string   `help:"Email body content"`
+	HTML    bool     `help:"Save as HTML (default: plain text)" name:"html"`
+	Attach  []string `help:"File path(s) to attach (repeatable)" name:"attach" predictor:"file"`
+}
+
+// Run executes the create draft command
+func (cmd *MailDraftsCreateCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would save draft: %s\n", cmd.Subject)
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	attachments, err := uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals))
+	if err != nil {
+		return err
+	}
+
+	req := &zoho.SendEmailRequest{
+		ToAddress:   cmd.To,
+		CcAddress:   cmd.Cc,
+		BccAddress:  cmd.Bcc,
+		Subject:     cmd.Subject,
+		Content:     cmd.Body,
+		MailFormat:  mailFormat(cmd.HTML),
+		Attachments: attachments,
+	}
+
+	return saveDraft(ctx, mailClient, fp, "", req)
+}
+
+// MailDraftsUpdateCmd edits a saved draft; fields that are not given keep their current value
+type MailDraftsUpdateCmd struct {
+	DraftID string   `arg:"" help:"Draft ID"`
+	To      string   `help:"Recipient email address"`
+	Cc      string   `help:"CC recipient(s)" short:"c"`
+	Bcc     string   `help:"BCC recipient(s)" short:"b"`
+	Subject string   `help:"Email subject"`
+	Body    string   `help:"Email body content"`
+	HTML    bool     `help:"Body is HTML (default: plain text)" name:"html"`
+	Attach  []string `help:"File path(s) to attach, replacing existing attachments (repeatable)" name:"attach" predictor:"file"`
+}
+
+// Run executes the update draft command
+func (cmd *MailDraftsUpdateCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would update draft %s\n", cmd.DraftID)
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	folderID, err := resolveFolderID(ctx, mailClient, "Drafts")
+	if err != nil {
+		return err
+	}
+
+	// Start from the current draft so unspecified fields are kept
+	metadata, err := mailClient.GetMessageMetadata(ctx, folderID, cmd.DraftID)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch draft: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	req := &zoho.SendEmailRequest{
+		ToAddress:  metadata.ToAddress,
+		CcAddress:  metadata.CcAddress,
+		Subject:    metadata.Subject,
+		MailFormat: mailFormat(cmd.HTML),
+		Content:    cmd.Body,
+	}
+	if cmd.Body == "" {
+		// Stored content is always returned as HTML
+		content, err := mailClient.GetMessageContent(ctx, folderID, cmd.DraftID)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch draft content: %v", err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		req.Content = content.Content
+		req.MailFormat = "html"
+	}
+	if cmd.To != "" {
+		req.ToAddress = cmd.To
+	}
+	if cmd.Cc != "" {
+		req.CcAddress = cmd.Cc
+	}
+	if cmd.Subject != "" {
+		req.Subject = cmd.Subject
+	}
+
+	// Zoho replaces the whole draft, so the Bcc recipients, attachments and
+	// plain-text alternative, which only the message source carries, are
+	// copied from it
+	source, err := mailClient.GetOriginalMessage(ctx, folderID, cmd.DraftID)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch draft source: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	stored, err := zoho.ParseRawMessage(bytes.NewReader(source))
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Cannot keep the draft's Bcc and attachments: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	req.BccAddress = cmp.Or(cmd.Bcc, stored.Request.BccAddress)
+	if cmd.Body == "" {
+		req.TextContent = stored.Request.TextContent
+		for _, w := range stored.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+
+	if len(cmd.Attach) > 0 {
+		req.Attachments, err = uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals))
+		if err != nil {
+			return err
+		}
+	} else {
+		// Inline images stay referenced from the stored HTML body
+		var kept []zoho.RawPart
+		for _, part := range stored.Parts {
+			if !part.Inline {
+				kept = append(kept, part)
+			}
+		}
+		if err := uploadRawParts(ctx, mailClient, req, kept, showProgress(globals)); err != nil {
+			return err
+		}
+	}
+
+	if err := mailClient.UpdateDraft(ctx, cmd.DraftID, req); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to update draft: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, "Draft updated: %s\n", cmd.DraftID)
+	return nil
+}
+
+// MailDraftsSendCmd sends a saved draft
+type MailDraftsSendCmd struct {
+	DraftID string `arg:"" help:"Draft ID"`
+}
+
+// Run executes the send draft command
+func (cmd *MailDraftsSendCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would send draft %s\n", cmd.DraftID)
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	if err := mailClient.SendDraft(context.Background(), cmd.DraftID); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to send draft: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, "Draft sent: %s\n", cmd.DraftID)
+	return nil
+}
+
+// MailDraftsDeleteCmd deletes a saved draft
+type MailDraftsDeleteCmd struct {
+	DraftID string `arg:"" help:"Draft ID"`
+	Confirm bool   `help:"Confirm deletion"`
+}
+
+// Run executes the delete draft command
+func (cmd *MailDraftsDeleteCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement (unless --force or --dry-run)
+	if !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Deletion requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would delete draft %s\n", cmd.DraftID)
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	folderID, err := resolveFolderID(ctx, mailClient, "Drafts")
+	if err != nil {
+		return err
+	}
+
+	if err := mailClient.DeleteMessage(ctx, folderID, cmd.DraftID, true); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to delete draft: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, "Draft deleted: %s\n", cmd.DraftID)
+	return nil
+}
+
+// saveDraft saves req as a draft (of a reply or forward when messageID is set)
+// and prints the new draft ID
+func saveDraft(ctx context.Context, mc zoho.MailService, fp *FormatterProvider, messageID string, req *zoho.SendEmailRequest) error {
+	draftID, err := mc.SaveDraft(ctx, messageID, req)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save draft: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, "Draft saved: %s\n", draftID)
+
+	return fp.Formatter.Print(DraftResult{DraftID: draftID})
+}
+
+// uploadAttachments uploads each file and returns references for a send request.
+// Every file is size-checked before the first upload starts.
+func uploadAttachments(ctx context.Context, mc zoho.MailService, paths []string, progress bool) ([]zoho.AttachmentReference, error) {
+	if err := checkAttachmentSizes(paths); err != nil {
+		return nil, err
+	}
+	var attachments []zoho.AttachmentReference
+	for _, filePath := range paths {
+		uploadCtx, done := withProgress(ctx, progress, "Uploading "+filepath.Base(filePath))
+		ref, err := mc.UploadAttachment(uploadCtx, filePath)
+		done()
+		var tooLarge *zoho.AttachmentTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, attachmentTooLarge(err)
+		}
+		if err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to upload attachment %s: %v", filePath, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		attachments = append(attachments, *ref)
+	}
+	return attachments, nil
+}
+
+// mailFormat returns the SendEmailRequest mail format for a body
+func mailFormat(html bool) string {
+	if html {
+		return "html"
+	}
+	return "plaintext"
+}
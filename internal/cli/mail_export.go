@@ -0,0 +1,456 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// exportStateFile is the progress file kept in the export directory
+const exportStateFile = ".zoh-export.json"
+
+// ExportSummary is a display struct for the result of an export
+type ExportSummary struct {
+	Folder      string
+	Format      string
+	Out         string
+	Exported    int
+	Skipped     int
+	Matched     int
+	FolderCount int
+	Warning     string
+}
+
+// exportState records which messages an export has written, so a re-run resumes
+type exportState struct {
+	FolderID string            `json:"folderId"`
+	Format   string            `json:"format"`
+	Exported map[string]string `json:"exported"`           // message ID -> file written
+	MboxSize int64             `json:"mboxSize,omitempty"` // mbox length after the last complete message
+}
+
+// MailExportCmd exports a folder's messages to local archive files
+type MailExportCmd struct {
+	Folder  string `help:"Folder name, path or ID, or @name for a saved search" required:"" short:"f"`
+	Format  string `help:"Archive format" enum:"mbox,maildir,eml" default:"mbox"`
+	Out     string `help:"Output directory (created if missing)" required:"" predictor:"file"`
+	After   string `help:"Only messages received on or after this date (YYYY-MM-DD or RFC3339)"`
+	Before  string `help:"Only messages received on or before this date (YYYY-MM-DD or RFC3339)"`
+	Restart bool   `help:"Ignore recorded progress and export every message again"`
+}
+
+// Run executes the export command
+func (cmd *MailExportCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals, cfg *config.Config) error {
+	var after, before time.Time
+	var err error
+	if cmd.After != "" {
+		if after, err = parseDate(cmd.After, false); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Invalid --after date: %v", err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+	}
+	if cmd.Before != "" {
+		if before, err = parseDate(cmd.Before, true); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Invalid --before date: %v", err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	src, err := resolveMessageSource(ctx, mailClient, cfg.Profile, cmd.Folder)
+	if err != nil {
+		return err
+	}
+	folder := src.Folder
+
+	// Page through the whole folder, or run a saved search once; the date
+	// filters are applied locally
+	var listed []zoho.MessageSummary
+	if src.saved != nil {
+		listed, err = src.list(ctx, mailClient, 0, 0)
+	} else {
+		listed, err = zoho.NewPageIterator(func(start, limit int) ([]zoho.MessageSummary, error) {
+			return src.list(ctx, mailClient, start, limit)
+		}, 200).FetchAll()
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch messages: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	var messages []zoho.MessageSummary
+	for _, msg := range listed {
+		received := receivedAt(msg.ReceivedTime)
+		if (!after.IsZero() && received.Before(after)) || (!before.IsZero() && received.After(before)) {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	statePath := filepath.Join(cmd.Out, exportStateFile)
+	state := &exportState{FolderID: folder.FolderID, Format: cmd.Format, Exported: make(map[string]string)}
+	if !cmd.Restart {
+		if state, err = loadExportState(statePath, folder.FolderID, cmd.Format); err != nil {
+			return err
+		}
+	}
+
+	pending := 0
+	for _, msg := range messages {
+		if _, done := state.Exported[msg.MessageID]; !done {
+			pending++
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would export %d message(s) from %s to %s as %s (%d already exported)\n",
+			pending, folder.Path, cmd.Out, cmd.Format, len(messages)-pending)
+		return nil
+	}
+
+	if err := os.MkdirAll(cmd.Out, 0o700); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to create %s: %v", cmd.Out, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	w, err := newExportWriter(cmd.Format, cmd.Out, folder, state)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to open export: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	defer w.Close()
+
+	exported := 0
+	for _, msg := range messages {
+		if _, done := state.Exported[msg.MessageID]; done {
+			continue
+		}
+
+		raw, err := mailClient.GetOriginalMessage(ctx, src.folderOf(msg), msg.MessageID)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch message %s after exporting %d: %v (re-run to resume)", msg.MessageID, exported, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+
+		name, err := w.Write(msg, raw)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to write message %s: %v", msg.MessageID, err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+
+		state.Exported[msg.MessageID] = name
+		if err := saveExportState(statePath, state); err != nil {
+			return err
+		}
+		exported++
+		fmt.Fprintf(os.Stderr, "\rExported %d/%d", exported, pending)
+	}
+	if exported > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	// Every matched message must now be recorded as exported
+	written := 0
+	for _, msg := range messages {
+		if _, done := state.Exported[msg.MessageID]; done {
+			written++
+		}
+	}
+	if written != len(messages) {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Export incomplete: %d of %d messages written", written, len(messages)),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	summary := ExportSummary{
+		Folder:      folder.Path,
+		Format:      cmd.Format,
+		Out:         cmd.Out,
+		Exported:    exported,
+		Skipped:     len(messages) - exported,
+		Matched:     len(messages),
+		FolderCount: folder.MessageCount,
+	}
+
+	// A whole folder is checked against its message count once exported;
+	// messages that arrive or are deleted meanwhile make them differ
+	filtered := !after.IsZero() || !before.IsZero()
+	if !filtered && src.saved == nil {
+		if now, err := resolveFolder(ctx, mailClient, folder.FolderID); err == nil {
+			summary.FolderCount = now.MessageCount
+		}
+		if summary.FolderCount != len(listed) {
+			summary.Warning = fmt.Sprintf("folder %s reports %d messages but %d were listed; re-run to export any that arrived since",
+				folder.Path, summary.FolderCount, len(listed))
+		}
+	}
+
+	if err := fp.Formatter.Print(summary); err != nil {
+		return err
+	}
+	if summary.Warning != "" {
+		return &output.CLIError{
+			Message:  "Export incomplete: " + summary.Warning,
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// resolveFolder finds a folder by path, name or ID
+func resolveFolder(ctx context.Context, mc zoho.MailService, folderNameOrID string) (*zoho.Folder, error) {
+	folders, err := mc.ListFolders(ctx)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch folders: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	if strings.Contains(folderNameOrID, "/") {
+		if folder := findFolderByPath(folders, folderNameOrID); folder != nil {
+			return folder, nil
+		}
+	}
+	for i := range folders {
+		if strings.EqualFold(folders[i].FolderName, folderNameOrID) || folders[i].FolderID == folderNameOrID {
+			return &folders[i], nil
+		}
+	}
+
+	return nil, &output.CLIError{
+		Message:  fmt.Sprintf("Folder not found: %s", folderNameOrID),
+		ExitCode: output.ExitNotFound,
+	}
+}
+
+// receivedAt converts a Unix-milliseconds timestamp string to a time
+func receivedAt(ms string) time.Time {
+	n, _ := strconv.ParseInt(ms, 10, 64)
+	return time.UnixMilli(n)
+}
+
+// loadExportState reads recorded export progress; a missing file means no progress
+func loadExportState(path, folderID, format string) (*exportState, error) {
+	state := &exportState{FolderID: folderID, Format: format, Exported: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, state)
+	}
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read export progress %s: %v (use --restart to ignore it)", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if state.FolderID != folderID || state.Format != format {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("%s holds a %s export of another folder or format (use another --out or --restart)", filepath.Dir(path), state.Format),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if state.Exported == nil {
+		state.Exported = make(map[string]string)
+	}
+	return state, nil
+}
+
+// saveExportState atomically writes export progress
+func saveExportState(path string, state *exportState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = fileutil.WriteAtomic(path, append(data, '\n'))
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save export progress %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return nil
+}
+
+// exportWriter stores exported messages in one archive format
+type exportWriter interface {
+	// Write stores one message and returns the file it was written to
+	Write(msg zoho.MessageSummary, raw []byte) (string, error)
+	Close() error
+}
+
+// newExportWriter opens the writer for format in dir, continuing from state
+func newExportWriter(format, dir string, folder *zoho.Folder, state *exportState) (exportWriter, error) {
+	switch format {
+	case "mbox":
+		return openMboxWriter(filepath.Join(dir, safeFileName(folder.FolderName)+".mbox"), state)
+	case "maildir":
+		for _, sub := range []string{"tmp", "new", "cur"} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+				return nil, err
+			}
+		}
+		return &maildirWriter{dir: dir}, nil
+	default:
+		return &emlWriter{dir: dir}, nil
+	}
+}
+
+// unsafeFileChars matches characters that are not safe in file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// safeFileName reduces s to a portable file name
+func safeFileName(s string) string {
+	s = strings.Trim(unsafeFileChars.ReplaceAllString(s, "_"), "_.")
+	if s == "" {
+		return "messages"
+	}
+	return s
+}
+
+// mboxWriter appends messages to a single mboxrd file
+type mboxWriter struct {
+	path  string
+	f     *os.File
+	state *exportState
+}
+
+// openMboxWriter opens path for appending, dropping any partial message left by an interrupted run
+func openMboxWriter(path string, state *exportState) (*mboxWriter, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(state.MboxSize); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(state.MboxSize, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &mboxWriter{path: path, f: f, state: state}, nil
+}
+
+func (w *mboxWriter) Write(msg zoho.MessageSummary, raw []byte) (string, error) {
+	var buf bytes.Buffer
+	sender := msg.FromAddress
+	if sender == "" {
+		sender = "MAILER-DAEMON"
+	}
+	fmt.Fprintf(&buf, "From %s %s\n", sender, receivedAt(msg.ReceivedTime).UTC().Format(time.ANSIC))
+
+	// mboxrd quoting: any line starting with >*From gains one more >
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), len(raw)+1)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			buf.WriteByte('>')
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	buf.WriteByte('\n')
+
+	if _, err := w.f.Write(buf.Bytes()); err != nil {
+		return "", err
+	}
+	if err := w.f.Sync(); err != nil {
+		return "", err
+	}
+	w.state.MboxSize += int64(buf.Len())
+	return filepath.Base(w.path), nil
+}
+
+func (w *mboxWriter) Close() error {
+	return w.f.Close()
+}
+
+// maildirWriter delivers messages into a Maildir's cur directory
+type maildirWriter struct {
+	dir string
+}
+
+func (w *maildirWriter) Write(msg zoho.MessageSummary, raw []byte) (string, error) {
+	flags := ""
+	if msg.FlagID != "" && msg.FlagID != zoho.FlagNone {
+		flags += "F"
+	}
+	if msg.Status == "1" {
+		flags += "S"
+	}
+	name := fmt.Sprintf("%d.%s.zoh:2,%s", receivedAt(msg.ReceivedTime).Unix(), safeFileName(msg.MessageID), flags)
+
+	// Deliver through tmp so a partial file never appears in cur
+	tmp := filepath.Join(w.dir, "tmp", name)
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return "", err
+	}
+	rel := filepath.Join("cur", name)
+	if err := os.Rename(tmp, filepath.Join(w.dir, rel)); err != nil {
+		return "", err
+	}
+	return rel, nil
+}
+
+func (w *maildirWriter) Close() error {
+	return nil
+}
+
+// emlWriter writes one .eml file per message
+type emlWriter struct {
+	dir string
+}
+
+func (w *emlWriter) Write(msg zoho.MessageSummary, raw []byte) (string, error) {
+	name := safeFileName(msg.MessageID) + ".eml"
+	if err := fileutil.WriteAtomic(filepath.Join(w.dir, name), raw); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (w *emlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,861 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// FilterRow is a display struct for filter list output
+type FilterRow struct {
+	Order      int
+	Name       string
+	Enabled    string
+	Match      string
+	Conditions string
+	Actions    string
+	ID         string
+}
+
+// filtersFile is the document filters export writes and filters apply reads.
+// Filters are listed in the order they run, without IDs, and folders and
+// labels are named rather than given by ID, so one file can be applied to
+// several mailboxes.
+type filtersFile struct {
+	Filters []zoho.MailFilter `json:"filters"`
+}
+
+// MailSettingsFiltersListCmd lists incoming mail filters
+type MailSettingsFiltersListCmd struct{}
+
+// Run executes the list filters command
+func (cmd *MailSettingsFiltersListCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	filters, err := listFilters(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+	refs, err := loadFilterRefs(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]FilterRow, len(filters))
+	for i, f := range filters {
+		portable := refs.portable(f)
+		conditions := make([]string, len(portable.Conditions))
+		for j, c := range portable.Conditions {
+			conditions[j] = describeFilterCondition(c)
+		}
+		actions := make([]string, len(portable.Actions))
+		for j, a := range portable.Actions {
+			actions[j] = describeFilterAction(a)
+		}
+		rows[i] = FilterRow{
+			Order:      f.FilterOrder,
+			Name:       f.FilterName,
+			Enabled:    yesNo(f.Enabled),
+			Match:      f.ConditionType,
+			Conditions: strings.Join(conditions, "; "),
+			Actions:    strings.Join(actions, "; "),
+			ID:         f.FilterID,
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Order", Key: "Order"},
+		{Name: "Name", Key: "Name"},
+		{Name: "Enabled", Key: "Enabled"},
+		{Name: "Match", Key: "Match"},
+		{Name: "Conditions", Key: "Conditions", Width: 50},
+		{Name: "Actions", Key: "Actions", Width: 40},
+		{Name: "ID", Key: "ID"},
+	}
+
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// MailSettingsFiltersGetCmd shows one filter
+type MailSettingsFiltersGetCmd struct {
+	Filter string `arg:"" help:"Filter name or ID"`
+}
+
+// Run executes the get filter command
+func (cmd *MailSettingsFiltersGetCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	filter, err := resolveFilter(ctx, mailClient, cmd.Filter)
+	if err != nil {
+		return err
+	}
+
+	return fp.Formatter.Print(filter)
+}
+
+// FilterDefinitionFlags are the flags describing a filter's match and actions
+type FilterDefinitionFlags struct {
+	Match     string   `help:"Run the actions when all or any of the conditions hold (all, any)"`
+	Condition []string `help:"Condition as 'FIELD COMPARISON VALUE', e.g. 'subject contains invoice' (repeatable)" short:"c"`
+	Action    []string `help:"Action as 'TYPE [VALUE]', e.g. 'moveToFolder Receipts' or 'markAsRead' (repeatable)" short:"a"`
+}
+
+// apply sets the flags given on a filter; folder and label names in actions are left for resolve
+func (flags *FilterDefinitionFlags) apply(f *zoho.MailFilter) error {
+	if flags.Match != "" {
+		if flags.Match != "all" && flags.Match != "any" {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Invalid --match %q (use all or any)", flags.Match),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		f.ConditionType = flags.Match
+	}
+
+	if len(flags.Condition) > 0 {
+		f.Conditions = nil
+		for _, c := range flags.Condition {
+			parts := strings.SplitN(strings.TrimSpace(c), " ", 3)
+			if len(parts) != 3 || strings.TrimSpace(parts[2]) == "" {
+				return &output.CLIError{
+					Message:  fmt.Sprintf("Invalid condition %q (use 'FIELD COMPARISON VALUE')", c),
+					ExitCode: output.ExitUsage,
+				}
+			}
+			f.Conditions = append(f.Conditions, zoho.FilterCondition{Field: parts[0], Comparison: parts[1], Value: strings.TrimSpace(parts[2])})
+		}
+	}
+
+	if len(flags.Action) > 0 {
+		f.Actions = nil
+		for _, a := range flags.Action {
+			actionType, value, _ := strings.Cut(strings.TrimSpace(a), " ")
+			if actionType == "" {
+				return &output.CLIError{
+					Message:  fmt.Sprintf("Invalid action %q (use 'TYPE [VALUE]')", a),
+					ExitCode: output.ExitUsage,
+				}
+			}
+			f.Actions = append(f.Actions, zoho.FilterAction{ActionType: actionType, ActionValue: strings.TrimSpace(value)})
+		}
+	}
+	return nil
+}
+
+// MailSettingsFiltersCreateCmd creates a filter, run after the existing ones
+type MailSettingsFiltersCreateCmd struct {
+	Name string `help:"Filter name" required:""`
+	FilterDefinitionFlags
+	Disabled bool `help:"Create the filter disabled"`
+}
+
+// Run executes the create filter command
+func (cmd *MailSettingsFiltersCreateCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	filter := zoho.MailFilter{FilterName: cmd.Name, Enabled: !cmd.Disabled, ConditionType: "all"}
+	if err := cmd.apply(&filter); err != nil {
+		return err
+	}
+	if len(filter.Conditions) == 0 || len(filter.Actions) == 0 {
+		return &output.CLIError{
+			Message:  "A filter needs at least one --condition and one --action",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	refs, err := loadFilterRefs(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+	resolved, err := refs.resolve(filter)
+	if err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would create filter: %s\n", cmd.Name)
+		return nil
+	}
+
+	created, err := mailClient.CreateFilter(ctx, &resolved)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to create filter: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Created filter %s (ID: %s)\n", created.FilterName, created.FilterID)
+	return nil
+}
+
+// MailSettingsFiltersUpdateCmd changes a filter; conditions and actions given replace the existing ones
+type MailSettingsFiltersUpdateCmd struct {
+	Filter string `arg:"" help:"Filter name or ID"`
+	Name   string `help:"New filter name"`
+	FilterDefinitionFlags
+	Enable  bool `help:"Enable the filter" xor:"enabled"`
+	Disable bool `help:"Disable the filter" xor:"enabled"`
+}
+
+// Run executes the update filter command
+func (cmd *MailSettingsFiltersUpdateCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	existing, err := resolveFilter(ctx, mailClient, cmd.Filter)
+	if err != nil {
+		return err
+	}
+	refs, err := loadFilterRefs(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	// Edit the portable form so unchanged actions resolve back to the same IDs
+	filter := refs.portable(*existing)
+	if cmd.Name != "" {
+		filter.FilterName = cmd.Name
+	}
+	if cmd.Enable || cmd.Disable {
+		filter.Enabled = cmd.Enable
+	}
+	if err := cmd.apply(&filter); err != nil {
+		return err
+	}
+	resolved, err := refs.resolve(filter)
+	if err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would update filter: %s\n", existing.FilterName)
+		return nil
+	}
+
+	if err := mailClient.UpdateFilter(ctx, existing.FilterID, &resolved); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to update filter: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Filter updated: %s\n", resolved.FilterName)
+	return nil
+}
+
+// MailSettingsFiltersDeleteCmd deletes a filter
+type MailSettingsFiltersDeleteCmd struct {
+	Filter  string `arg:"" help:"Filter name or ID"`
+	Confirm bool   `help:"Confirm deletion"`
+}
+
+// Run executes the delete filter command
+func (cmd *MailSettingsFiltersDeleteCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement (unless --force or --dry-run)
+	if !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Deletion requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	filter, err := resolveFilter(ctx, mailClient, cmd.Filter)
+	if err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would delete filter: %s\n", filter.FilterName)
+		return nil
+	}
+
+	if err := mailClient.DeleteFilter(ctx, filter.FilterID); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to delete filter: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Filter deleted: %s\n", filter.FilterName)
+	return nil
+}
+
+// MailSettingsFiltersReorderCmd moves filters to the front of the run order
+type MailSettingsFiltersReorderCmd struct {
+	Filters []string `arg:"" help:"Filter names or IDs in the order they should run; unlisted filters follow in their current order"`
+}
+
+// Run executes the reorder filters command
+func (cmd *MailSettingsFiltersReorderCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	filters, err := listFilters(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	var order []zoho.MailFilter
+	for _, nameOrID := range cmd.Filters {
+		f := findFilter(filters, nameOrID)
+		if f == nil {
+			return filterNotFound(nameOrID)
+		}
+		if slices.ContainsFunc(order, func(o zoho.MailFilter) bool { return o.FilterID == f.FilterID }) {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Filter listed twice: %s", nameOrID),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		order = append(order, *f)
+	}
+	for _, f := range filters {
+		if !slices.ContainsFunc(order, func(o zoho.MailFilter) bool { return o.FilterID == f.FilterID }) {
+			order = append(order, f)
+		}
+	}
+
+	names := filterNames(order)
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would reorder filters: %s\n", strings.Join(names, ", "))
+		return nil
+	}
+
+	if err := mailClient.ReorderFilters(ctx, filterIDs(order)); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to reorder filters: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Filters reordered: %s\n", strings.Join(names, ", "))
+	return nil
+}
+
+// MailSettingsFiltersExportCmd writes the filters as a portable JSON document
+type MailSettingsFiltersExportCmd struct{}
+
+// Run executes the export filters command
+func (cmd *MailSettingsFiltersExportCmd) Run(sp *ServiceProvider) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	filters, err := listFilters(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+	refs, err := loadFilterRefs(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	doc := filtersFile{Filters: []zoho.MailFilter{}}
+	for _, f := range filters {
+		doc.Filters = append(doc.Filters, refs.portable(f))
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal filters: %w", err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+// MailSettingsFiltersApplyCmd makes the account's filters match an exported document
+type MailSettingsFiltersApplyCmd struct {
+	File    string `arg:"" help:"Filters document written by filters export" type:"existingfile"`
+	Prune   bool   `help:"Delete filters the document does not list"`
+	Confirm bool   `help:"Confirm the changes"`
+}
+
+// filterChange is one step of applying a filters document
+type filterChange struct {
+	Op     string // "create", "update" or "delete"
+	Name   string
+	ID     string
+	Filter zoho.MailFilter // resolved filter to create or update to
+	Diff   []string
+}
+
+// Run executes the apply filters command
+func (cmd *MailSettingsFiltersApplyCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	desired, err := readFiltersFile(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	current, err := listFilters(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+	refs, err := loadFilterRefs(ctx, mailClient)
+	if err != nil {
+		return err
+	}
+
+	// Plan: filters are matched by name
+	var changes []filterChange
+	managed := make(map[string]bool)
+	for _, want := range desired.Filters {
+		managed[want.FilterName] = true
+		resolved, err := refs.resolve(want)
+		if err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(current, func(f zoho.MailFilter) bool { return f.FilterName == want.FilterName })
+		if idx < 0 {
+			changes = append(changes, filterChange{Op: "create", Name: want.FilterName, Filter: resolved})
+			continue
+		}
+		before := refs.portable(current[idx])
+		after := refs.portable(resolved)
+		if diff := diffLines(filterJSON(before), filterJSON(after)); diff != nil {
+			changes = append(changes, filterChange{Op: "update", Name: want.FilterName, ID: current[idx].FilterID, Filter: resolved, Diff: diff})
+		}
+	}
+
+	var unmanaged []string
+	for _, f := range current {
+		if managed[f.FilterName] {
+			continue
+		}
+		if cmd.Prune {
+			changes = append(changes, filterChange{Op: "delete", Name: f.FilterName, ID: f.FilterID})
+		} else {
+			unmanaged = append(unmanaged, f.FilterName)
+		}
+	}
+
+	// The document's filters run first, in its order, then any it does not manage.
+	// Compare with the order the filters would be in after creating and deleting.
+	target := append(filterNames(desired.Filters), unmanaged...)
+	var after []string
+	for _, f := range current {
+		if managed[f.FilterName] || !cmd.Prune {
+			after = append(after, f.FilterName)
+		}
+	}
+	for _, c := range changes {
+		if c.Op == "create" {
+			after = append(after, c.Name)
+		}
+	}
+	reorder := !slices.Equal(after, target)
+
+	if len(changes) == 0 && !reorder {
+		fmt.Fprintln(os.Stderr, "Filters are up to date")
+		return nil
+	}
+
+	// Preview
+	for _, c := range changes {
+		switch c.Op {
+		case "create":
+			fmt.Fprintf(os.Stdout, "+ create %s\n", c.Name)
+		case "update":
+			fmt.Fprintf(os.Stdout, "~ update %s\n", c.Name)
+			for _, line := range c.Diff {
+				fmt.Fprintf(os.Stdout, "    %s\n", line)
+			}
+		case "delete":
+			fmt.Fprintf(os.Stdout, "- delete %s\n", c.Name)
+		}
+	}
+	if reorder {
+		fmt.Fprintf(os.Stdout, "= reorder %s\n", strings.Join(target, ", "))
+	}
+
+	description := describeFilterChanges(changes, reorder)
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would %s\n", description)
+		return nil
+	}
+
+	// Check confirmation requirement (unless --force)
+	if !cmd.Confirm && !globals.Force {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("This would %s. Re-run with --confirm or --force to proceed", description),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Delete first so pruned filters never run alongside their replacements
+	ids := make(map[string]string)
+	for _, f := range current {
+		ids[f.FilterName] = f.FilterID
+	}
+	for _, op := range []string{"delete", "update", "create"} {
+		for _, c := range changes {
+			if c.Op != op {
+				continue
+			}
+			if err := applyFilterChange(ctx, mailClient, c, ids); err != nil {
+				return &output.CLIError{
+					Message:  fmt.Sprintf("Failed to %s filter %s: %v", c.Op, c.Name, err),
+					ExitCode: output.ExitAPIError,
+				}
+			}
+		}
+	}
+
+	if reorder {
+		order := make([]string, len(target))
+		for i, name := range target {
+			order[i] = ids[name]
+		}
+		if err := mailClient.ReorderFilters(ctx, order); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to reorder filters: %v", err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Applied filters: %s\n", description)
+	return nil
+}
+
+// applyFilterChange carries out one planned change, recording created filters' IDs in ids
+func applyFilterChange(ctx context.Context, mc zoho.MailService, c filterChange, ids map[string]string) error {
+	switch c.Op {
+	case "create":
+		created, err := mc.CreateFilter(ctx, &c.Filter)
+		if err != nil {
+			return err
+		}
+		ids[c.Name] = created.FilterID
+		return nil
+	case "update":
+		return mc.UpdateFilter(ctx, c.ID, &c.Filter)
+	default:
+		if err := mc.DeleteFilter(ctx, c.ID); err != nil {
+			return err
+		}
+		delete(ids, c.Name)
+		return nil
+	}
+}
+
+// describeFilterChanges summarizes planned changes, e.g. "create 1, update 2 filter(s) and reorder"
+func describeFilterChanges(changes []filterChange, reorder bool) string {
+	var parts []string
+	for _, op := range []string{"create", "update", "delete"} {
+		n := 0
+		for _, c := range changes {
+			if c.Op == op {
+				n++
+			}
+		}
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", op, n))
+		}
+	}
+	description := strings.Join(parts, ", ")
+	if description != "" {
+		description += " filter(s)"
+	}
+	if reorder {
+		if description != "" {
+			description += " and "
+		}
+		description += "reorder filters"
+	}
+	return description
+}
+
+// readFiltersFile reads and checks a filters document
+func readFiltersFile(path string) (*filtersFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read filters: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	invalid := func(format string, args ...any) error {
+		return (&output.CLIError{
+			Message:  fmt.Sprintf("Invalid filters file %s: %s", path, fmt.Sprintf(format, args...)),
+			ExitCode: output.ExitUsage,
+		}).WithHint("Start from the output of 'zoh mail settings filters export'")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc filtersFile
+	if err := dec.Decode(&doc); err != nil {
+		return nil, invalid("%v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range doc.Filters {
+		f := &doc.Filters[i]
+		f.FilterID, f.FilterOrder = "", 0
+		switch {
+		case f.FilterName == "":
+			return nil, invalid("filter %d has no filterName", i+1)
+		case seen[f.FilterName]:
+			return nil, invalid("filter %q is listed twice", f.FilterName)
+		case f.ConditionType != "all" && f.ConditionType != "any":
+			return nil, invalid("filter %q: conditionType must be all or any", f.FilterName)
+		case len(f.Conditions) == 0 || len(f.Actions) == 0:
+			return nil, invalid("filter %q needs conditions and actions", f.FilterName)
+		}
+		seen[f.FilterName] = true
+	}
+	return &doc, nil
+}
+
+// filterRefs holds the folders and labels filter actions refer to
+type filterRefs struct {
+	folders []zoho.Folder
+	labels  []zoho.Label
+}
+
+// loadFilterRefs fetches the account's folders and labels
+func loadFilterRefs(ctx context.Context, mc zoho.MailService) (*filterRefs, error) {
+	folders, err := mc.ListFolders(ctx)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch folders: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	labels, err := mc.ListLabels(ctx)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch labels: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return &filterRefs{folders: folders, labels: labels}, nil
+}
+
+// portable returns f without its ID and position, with folder IDs in actions
+// replaced by folder paths and label IDs by label names
+func (r *filterRefs) portable(f zoho.MailFilter) zoho.MailFilter {
+	f.FilterID, f.FilterOrder = "", 0
+	f.Actions = slices.Clone(f.Actions)
+	for i, a := range f.Actions {
+		switch a.ActionType {
+		case zoho.FilterActionMove, zoho.FilterActionCopy:
+			if j := slices.IndexFunc(r.folders, func(folder zoho.Folder) bool { return folder.FolderID == a.ActionValue }); j >= 0 {
+				f.Actions[i].ActionValue = r.folders[j].Path
+			}
+		case zoho.FilterActionLabel:
+			if j := slices.IndexFunc(r.labels, func(label zoho.Label) bool { return label.LabelID == a.ActionValue }); j >= 0 {
+				f.Actions[i].ActionValue = r.labels[j].LabelName
+			}
+		}
+	}
+	return f
+}
+
+// resolve returns f with the folder paths, names and label names in its actions
+// replaced by IDs; IDs are accepted as they are
+func (r *filterRefs) resolve(f zoho.MailFilter) (zoho.MailFilter, error) {
+	f.Actions = slices.Clone(f.Actions)
+	for i, a := range f.Actions {
+		switch a.ActionType {
+		case zoho.FilterActionMove, zoho.FilterActionCopy:
+			folder := findFolderByPath(r.folders, a.ActionValue)
+			if folder == nil {
+				for j := range r.folders {
+					if r.folders[j].FolderID == a.ActionValue || strings.EqualFold(r.folders[j].FolderName, a.ActionValue) {
+						folder = &r.folders[j]
+						break
+					}
+				}
+			}
+			if folder == nil {
+				return f, (&output.CLIError{
+					Message:  fmt.Sprintf("Filter %s: folder not found: %s", f.FilterName, a.ActionValue),
+					ExitCode: output.ExitNotFound,
+				}).WithHint("Create it first with 'zoh mail folders create'")
+			}
+			f.Actions[i].ActionValue = folder.FolderID
+		case zoho.FilterActionLabel:
+			j := slices.IndexFunc(r.labels, func(label zoho.Label) bool {
+				return label.LabelID == a.ActionValue || strings.EqualFold(label.LabelName, a.ActionValue)
+			})
+			if j < 0 {
+				return f, (&output.CLIError{
+					Message:  fmt.Sprintf("Filter %s: label not found: %s", f.FilterName, a.ActionValue),
+					ExitCode: output.ExitNotFound,
+				}).WithHint("Create it first with 'zoh mail labels create'")
+			}
+			f.Actions[i].ActionValue = r.labels[j].LabelID
+		}
+	}
+	return f, nil
+}
+
+// listFilters fetches the account's filters in run order
+func listFilters(ctx context.Context, mc zoho.MailService) ([]zoho.MailFilter, error) {
+	filters, err := mc.ListFilters(ctx)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to list filters: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return filters, nil
+}
+
+// resolveFilter finds a filter by name or ID
+func resolveFilter(ctx context.Context, mc zoho.MailService, nameOrID string) (*zoho.MailFilter, error) {
+	filters, err := listFilters(ctx, mc)
+	if err != nil {
+		return nil, err
+	}
+	if f := findFilter(filters, nameOrID); f != nil {
+		return f, nil
+	}
+	return nil, filterNotFound(nameOrID)
+}
+
+// findFilter returns the filter with the given ID or name (case-insensitive), or nil
+func findFilter(filters []zoho.MailFilter, nameOrID string) *zoho.MailFilter {
+	for i := range filters {
+		if filters[i].FilterID == nameOrID {
+			return &filters[i]
+		}
+	}
+	for i := range filters {
+		if strings.EqualFold(filters[i].FilterName, nameOrID) {
+			return &filters[i]
+		}
+	}
+	return nil
+}
+
+// filterNotFound reports an unknown filter
+func filterNotFound(nameOrID string) error {
+	return (&output.CLIError{
+		Message:  fmt.Sprintf("Filter not found: %s", nameOrID),
+		ExitCode: output.ExitNotFound,
+	}).WithHint("Run 'zoh mail settings filters list' to see filters")
+}
+
+// filterNames returns the filters' names
+func filterNames(filters []zoho.MailFilter) []string {
+	names := make([]string, len(filters))
+	for i, f := range filters {
+		names[i] = f.FilterName
+	}
+	return names
+}
+
+// filterIDs returns the filters' IDs
+func filterIDs(filters []zoho.MailFilter) []string {
+	ids := make([]string, len(filters))
+	for i, f := range filters {
+		ids[i] = f.FilterID
+	}
+	return ids
+}
+
+// describeFilterCondition renders a condition as the --condition flag takes it
+func describeFilterCondition(c zoho.FilterCondition) string {
+	return fmt.Sprintf("%s %s %s", c.Field, c.Comparison, c.Value)
+}
+
+// describeFilterAction renders an action as the --action flag takes it
+func describeFilterAction(a zoho.FilterAction) string {
+	if a.ActionValue == "" {
+		return a.ActionType
+	}
+	return a.ActionType + " " + a.ActionValue
+}
+
+// filterJSON renders a filter as the lines filters export writes for it
+func filterJSON(f zoho.MailFilter) []string {
+	data, _ := json.MarshalIndent(f, "", "  ")
+	return strings.Split(string(data), "\n")
+}
+
+// diffLines returns the lines removed from a ("- ") and added in b ("+ "),
+// in order, or nil when they are equal
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	return diff
+}
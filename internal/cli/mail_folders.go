@@ -3,8 +3,12 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 
 	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
 )
 
 
@@ -68,3 +72,392 @@ func (cmd *MailLabelsListCmd) Run(sp *ServiceProvider, fp *FormatterProvider) er
 
 	return fp.Formatter.PrintList(labels, columns)
 }
+
+// findFolderByPath finds a folder by its full path (case-insensitive, leading slash optional)
+func findFolderByPath(folders []zoho.Folder, path string) *zoho.Folder {
+	path = "/" + strings.Trim(path, "/")
+	for i := range folders {
+		if strings.EqualFold(folders[i].Path, path) {
+			return &folders[i]
+		}
+	}
+	return nil
+}
+
+// MailFoldersCreateCmd creates a folder, including any missing parent folders
+type MailFoldersCreateCmd struct {
+	Path string `arg:"" help:"Folder path to create (e.g. Projects/2025/Q1)"`
+}
+
+// Run executes the create folder command
+func (cmd *MailFoldersCreateCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	var segments []string
+	for _, segment := range strings.Split(cmd.Path, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return &output.CLIError{
+			Message:  "Folder path must not be empty",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would create folder (and missing parents): /%s\n", strings.Join(segments, "/"))
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	folders, err := mailClient.ListFolders(ctx)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch folders: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Walk the path, creating each missing folder under its parent
+	var folder *zoho.Folder
+	path, parentID, created := "", "", false
+	for _, segment := range segments {
+		path += "/" + segment
+		if existing := findFolderByPath(folders, path); existing != nil {
+			folder, parentID = existing, existing.FolderID
+			continue
+		}
+
+		folder, err = mailClient.CreateFolder(ctx, segment, parentID)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to create folder %s: %v", path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		parentID, created = folder.FolderID, true
+		fmt.Fprintf(os.Stderr, "Folder created: %s\n", path)
+	}
+
+	if !created {
+		fmt.Fprintf(os.Stderr, "Folder already exists: %s\n", path)
+	}
+
+	return fp.Formatter.Print(*folder)
+}
+
+// MailFoldersRenameCmd renames a folder
+type MailFoldersRenameCmd struct {
+	Folder  string `arg:"" help:"Folder name, path or ID"`
+	NewName string `arg:"" help:"New folder name"`
+}
+
+// Run executes the rename folder command
+func (cmd *MailFoldersRenameCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	if strings.Contains(cmd.NewName, "/") {
+		return &output.CLIError{
+			Message:  "New folder name must not contain \"/\" (use move to change the parent)",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would rename folder %s to %s\n", cmd.Folder, cmd.NewName)
+		return nil
+	}
+
+	return runFolderAction(sp, cmd.Folder, "rename", func(ctx context.Context, mc zoho.MailService, folderID string) error {
+		return mc.RenameFolder(ctx, folderID, cmd.NewName)
+	}, fmt.Sprintf("Folder renamed: %s -> %s", cmd.Folder, cmd.NewName))
+}
+
+// MailFoldersMoveCmd moves a folder under another folder
+type MailFoldersMoveCmd struct {
+	Folder string `arg:"" help:"Folder name, path or ID"`
+	To     string `help:"New parent folder name, path or ID" required:""`
+}
+
+// Run executes the move folder command
+func (cmd *MailFoldersMoveCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would move folder %s under %s\n", cmd.Folder, cmd.To)
+		return nil
+	}
+
+	return runFolderAction(sp, cmd.Folder, "move", func(ctx context.Context, mc zoho.MailService, folderID string) error {
+		parentID, err := resolveFolderID(ctx, mc, cmd.To)
+		if err != nil {
+			return err
+		}
+		return mc.MoveFolder(ctx, folderID, parentID)
+	}, fmt.Sprintf("Folder moved: %s -> %s", cmd.Folder, cmd.To))
+}
+
+// MailFoldersEmptyCmd permanently deletes every message in a folder
+type MailFoldersEmptyCmd struct {
+	Folder  string `arg:"" help:"Folder name, path or ID (e.g. Trash or Spam)"`
+	Confirm bool   `help:"Confirm permanent deletion of the folder's messages"`
+}
+
+// Run executes the empty folder command
+func (cmd *MailFoldersEmptyCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement (unless --force or --dry-run)
+	if !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Emptying a folder requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would permanently delete every message in folder: %s\n", cmd.Folder)
+		return nil
+	}
+
+	return runFolderAction(sp, cmd.Folder, "empty", func(ctx context.Context, mc zoho.MailService, folderID string) error {
+		return mc.EmptyFolder(ctx, folderID)
+	}, fmt.Sprintf("Folder emptied: %s", cmd.Folder))
+}
+
+// MailFoldersDeleteCmd deletes a folder with its subfolders and messages
+type MailFoldersDeleteCmd struct {
+	Folder  string `arg:"" help:"Folder name, path or ID"`
+	Confirm bool   `help:"Confirm deletion of the folder and its messages"`
+}
+
+// Run executes the delete folder command
+func (cmd *MailFoldersDeleteCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement (unless --force or --dry-run)
+	if !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Deletion requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would delete folder and its messages: %s\n", cmd.Folder)
+		return nil
+	}
+
+	return runFolderAction(sp, cmd.Folder, "delete", func(ctx context.Context, mc zoho.MailService, folderID string) error {
+		return mc.DeleteFolder(ctx, folderID)
+	}, fmt.Sprintf("Folder deleted: %s", cmd.Folder))
+}
+
+// MailFoldersMarkAllReadCmd marks every message in a folder as read
+type MailFoldersMarkAllReadCmd struct {
+	Folder string `arg:"" help:"Folder name, path or ID"`
+}
+
+// Run executes the mark-all-read command
+func (cmd *MailFoldersMarkAllReadCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would mark every message as read in folder: %s\n", cmd.Folder)
+		return nil
+	}
+
+	return runFolderAction(sp, cmd.Folder, "mark all messages as read in", func(ctx context.Context, mc zoho.MailService, folderID string) error {
+		return mc.MarkFolderRead(ctx, folderID)
+	}, fmt.Sprintf("All messages marked as read: %s", cmd.Folder))
+}
+
+// runFolderAction resolves a folder and applies an action to it, printing done to stderr on success
+func runFolderAction(sp *ServiceProvider, folder, verb string, action func(ctx context.Context, mc zoho.MailService, folderID string) error, done string) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	folderID, err := resolveFolderID(ctx, mailClient, folder)
+	if err != nil {
+		return err
+	}
+
+	if err := action(ctx, mailClient, folderID); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to %s folder: %v", verb, err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintln(os.Stderr, done)
+	return nil
+}
+
+// resolveLabelID resolves a label name to label ID, fallback to treating input as ID
+func resolveLabelID(ctx context.Context, mc zoho.MailService, labelNameOrID string) (string, error) {
+	labels, err := mc.ListLabels(ctx)
+	if err != nil {
+		return "", &output.CLIError{
+			Message:  fmt.Sprintf("Failed to list labels: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	for _, label := range labels {
+		if strings.EqualFold(label.LabelName, labelNameOrID) {
+			return label.LabelID, nil
+		}
+	}
+	return labelNameOrID, nil
+}
+
+// labelColorPattern matches label colors in #RRGGBB form
+var labelColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// validateLabelColor checks a label color is in #RRGGBB form
+func validateLabelColor(color string) error {
+	if !labelColorPattern.MatchString(color) {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Invalid label color %q (use #RRGGBB)", color),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	return nil
+}
+
+// MailLabelsCreateCmd creates a label
+type MailLabelsCreateCmd struct {
+	Name  string `arg:"" help:"Label name"`
+	Color string `help:"Label color (#RRGGBB)"`
+}
+
+// Run executes the create label command
+func (cmd *MailLabelsCreateCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	if cmd.Color != "" {
+		if err := validateLabelColor(cmd.Color); err != nil {
+			return err
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would create label: %s\n", cmd.Name)
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	label, err := mailClient.CreateLabel(context.Background(), cmd.Name, cmd.Color)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to create label: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintf(os.Stderr, "Label created: %s\n", label.LabelName)
+
+	return fp.Formatter.Print(*label)
+}
+
+// MailLabelsRenameCmd renames a label
+type MailLabelsRenameCmd struct {
+	Label   string `arg:"" help:"Label name or ID"`
+	NewName string `arg:"" help:"New label name"`
+}
+
+// Run executes the rename label command
+func (cmd *MailLabelsRenameCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would rename label %s to %s\n", cmd.Label, cmd.NewName)
+		return nil
+	}
+
+	return runLabelAction(sp, cmd.Label, "rename", func(ctx context.Context, mc zoho.MailService, labelID string) error {
+		return mc.UpdateLabel(ctx, labelID, cmd.NewName, "")
+	}, fmt.Sprintf("Label renamed: %s -> %s", cmd.Label, cmd.NewName))
+}
+
+// MailLabelsRecolorCmd changes the color of a label
+type MailLabelsRecolorCmd struct {
+	Label string `arg:"" help:"Label name or ID"`
+	Color string `arg:"" help:"New label color (#RRGGBB)"`
+}
+
+// Run executes the recolor label command
+func (cmd *MailLabelsRecolorCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	if err := validateLabelColor(cmd.Color); err != nil {
+		return err
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would recolor label %s to %s\n", cmd.Label, cmd.Color)
+		return nil
+	}
+
+	return runLabelAction(sp, cmd.Label, "recolor", func(ctx context.Context, mc zoho.MailService, labelID string) error {
+		return mc.UpdateLabel(ctx, labelID, "", cmd.Color)
+	}, fmt.Sprintf("Label recolored: %s -> %s", cmd.Label, cmd.Color))
+}
+
+// MailLabelsDeleteCmd deletes a label
+type MailLabelsDeleteCmd struct {
+	Label   string `arg:"" help:"Label name or ID"`
+	Confirm bool   `help:"Confirm deletion"`
+}
+
+// Run executes the delete label command
+func (cmd *MailLabelsDeleteCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	// Check confirmation requirement (unless --force or --dry-run)
+	if !cmd.Confirm && !globals.Force && !globals.DryRun {
+		return &output.CLIError{
+			Message:  "Deletion requires --confirm or --force flag",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would delete label: %s\n", cmd.Label)
+		return nil
+	}
+
+	return runLabelAction(sp, cmd.Label, "delete", func(ctx context.Context, mc zoho.MailService, labelID string) error {
+		return mc.DeleteLabel(ctx, labelID)
+	}, fmt.Sprintf("Label deleted: %s", cmd.Label))
+}
+
+// runLabelAction resolves a label and applies an action to it, printing done to stderr on success
+func runLabelAction(sp *ServiceProvider, label, verb string, action func(ctx context.Context, mc zoho.MailService, labelID string) error, done string) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	labelID, err := resolveLabelID(ctx, mailClient, label)
+	if err != nil {
+		return err
+	}
+
+	if err := action(ctx, mailClient, labelID); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to %s label: %v", verb, err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	fmt.Fprintln(os.Stderr, done)
+	return nil
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/output"
+)
+
+// ImportSummary is a display struct for the result of an import
+type ImportSummary struct {
+	Folder   string
+	Format   string
+	Imported int
+	Skipped  int
+	Failed   int
+}
+
+// importState records which messages have been imported, so a re-run skips them
+type importState struct {
+	Imported map[string]string `json:"imported"` // Message-ID (or content hash) -> Zoho message ID
+}
+
+// archivedMessage is one message read from a local archive
+type archivedMessage struct {
+	source string // file, or file#index for mbox
+	raw    []byte
+	read   bool
+}
+
+// key identifies the message for checkpointing: its Message-ID, or a content
+// hash when the header is missing
+func (m *archivedMessage) key() string {
+	if parsed, err := mail.ReadMessage(bytes.NewReader(m.raw)); err == nil {
+		if id := strings.TrimSpace(parsed.Header.Get("Message-ID")); id != "" {
+			return id
+		}
+	}
+	sum := sha256.Sum256(m.raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// MailImportCmd imports messages from local archives into a folder
+type MailImportCmd struct {
+	Format  string `help:"Archive format" enum:"mbox,maildir,eml" default:"mbox"`
+	File    string `help:"mbox file, Maildir directory, or .eml file or directory" required:"" short:"F" predictor:"file"`
+	Folder  string `help:"Target folder name, path or ID" required:"" short:"f"`
+	State   string `help:"Checkpoint file used to skip already imported messages (default: <file>.import.json)" predictor:"file"`
+	Restart bool   `help:"Ignore the checkpoint and import every message again"`
+}
+
+// Run executes the import command
+func (cmd *MailImportCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	statePath := cmd.State
+	if statePath == "" {
+		statePath = strings.TrimRight(cmd.File, string(filepath.Separator)) + ".import.json"
+	}
+	state := &importState{Imported: make(map[string]string)}
+	if !cmd.Restart {
+		var err error
+		if state, err = loadImportState(statePath); err != nil {
+			return err
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		total, pending := 0, 0
+		err := walkArchive(cmd.Format, cmd.File, func(m *archivedMessage) error {
+			total++
+			if _, done := state.Imported[m.key()]; !done {
+				pending++
+			}
+			return nil
+		})
+		if err != nil {
+			return archiveError(cmd.File, err)
+		}
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would import %d message(s) into %s (%d already imported)\n", pending, cmd.Folder, total-pending)
+		return nil
+	}
+
+	// One client for the whole run, so its rate limiter paces every import
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	folder, err := resolveFolder(ctx, mailClient, cmd.Folder)
+	if err != nil {
+		if cliErr, ok := err.(*output.CLIError); ok && cliErr.ExitCode == output.ExitNotFound {
+			cliErr.Message += fmt.Sprintf(" (create it with: zoh mail folders create %q)", cmd.Folder)
+		}
+		return err
+	}
+
+	summary := ImportSummary{Folder: folder.Path, Format: cmd.Format}
+	err = walkArchive(cmd.Format, cmd.File, func(m *archivedMessage) error {
+		key := m.key()
+		if _, done := state.Imported[key]; done {
+			summary.Skipped++
+			return nil
+		}
+
+		id, err := mailClient.ImportMessage(ctx, folder.FolderID, m.raw, m.read)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to import %s: %v\n", m.source, err)
+			summary.Failed++
+			return nil
+		}
+
+		state.Imported[key] = id
+		summary.Imported++
+		return saveImportState(statePath, state)
+	})
+	if err != nil {
+		if _, ok := err.(*output.CLIError); ok {
+			return err
+		}
+		return archiveError(cmd.File, err)
+	}
+
+	if err := fp.Formatter.Print(summary); err != nil {
+		return err
+	}
+
+	if summary.Failed > 0 {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("%d message(s) failed to import; re-run the same command to retry them", summary.Failed),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// archiveError wraps an error reading a local archive
+func archiveError(path string, err error) error {
+	return &output.CLIError{
+		Message:  fmt.Sprintf("Failed to read %s: %v", path, err),
+		ExitCode: output.ExitGeneral,
+	}
+}
+
+// loadImportState reads the import checkpoint; a missing file means nothing was imported
+func loadImportState(path string) (*importState, error) {
+	state := &importState{Imported: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, state)
+	}
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read import checkpoint %s: %v (use --restart to ignore it)", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if state.Imported == nil {
+		state.Imported = make(map[string]string)
+	}
+	return state, nil
+}
+
+// saveImportState atomically writes the import checkpoint
+func saveImportState(path string, state *importState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = fileutil.WriteAtomic(path, append(data, '\n'))
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save import checkpoint %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return nil
+}
+
+// walkArchive calls visit for each message in a local archive, in archive order
+func walkArchive(format, path string, visit func(*archivedMessage) error) error {
+	switch format {
+	case "mbox":
+		return walkMbox(path, visit)
+	case "maildir":
+		return walkMaildir(path, visit)
+	default:
+		return walkEml(path, visit)
+	}
+}
+
+// walkMbox reads an mboxo/mboxrd file, undoing ">From " quoting
+func walkMbox(path string, visit func(*archivedMessage) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	index, inMessage := 0, false
+	flush := func() error {
+		if !inMessage {
+			return nil
+		}
+		// Drop the blank separator line that precedes the next From_ line
+		raw := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		m := &archivedMessage{source: fmt.Sprintf("%s#%d", path, index), raw: bytes.Clone(raw)}
+		m.read = headerRead(m.raw)
+		buf.Reset()
+		return visit(m)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if bytes.HasPrefix(line, []byte("From ")) {
+				if err := flush(); err != nil {
+					return err
+				}
+				index++
+				inMessage = true
+			} else if inMessage {
+				if unquoted := bytes.TrimLeft(line, ">"); len(unquoted) < len(line) && bytes.HasPrefix(unquoted, []byte("From ")) {
+					line = line[1:]
+				}
+				buf.Write(line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// walkMaildir reads the cur and new directories of a Maildir, oldest first.
+// Messages in cur carrying the S (seen) flag are imported as read.
+func walkMaildir(dir string, visit func(*archivedMessage) error) error {
+	var files []string
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				files = append(files, filepath.Join(sub, e.Name()))
+			}
+		}
+	}
+	// Maildir names start with the delivery time, so name order is roughly date order
+	sort.Slice(files, func(i, j int) bool { return filepath.Base(files[i]) < filepath.Base(files[j]) })
+
+	for _, name := range files {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		read := false
+		if _, info, ok := strings.Cut(filepath.Base(name), ":2,"); ok {
+			read = strings.Contains(info, "S")
+		}
+		if err := visit(&archivedMessage{source: filepath.Join(dir, name), raw: raw, read: read}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkEml reads a single .eml file, or every .eml file in a directory
+func walkEml(path string, visit func(*archivedMessage) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.eml")); err != nil {
+			return err
+		}
+		sort.Strings(files)
+	}
+
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := visit(&archivedMessage{source: file, raw: raw, read: headerRead(raw)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerRead reports whether a message's Status header marks it as read,
+// as written by mbox-based clients such as Thunderbird and mutt
+func headerRead(raw []byte) bool {
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(parsed.Header.Get("Status"), "R")
+}
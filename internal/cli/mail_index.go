@@ -0,0 +1,382 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/index"
+	"github.com/SeMmyT/zohcli/internal/mirror"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// indexSaveEvery is how many messages are indexed between index saves
+const indexSaveEvery = 25
+
+// IndexBuildSummary is a display struct for the result of an index build
+type IndexBuildSummary struct {
+	Folders int
+	Added   int
+	Removed int
+	Failed  int
+	Total   int
+	Path    string
+}
+
+// IndexResultRow is a display struct for offline search results
+type IndexResultRow struct {
+	Folder     string
+	From       string
+	Subject    string
+	Date       string
+	Attachment string
+	MessageID  string
+}
+
+// MailIndexCmd holds offline search index subcommands
+type MailIndexCmd struct {
+	Build  MailIndexBuildCmd  `cmd:"" help:"Fetch messages into the local search index"`
+	Search MailIndexSearchCmd `cmd:"" help:"Search the local index (no network)"`
+}
+
+// MailIndexBuildCmd adds messages to the local search index
+type MailIndexBuildCmd struct {
+	Folder  []string `help:"Folder name, path or ID to index (repeatable; default: every folder)" short:"f"`
+	Mirror  string   `help:"Index a local mirror written by 'mail sync' instead of fetching from Zoho" predictor:"file"`
+	Rebuild bool     `help:"Discard the existing index first"`
+}
+
+// Run executes the index build command
+func (cmd *MailIndexBuildCmd) Run(sp *ServiceProvider, fp *FormatterProvider, cfg *config.Config) error {
+	indexPath := index.Path(cfg.Profile)
+	ix := index.New()
+	if !cmd.Rebuild {
+		var err error
+		if ix, err = index.Load(indexPath); err != nil {
+			return &output.CLIError{
+				Message:  err.Error(),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+	}
+
+	summary := &IndexBuildSummary{Path: indexPath}
+	save := func() error {
+		if err := ix.Save(indexPath); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to save index: %v", err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if cmd.Mirror != "" {
+		err = cmd.fromMirror(ix, cfg, summary, save)
+	} else {
+		err = cmd.fromZoho(sp, ix, summary, save)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := save(); err != nil {
+		return err
+	}
+	summary.Total = len(ix.Docs)
+	return fp.Formatter.Print(*summary)
+}
+
+// fromZoho indexes the selected folders, fetching each new message's source once
+func (cmd *MailIndexBuildCmd) fromZoho(sp *ServiceProvider, ix *index.Index, summary *IndexBuildSummary, save func() error) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels in-flight requests; messages indexed so far are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var folders []zoho.Folder
+	if len(cmd.Folder) == 0 {
+		if folders, err = mailClient.ListFolders(ctx); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch folders: %v", err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+	} else {
+		for _, name := range cmd.Folder {
+			folder, err := resolveFolder(ctx, mailClient, name)
+			if err != nil {
+				return err
+			}
+			folders = append(folders, *folder)
+		}
+	}
+	summary.Folders = len(folders)
+
+	added := 0
+	for _, folder := range folders {
+		iterator := zoho.NewPageIterator(func(start, limit int) ([]zoho.MessageSummary, error) {
+			return mailClient.ListMessages(ctx, folder.FolderID, start, limit)
+		}, 200)
+		messages, err := iterator.FetchAll()
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+
+		listed := make(map[string]bool, len(messages))
+		for _, msg := range messages {
+			listed[msg.MessageID] = true
+			if ix.Has(msg.MessageID) {
+				// Already indexed; keep folder and read state current
+				doc := ix.Docs[msg.MessageID]
+				doc.FolderID, doc.Folder, doc.Unread = folder.FolderID, folder.Path, msg.Status == "0"
+				continue
+			}
+
+			raw, err := mailClient.GetOriginalMessage(ctx, folder.FolderID, msg.MessageID)
+			if err != nil {
+				if ctx.Err() != nil {
+					if saveErr := save(); saveErr != nil {
+						return saveErr
+					}
+					return &output.CLIError{
+						Message:  fmt.Sprintf("Indexing interrupted after %d new message(s); re-run to continue", summary.Added),
+						ExitCode: output.ExitGeneral,
+					}
+				}
+				fmt.Fprintf(os.Stderr, "Failed to fetch message %s: %v\n", msg.MessageID, err)
+				summary.Failed++
+				continue
+			}
+
+			doc, body, err := index.ParseDoc(raw)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to parse message %s: %v\n", msg.MessageID, err)
+				summary.Failed++
+				continue
+			}
+			doc.MessageID, doc.FolderID, doc.Folder = msg.MessageID, folder.FolderID, folder.Path
+			doc.ReceivedTime, _ = strconv.ParseInt(msg.ReceivedTime, 10, 64)
+			doc.Unread = msg.Status == "0"
+			ix.Add(doc, body)
+			summary.Added++
+
+			if added++; added%indexSaveEvery == 0 {
+				if err := save(); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Messages no longer listed in this folder were moved away or deleted
+		for id, doc := range ix.Docs {
+			if doc.FolderID == folder.FolderID && !listed[id] {
+				ix.Remove(id)
+				summary.Removed++
+			}
+		}
+	}
+	return nil
+}
+
+// fromMirror indexes the messages of a local mirror without any network access
+func (cmd *MailIndexBuildCmd) fromMirror(ix *index.Index, cfg *config.Config, summary *IndexBuildSummary, save func() error) error {
+	statePath, err := mirror.StatePath(cfg.Profile, cmd.Mirror)
+	if err == nil && !fileExists(statePath) {
+		err = fmt.Errorf("no sync state for this directory (run: zoh mail sync --out %s)", cmd.Mirror)
+	}
+	var state *mirror.State
+	if err == nil {
+		state, err = mirror.Load(statePath, cmd.Mirror)
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Cannot index mirror %s: %v", cmd.Mirror, err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	summary.Folders = len(state.Folders)
+
+	added := 0
+	for id, entry := range state.Messages {
+		if entry.DeletedAt != nil {
+			if ix.Has(id) {
+				ix.Remove(id)
+				summary.Removed++
+			}
+			continue
+		}
+
+		folderPath := ""
+		if f, ok := state.Folders[entry.FolderID]; ok {
+			folderPath = f.Path
+		}
+		if ix.Has(id) {
+			doc := ix.Docs[id]
+			doc.FolderID, doc.Folder = entry.FolderID, folderPath
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(cmd.Mirror, entry.File))
+		if err == nil {
+			var doc *index.Doc
+			var body string
+			if doc, body, err = index.ParseDoc(raw); err == nil {
+				doc.MessageID, doc.FolderID, doc.Folder, doc.ReceivedTime = id, entry.FolderID, folderPath, entry.ReceivedTime
+				ix.Add(doc, body)
+				summary.Added++
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to index %s: %v\n", entry.File, err)
+			summary.Failed++
+			continue
+		}
+
+		if added++; added%indexSaveEvery == 0 {
+			if err := save(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MailIndexSearchCmd searches the local index
+type MailIndexSearchCmd struct {
+	Query  string `arg:"" help:"Query (see mail messages search), e.g. 'from:alice has:attachment after:2025-01-01 invoice -paid'"`
+	Folder string `help:"Only results in this folder (path or name)" short:"f"`
+	Limit  int    `help:"Maximum results" short:"l" default:"50"`
+}
+
+// Run executes the index search command
+func (cmd *MailIndexSearchCmd) Run(fp *FormatterProvider, cfg *config.Config) error {
+	query, err := zoho.ParseSearch(cmd.Query)
+	if err != nil {
+		return searchSyntaxError(err)
+	}
+	if query == nil {
+		return &output.CLIError{
+			Message:  "Specify at least one search criterion",
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if term := unindexedTerm(query); term != nil {
+		return (&output.CLIError{
+			Message:  fmt.Sprintf("%s cannot be searched in the local index", term),
+			ExitCode: output.ExitUsage,
+		}).WithHint("Use 'zoh mail messages search' for label:, larger: and smaller:")
+	}
+
+	indexPath := index.Path(cfg.Profile)
+	if !fileExists(indexPath) {
+		return &output.CLIError{
+			Message:  "No local index yet (run: zoh mail index build)",
+			ExitCode: output.ExitNotFound,
+		}
+	}
+	ix, err := index.Load(indexPath)
+	if err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	// Folders are looked up among the indexed messages, without the network
+	err = zoho.ResolveSearch(query, func(field, name string) (string, error) {
+		for _, doc := range ix.Docs {
+			if doc.FolderID == name || folderMatches(doc.Folder, name) {
+				return doc.FolderID, nil
+			}
+		}
+		return name, nil
+	})
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Invalid query: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	rows := make([]IndexResultRow, 0)
+	for _, doc := range ix.Search(query) {
+		if cmd.Folder != "" && !folderMatches(doc.Folder, cmd.Folder) {
+			continue
+		}
+		attachment := ""
+		if doc.HasAttachment {
+			attachment = "Y"
+		}
+		rows = append(rows, IndexResultRow{
+			Folder:     doc.Folder,
+			From:       doc.From,
+			Subject:    doc.Subject,
+			Date:       formatReceivedTime(strconv.FormatInt(doc.ReceivedTime, 10)),
+			Attachment: attachment,
+			MessageID:  doc.MessageID,
+		})
+		if cmd.Limit > 0 && len(rows) == cmd.Limit {
+			break
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Folder", Key: "Folder"},
+		{Name: "From", Key: "From"},
+		{Name: "Subject", Key: "Subject"},
+		{Name: "Date", Key: "Date"},
+		{Name: "Attachment", Key: "Attachment"},
+		{Name: "ID", Key: "MessageID"},
+	}
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// unindexedTerm returns the first term of a query that the local index has
+// no data for, or nil
+func unindexedTerm(expr zoho.SearchExpr) *zoho.SearchTerm {
+	switch e := expr.(type) {
+	case *zoho.SearchTerm:
+		if e.Field == "label" || e.Field == "larger" || e.Field == "smaller" {
+			return e
+		}
+	case *zoho.SearchAnd:
+		for _, t := range e.Terms {
+			if term := unindexedTerm(t); term != nil {
+				return term
+			}
+		}
+	case *zoho.SearchOr:
+		for _, t := range e.Terms {
+			if term := unindexedTerm(t); term != nil {
+				return term
+			}
+		}
+	case *zoho.SearchNot:
+		return unindexedTerm(e.Term)
+	}
+	return nil
+}
+
+// folderMatches reports whether a folder path matches a folder path or name given by the user
+func folderMatches(folderPath, nameOrPath string) bool {
+	if strings.Contains(nameOrPath, "/") {
+		return strings.EqualFold(folderPath, "/"+strings.Trim(nameOrPath, "/"))
+	}
+	return strings.EqualFold(path.Base(folderPath), nameOrPath)
+}
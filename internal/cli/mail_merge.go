@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// MergeResultRow is a display struct for the per-row result of a mail merge
+type MergeResultRow struct {
+	Row     int
+	To      string
+	Subject string
+	Result  string
+	Error   string
+}
+
+// mergeState records which rows of a merge have been sent, so a re-run resumes
+type mergeState struct {
+	Sent map[string]string `json:"sent"` // message key (see mergeKey) -> RFC3339 time sent
+}
+
+// mergeMessage is one rendered row of a merge
+type mergeMessage struct {
+	row  int
+	key  string
+	mail *renderedMail
+}
+
+// MailSendMergeCmd sends one templated message per CSV row
+type MailSendMergeCmd struct {
+	Template string   `help:"Go template file (see compose --template); CSV columns are the variables" required:"" predictor:"file"`
+	CSV      string   `help:"Recipients CSV file with a header row" required:"" name:"csv" predictor:"file"`
+	ToColumn string   `help:"CSV column with the recipient address, used when the template has no To header" default:"email"`
+	Subject  string   `help:"Subject template (overrides the template's Subject header)"`
+	HTML     bool     `help:"Send as HTML (default: plain text)" name:"html"`
+	Attach   []string `help:"File path(s) to attach to every message (repeatable)" name:"attach" predictor:"file"`
+	State    string   `help:"Progress file used to resume after a partial failure (default: <csv>.state.json)" predictor:"file"`
+	Restart  bool     `help:"Ignore recorded progress and send to every row again"`
+}
+
+// Run executes the merge command
+func (cmd *MailSendMergeCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	tmpl, err := loadMailTemplate(cmd.Template, map[string]string{"subject": cmd.Subject}, cmd.HTML)
+	if err != nil {
+		return err
+	}
+
+	rows, err := readMergeCSV(cmd.CSV)
+	if err != nil {
+		return err
+	}
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+
+	// Render every row up front so template errors surface before anything is sent
+	messages := make([]mergeMessage, len(rows))
+	occurrences := make(map[string]int)
+	for i, vars := range rows {
+		mail, err := tmpl.render(vars)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to render row %d: %v", i+1, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		if mail.To == "" {
+			mail.To = vars[cmd.ToColumn]
+		}
+		if mail.To == "" {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Row %d has no recipient (set a To header or fill the %q column)", i+1, cmd.ToColumn),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		if mail.Subject == "" {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Row %d has no subject (set a Subject header or --subject)", i+1),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		key := mergeKey(mail)
+		occurrences[key]++
+		if n := occurrences[key]; n > 1 {
+			key += fmt.Sprintf("#%d", n)
+		}
+		messages[i] = mergeMessage{row: i + 1, key: key, mail: mail}
+	}
+
+	statePath := cmd.State
+	if statePath == "" {
+		statePath = cmd.CSV + ".state.json"
+	}
+	state := &mergeState{Sent: make(map[string]string)}
+	if !cmd.Restart {
+		if state, err = loadMergeState(statePath); err != nil {
+			return err
+		}
+	}
+
+	pending := 0
+	for _, m := range messages {
+		if _, done := state.Sent[m.key]; !done {
+			pending++
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would send %d message(s) (%d already sent)\n", pending, len(messages)-pending)
+		for _, m := range messages {
+			if _, done := state.Sent[m.key]; !done {
+				fmt.Fprintf(os.Stderr, "  row %d: %s - %s\n", m.row, m.mail.To, m.mail.Subject)
+			}
+		}
+		return nil
+	}
+
+	// One client for the whole run, so its rate limiter paces every send
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Attachments are uploaded once and shared by every message
+	var attachments []zoho.AttachmentReference
+	if pending > 0 {
+		if attachments, err = uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals)); err != nil {
+			return err
+		}
+	}
+
+	results := make([]MergeResultRow, len(messages))
+	sent, failed := 0, 0
+	for i, m := range messages {
+		results[i] = MergeResultRow{Row: m.row, To: m.mail.To, Subject: m.mail.Subject}
+		if _, done := state.Sent[m.key]; done {
+			results[i].Result = "skipped"
+			continue
+		}
+
+		req := &zoho.SendEmailRequest{
+			ToAddress:   m.mail.To,
+			CcAddress:   m.mail.Cc,
+			BccAddress:  m.mail.Bcc,
+			Subject:     m.mail.Subject,
+			Content:     m.mail.Body,
+			MailFormat:  mailFormat(cmd.HTML),
+			Attachments: attachments,
+		}
+		if err := mailClient.SendEmail(ctx, req); err != nil {
+			results[i].Result = "failed"
+			results[i].Error = err.Error()
+			failed++
+			continue
+		}
+
+		results[i].Result = "sent"
+		sent++
+		state.Sent[m.key] = time.Now().UTC().Format(time.RFC3339)
+		if err := saveMergeState(statePath, state); err != nil {
+			return err
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Row", Key: "Row"},
+		{Name: "To", Key: "To"},
+		{Name: "Subject", Key: "Subject", Width: 40},
+		{Name: "Result", Key: "Result"},
+		{Name: "Error", Key: "Error"},
+	}
+	if err := fp.Formatter.PrintList(results, columns); err != nil {
+		return err
+	}
+
+	// Print summary to stderr
+	fmt.Fprintf(os.Stderr, "%d sent, %d skipped, %d failed\n", sent, len(messages)-sent-failed, failed)
+
+	if failed > 0 {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("%d message(s) failed; re-run the same command to retry them", failed),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// mergeKey identifies a rendered message by its recipient and content rather
+// than its row, so rows added, removed or reordered between runs never make a
+// re-run send a message twice. Identical rows are told apart by a "#n"
+// suffix on the key of each repeat.
+func mergeKey(mail *renderedMail) string {
+	sum := sha256.Sum256([]byte(mail.Subject + "\x00" + mail.Body))
+	return mail.To + ":" + hex.EncodeToString(sum[:])
+}
+
+// readMergeCSV reads a CSV file with a header row into one map per data row
+func readMergeCSV(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to open %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid CSV %s: %v", path, err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if len(records) < 2 {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("%s needs a header row and at least one data row", path),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// loadMergeState reads recorded merge progress; a missing file means no progress
+func loadMergeState(path string) (*mergeState, error) {
+	state := &mergeState{Sent: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, state)
+	}
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read merge progress %s: %v (use --restart to ignore it)", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if state.Sent == nil {
+		state.Sent = make(map[string]string)
+	}
+	return state, nil
+}
+
+// saveMergeState atomically writes merge progress
+func saveMergeState(path string, state *mergeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = fileutil.WriteAtomic(path, append(data, '\n'))
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save merge progress %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return nil
+}
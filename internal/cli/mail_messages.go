@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"regexp"
+	"path/filepath"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/htmltext"
 	"github.com/SeMmyT/zohcli/internal/output"
 	"github.com/SeMmyT/zohcli/internal/zoho"
 )
@@ -42,13 +44,14 @@ type MessageDetail struct {
 
 // MailMessagesListCmd lists messages in a folder
 type MailMessagesListCmd struct {
-	Folder string `help:"Folder name or ID" default:"Inbox" short:"f"`
+	Folder string `help:"Folder name or ID, or @name for a saved search" default:"Inbox" short:"f"`
 	Limit  int    `help:"Maximum messages to show" short:"l" default:"50"`
 	All    bool   `help:"Fetch all messages (no pagination limit)" short:"a"`
+	Label  string `help:"Only show messages with this label (name or ID)"`
 }
 
 // Run executes the list messages command
-func (cmd *MailMessagesListCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+func (cmd *MailMessagesListCmd) Run(sp *ServiceProvider, fp *FormatterProvider, cfg *config.Config) error {
 	mailClient, err := sp.Mail()
 	if err != nil {
 		return err
@@ -56,19 +59,43 @@ func (cmd *MailMessagesListCmd) Run(sp *ServiceProvider, fp *FormatterProvider)
 
 	ctx := context.Background()
 
-	// Resolve folder name to folder ID
-	var folderID string
-	folder, err := mailClient.GetFolderByName(ctx, cmd.Folder)
+	src, err := resolveMessageSource(ctx, mailClient, cfg.Profile, cmd.Folder)
 	if err != nil {
-		// If GetFolderByName fails, treat cmd.Folder as a folder ID
-		folderID = cmd.Folder
-	} else {
-		folderID = folder.FolderID
+		return err
 	}
 
+	// A saved search stands in for a folder
+	if src.saved != nil {
+		var label zoho.SearchExpr
+		if cmd.Label != "" {
+			label, _ = zoho.ParseSearch((&zoho.SearchTerm{Field: "label", Value: cmd.Label}).String())
+		}
+		limit := cmd.Limit
+		if cmd.All {
+			limit = 0
+		}
+		messages, err := runSavedSearch(ctx, mailClient, src.saved, label, limit)
+		if err != nil {
+			return err
+		}
+		return printMessageList(fp, messages)
+	}
+	folderID := src.FolderID
+
 	var messages []zoho.MessageSummary
 
-	if cmd.All {
+	if cmd.Label != "" {
+		limit := cmd.Limit
+		if cmd.All {
+			limit = 0
+		}
+		messages, err = fetchLabeled(ctx, mailClient, cmd.Label, limit, func(start, limit int) ([]zoho.MessageSummary, error) {
+			return mailClient.ListMessages(ctx, folderID, start, limit)
+		})
+		if err != nil {
+			return err
+		}
+	} else if cmd.All {
 		// Use PageIterator to fetch all messages
 		iterator := zoho.NewPageIterator(func(start, limit int) ([]zoho.MessageSummary, error) {
 			return mailClient.ListMessages(ctx, folderID, start, limit)
@@ -129,8 +156,9 @@ func (cmd *MailMessagesListCmd) Run(sp *ServiceProvider, fp *FormatterProvider)
 
 // MailMessagesGetCmd gets full details for a specific message
 type MailMessagesGetCmd struct {
-	MessageID string `arg:"" help:"Message ID to retrieve"`
-	Folder    string `help:"Folder name or ID (required)" short:"f" required:""`
+	MessageID  string `arg:"" help:"Message ID to retrieve"`
+	Folder     string `help:"Folder name or ID (required)" short:"f" required:""`
+	BodyFormat string `help:"Body format (default: html with --output json, text otherwise)" enum:"html,text,markdown," default:""`
 }
 
 // Run executes the get message command
@@ -183,7 +211,7 @@ func (cmd *MailMessagesGetCmd) Run(sp *ServiceProvider, fp *FormatterProvider, g
 		MessageID:     metadata.MessageID,
 		ThreadID:      metadata.ThreadID,
 		FolderID:      metadata.FolderID,
-		Body:          formatBody(content.Content, globals.ResolvedOutput()),
+		Body:          formatBody(content.Content, cmd.BodyFormat, globals.ResolvedOutput()),
 	}
 
 	return fp.Formatter.Print(detail)
@@ -253,136 +281,88 @@ func formatStringPriority(s string) string {
 	}
 }
 
-// formatBody processes HTML content based on output mode
-func formatBody(htmlContent, outputMode string) string {
-	if outputMode == "json" {
-		// JSON mode returns raw HTML
-		return htmlContent
+// formatBody renders HTML content in bodyFormat; without one, JSON mode
+// keeps the raw HTML and plain and rich modes render text
+func formatBody(htmlContent, bodyFormat, outputMode string) string {
+	if bodyFormat == "" {
+		bodyFormat = "text"
+		if outputMode == "json" {
+			bodyFormat = "html"
+		}
 	}
 
-	// Plain and rich modes: strip HTML tags
-	re := regexp.MustCompile("<[^>]*>")
-	stripped := re.ReplaceAllString(htmlContent, "")
-	return strings.TrimSpace(stripped)
+	switch bodyFormat {
+	case "html":
+		return htmlContent
+	case "markdown":
+		return htmltext.Markdown(htmlContent)
+	default:
+		return htmltext.Text(htmlContent)
+	}
 }
 
-// MailMessagesSearchCmd searches for messages using query filters
-type MailMessagesSearchCmd struct {
-	Query         string `arg:"" optional:"" help:"Free-text search query"`
+// MessageFilterFlags holds the search filters shared by search and apply
+type MessageFilterFlags struct {
 	From          string `help:"Filter by sender email" short:"f"`
 	Subject       string `help:"Filter by subject" short:"s"`
 	After         string `help:"Messages after date (YYYY-MM-DD)" short:"a"`
 	Before        string `help:"Messages before date (YYYY-MM-DD)" short:"b"`
 	Unread        bool   `help:"Only unread messages" short:"u"`
 	HasAttachment bool   `help:"Only messages with attachments"`
-	Limit         int    `help:"Maximum results" short:"l" default:"50"`
+}
+
+// MailMessagesSearchCmd searches for messages using query filters
+type MailMessagesSearchCmd struct {
+	Query string `arg:"" optional:"" help:"Search query, e.g. 'from:a OR from:b -subject:digest in:Support larger:5M is:flagged'"`
+	MessageFilterFlags
+	Label string `help:"Only show messages with this label (name or ID)"`
+	Limit int    `help:"Maximum results" short:"l" default:"50"`
 }
 
 // Run executes the search messages command
-func (cmd *MailMessagesSearchCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
-	mailClient, err := sp.Mail()
+func (cmd *MailMessagesSearchCmd) Run(sp *ServiceProvider, fp *FormatterProvider, cfg *config.Config) error {
+	expr, err := cmd.searchExpr(cmd.Query)
 	if err != nil {
 		return err
 	}
-
-	ctx := context.Background()
-
-	// Build search query from flags
-	sq := zoho.NewSearchQuery()
-
-	if cmd.From != "" {
-		sq.From(cmd.From)
-	}
-	if cmd.Subject != "" {
-		sq.Subject(cmd.Subject)
-	}
-	if cmd.After != "" {
-		afterDate, err := time.Parse("2006-01-02", cmd.After)
-		if err != nil {
-			return &output.CLIError{
-				Message:  fmt.Sprintf("Invalid after date format (use YYYY-MM-DD): %v", err),
-				ExitCode: output.ExitUsage,
-			}
-		}
-		sq.DateAfter(afterDate)
-	}
-	if cmd.Before != "" {
-		beforeDate, err := time.Parse("2006-01-02", cmd.Before)
-		if err != nil {
-			return &output.CLIError{
-				Message:  fmt.Sprintf("Invalid before date format (use YYYY-MM-DD): %v", err),
-				ExitCode: output.ExitUsage,
-			}
-		}
-		sq.DateBefore(beforeDate)
-	}
-	if cmd.Unread {
-		sq.IsUnread()
-	}
-	if cmd.HasAttachment {
-		sq.HasAttachment()
-	}
-	if cmd.Query != "" {
-		sq.Text(cmd.Query)
+	if expr, err = expandSavedSearches(cfg.Profile, expr); err != nil {
+		return err
 	}
-
-	// Verify at least one search criterion
-	if sq.IsEmpty() {
-		return &output.CLIError{
-			Message:  "Specify at least one search criterion",
-			ExitCode: output.ExitUsage,
-		}
+	if cmd.Label != "" {
+		label, _ := zoho.ParseSearch((&zoho.SearchTerm{Field: "label", Value: cmd.Label}).String())
+		expr = &zoho.SearchAnd{Terms: []zoho.SearchExpr{expr, label}}
 	}
 
-	// Execute search
-	messages, err := mailClient.SearchMessages(ctx, sq.Build(), 1, cmd.Limit)
+	mailClient, err := sp.Mail()
 	if err != nil {
-		return &output.CLIError{
-			Message:  fmt.Sprintf("Failed to search messages: %v", err),
-			ExitCode: output.ExitAPIError,
-		}
+		return err
 	}
 
-	// Convert to display rows with formatted fields
-	rows := make([]MessageListRow, len(messages))
-	for i, msg := range messages {
-		timestamp := formatReceivedTime(msg.ReceivedTime)
-		attachment := ""
-		if msg.HasAttachment == "1" || msg.HasAttachment == "true" {
-			attachment = "Y"
-		}
+	ctx := context.Background()
 
-		rows[i] = MessageListRow{
-			Status:      msg.Status,
-			FromAddress: msg.FromAddress,
-			Subject:     msg.Subject,
-			Date:        timestamp,
-			Attachment:  attachment,
-			MessageID:   msg.MessageID,
-		}
+	// Execute search
+	plan, err := compileSearch(ctx, mailClient, expr)
+	if err != nil {
+		return err
 	}
-
-	columns := []output.Column{
-		{Name: "Status", Key: "Status"},
-		{Name: "From", Key: "FromAddress"},
-		{Name: "Subject", Key: "Subject"},
-		{Name: "Date", Key: "Date"},
-		{Name: "Attachment", Key: "Attachment"},
-		{Name: "ID", Key: "MessageID"},
+	messages, err := searchMessages(ctx, mailClient, plan, cmd.Limit)
+	if err != nil {
+		return err
 	}
 
-	return fp.Formatter.PrintList(rows, columns)
+	return printMessageList(fp, messages)
 }
 
 // MailMessagesThreadCmd shows all messages in a thread
 type MailMessagesThreadCmd struct {
 	ThreadID string `arg:"" help:"Thread ID to view"`
-	Folder   string `help:"Folder name or ID" default:"Inbox" short:"f"`
+	Folder   string `help:"Folder name or ID, or @name for a saved search" default:"Inbox" short:"f"`
 	Limit    int    `help:"Maximum messages to scan" short:"l" default:"200"`
+	Full     bool   `help:"Fetch every message's body and show the thread as a conversation"`
 }
 
 // Run executes the thread view command
-func (cmd *MailMessagesThreadCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+func (cmd *MailMessagesThreadCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals, cfg *config.Config) error {
 	mailClient, err := sp.Mail()
 	if err != nil {
 		return err
@@ -390,18 +370,25 @@ func (cmd *MailMessagesThreadCmd) Run(sp *ServiceProvider, fp *FormatterProvider
 
 	ctx := context.Background()
 
-	// Resolve folder name to folder ID
-	var folderID string
-	folder, err := mailClient.GetFolderByName(ctx, cmd.Folder)
+	src, err := resolveMessageSource(ctx, mailClient, cfg.Profile, cmd.Folder)
 	if err != nil {
-		// If GetFolderByName fails, treat cmd.Folder as a folder ID
-		folderID = cmd.Folder
-	} else {
-		folderID = folder.FolderID
+		return err
 	}
 
-	// Get thread messages
-	messages, err := mailClient.GetThread(ctx, folderID, cmd.ThreadID, cmd.Limit)
+	// Get thread messages; a saved search's matches are scanned instead of a folder
+	var messages []zoho.MessageSummary
+	if src.saved != nil {
+		var scanned []zoho.MessageSummary
+		if scanned, err = src.list(ctx, mailClient, 0, cmd.Limit); err == nil {
+			for _, msg := range scanned {
+				if msg.ThreadID == cmd.ThreadID {
+					messages = append(messages, msg)
+				}
+			}
+		}
+	} else {
+		messages, err = mailClient.GetThread(ctx, src.FolderID, cmd.ThreadID, cmd.Limit)
+	}
 	if err != nil {
 		return &output.CLIError{
 			Message:  fmt.Sprintf("Failed to fetch thread: %v", err),
@@ -409,6 +396,10 @@ func (cmd *MailMessagesThreadCmd) Run(sp *ServiceProvider, fp *FormatterProvider
 		}
 	}
 
+	if cmd.Full {
+		return printConversation(ctx, mailClient, fp, src, messages, globals.ResolvedOutput())
+	}
+
 	// Convert to display rows
 	rows := make([]MessageListRow, len(messages))
 	for i, msg := range messages {
@@ -512,7 +503,7 @@ type MailAttachmentsDownloadCmd struct {
 }
 
 // Run executes the download attachment command
-func (cmd *MailAttachmentsDownloadCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+func (cmd *MailAttachmentsDownloadCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
 	mailClient, err := sp.Mail()
 	if err != nil {
 		return err
@@ -562,8 +553,10 @@ func (cmd *MailAttachmentsDownloadCmd) Run(sp *ServiceProvider, fp *FormatterPro
 		}
 	}
 
-	// Download attachment
-	err = mailClient.DownloadAttachment(ctx, folderID, cmd.MessageID, cmd.AttachmentID, destPath)
+	// Download attachment; an interrupted download resumes from destPath.part on the next run
+	downloadCtx, finish := withProgress(ctx, showProgress(globals), "Downloading "+filepath.Base(destPath))
+	err = mailClient.DownloadAttachment(downloadCtx, folderID, cmd.MessageID, cmd.AttachmentID, destPath)
+	finish()
 	if err != nil {
 		return &output.CLIError{
 			Message:  fmt.Sprintf("Failed to download attachment: %v", err),
@@ -580,3 +573,37 @@ func (cmd *MailAttachmentsDownloadCmd) Run(sp *ServiceProvider, fp *FormatterPro
 
 	return nil
 }
+
+// fetchLabeled pages through fetch and keeps messages carrying the given label,
+// stopping once limit matches are found (0 = no limit)
+func fetchLabeled(ctx context.Context, mc zoho.MailService, label string, limit int, fetch func(start, limit int) ([]zoho.MessageSummary, error)) ([]zoho.MessageSummary, error) {
+	labelID, err := resolveLabelID(ctx, mc, label)
+	if err != nil {
+		return nil, err
+	}
+
+	const pageSize = 200
+	var matches []zoho.MessageSummary
+	for start := 0; ; start += pageSize {
+		page, err := fetch(start, pageSize)
+		if err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch messages: %v", err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+
+		for _, msg := range page {
+			if slices.Contains(msg.LabelIDs, labelID) {
+				matches = append(matches, msg)
+				if limit > 0 && len(matches) == limit {
+					return matches, nil
+				}
+			}
+		}
+
+		if len(page) < pageSize {
+			return matches, nil
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// MailSendRawCmd sends an RFC 5322 message, such as an .eml file
+type MailSendRawCmd struct {
+	File  string `help:"Message file (.eml); reads stdin if omitted or \"-\"" short:"F" predictor:"file"`
+	Draft bool   `help:"Save as a draft instead of sending"`
+	ScheduleFlags
+}
+
+// Run executes the raw send command
+func (cmd *MailSendRawCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	sendAt, err := cmd.schedule(time.Now(), cmd.Draft)
+	if err != nil {
+		return err
+	}
+
+	data, err := readRawMessage(cmd.File, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	raw, err := zoho.ParseRawMessage(bytes.NewReader(data))
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Invalid message: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	for _, w := range raw.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	req := &raw.Request
+	if req.ToAddress == "" && !cmd.Draft {
+		return &output.CLIError{
+			Message:  "Message has no To header",
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		if cmd.Draft {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would save draft:\n")
+		} else {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would send email:\n")
+		}
+		fmt.Fprintf(os.Stderr, "  To: %s\n", req.ToAddress)
+		if req.CcAddress != "" {
+			fmt.Fprintf(os.Stderr, "  Cc: %s\n", req.CcAddress)
+		}
+		if req.BccAddress != "" {
+			fmt.Fprintf(os.Stderr, "  Bcc: %s\n", req.BccAddress)
+		}
+		fmt.Fprintf(os.Stderr, "  Subject: %s\n", req.Subject)
+		fmt.Fprintf(os.Stderr, "  Format: %s\n", req.MailFormat)
+		if len(raw.Parts) > 0 {
+			fmt.Fprintf(os.Stderr, "  Parts: %d attachment(s)/inline image(s)\n", len(raw.Parts))
+		}
+		if !sendAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "  Send at: %s\n", formatScheduled(sendAt))
+		}
+		return nil
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	if err := uploadRawParts(ctx, mailClient, req, raw.Parts, showProgress(globals)); err != nil {
+		return err
+	}
+
+	if cmd.Draft {
+		return saveDraft(ctx, mailClient, fp, "", req)
+	}
+	if !sendAt.IsZero() {
+		req.Schedule(sendAt)
+	}
+
+	if err := mailClient.SendEmail(ctx, req); err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to send email: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	// Print confirmation to stderr
+	if !sendAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "Email to %s scheduled for %s\n", req.ToAddress, formatScheduled(sendAt))
+	} else {
+		fmt.Fprintf(os.Stderr, "Email sent to %s\n", req.ToAddress)
+	}
+	return nil
+}
+
+// readRawMessage reads a message from path, or from stdin when path is empty or "-"
+func readRawMessage(path string, stdin io.Reader) ([]byte, error) {
+	if path != "" && path != "-" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to read %s: %v", path, err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+		return data, nil
+	}
+
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return nil, &output.CLIError{
+			Message:  "No message given (pass --file or pipe it on stdin)",
+			ExitCode: output.ExitUsage,
+		}
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read message from stdin: %v", err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return data, nil
+}
+
+// uploadRawParts uploads embedded parts, adding attachments to req and pointing
+// cid: references in the HTML body at the uploaded inline images
+func uploadRawParts(ctx context.Context, mc zoho.MailService, req *zoho.SendEmailRequest, parts []zoho.RawPart, progress bool) error {
+	for _, part := range parts {
+		if err := zoho.CheckAttachmentSize(part.FileName, int64(len(part.Data))); err != nil {
+			return attachmentTooLarge(err)
+		}
+	}
+	for _, part := range parts {
+		inline := part.Inline && req.MailFormat == "html"
+		uploadCtx, done := withProgress(ctx, progress, "Uploading "+part.FileName)
+		ref, err := mc.UploadAttachmentData(uploadCtx, part.FileName, bytes.NewReader(part.Data), inline)
+		done()
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to upload %s: %v", part.FileName, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		if inline {
+			req.Content = strings.ReplaceAll(req.Content, "cid:"+part.ContentID, ref.AttachmentPath)
+		} else {
+			req.Attachments = append(req.Attachments, *ref)
+		}
+	}
+	return nil
+}
+
+// inlineParts reads image files for --inline; each is referenced from the HTML
+// body as cid:<file name>
+func inlineParts(paths []string) ([]zoho.RawPart, error) {
+	if err := checkAttachmentSizes(paths); err != nil {
+		return nil, err
+	}
+	parts := make([]zoho.RawPart, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to read inline image %s: %v", path, err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+		name := filepath.Base(path)
+		parts = append(parts, zoho.RawPart{FileName: name, ContentID: name, Inline: true, Data: data})
+	}
+	return parts, nil
+}
@@ -0,0 +1,587 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/rules"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// RuleResultRow is a display struct for one action a rule took on a message
+type RuleResultRow struct {
+	MessageID   string
+	FromAddress string
+	Subject     string
+	Rule        string
+	Action      string
+	Result      string
+	Error       string
+}
+
+// RuleRow is a display struct for a rule in a rules file
+type RuleRow struct {
+	Name       string
+	Conditions string
+	Actions    string
+	Stop       string
+	Enabled    string
+}
+
+// MailRulesRunCmd applies a rules file to a folder's messages
+type MailRulesRunCmd struct {
+	File     string        `help:"Rules file (.json5, .json, .yaml or .yml; default: rules.json5 in the config directory)" short:"r" env:"ZOH_RULES" predictor:"file"`
+	Folder   string        `help:"Folder name, path or ID, or @name for a saved search" default:"Inbox" short:"f"`
+	Limit    int           `help:"Newest messages to process on the first pass or with --force; later passes take everything since the last" short:"l" default:"50"`
+	Watch    bool          `help:"Keep running, applying the rules to new messages as they arrive"`
+	Interval time.Duration `help:"Time between polls (with --watch)" default:"30s"`
+	Cursor   string        `help:"Cursor file recording the messages already processed (default: one per profile, folder and rules file under the data directory)" predictor:"file"`
+	Once     bool          `help:"With --watch, poll once and exit (for cron)"`
+}
+
+// Run executes the rules run command
+func (cmd *MailRulesRunCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals, cfg *config.Config) error {
+	if cmd.Watch && cmd.Interval < watchMinInterval && !cmd.Once {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("--interval must be at least %s", watchMinInterval),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if cmd.Once && !cmd.Watch {
+		return &output.CLIError{Message: "--once only applies with --watch", ExitCode: output.ExitUsage}
+	}
+
+	file, err := loadRules(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C stops watching; the cursor is saved after every message
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	src, err := resolveMessageSource(ctx, mailClient, cfg.Profile, cmd.Folder)
+	if err != nil {
+		return err
+	}
+	runner, err := newRuleRunner(ctx, mailClient, file, src, globals.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Watch {
+		return cmd.watch(ctx, runner, globals, cfg)
+	}
+
+	cursorPath, cursor, started, err := cmd.loadCursor(runner, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Messages a previous run processed are skipped, so actions such as
+	// forward, reply and run are not repeated unless forced. Everything since
+	// that run is taken, however much arrived in between.
+	var messages []zoho.MessageSummary
+	if started && !globals.Force {
+		messages, err = pollNewMessages(ctx, mailClient, src, cursor)
+	} else {
+		messages, err = listNewest(ctx, mailClient, src, cmd.Limit)
+		// Oldest first, as the rules would have run had they been watching
+		slices.Reverse(messages)
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to list %s: %v", src.Path, err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	if started && !globals.Force && forgetVanished(cursor, messages, src) {
+		if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+			return err
+		}
+	}
+
+	var rows []RuleResultRow
+	matched, failed := 0, 0
+	for _, msg := range messages {
+		results := runner.run(ctx, msg, cursor, globals.Force)
+		if len(results) > 0 {
+			matched++
+		}
+		for _, row := range results {
+			if row.Result == "failed" {
+				failed++
+			}
+		}
+		rows = append(rows, results...)
+
+		if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+			return err
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "ID", Key: "MessageID"},
+		{Name: "From", Key: "FromAddress"},
+		{Name: "Subject", Key: "Subject", Width: 40},
+		{Name: "Rule", Key: "Rule"},
+		{Name: "Action", Key: "Action"},
+		{Name: "Result", Key: "Result"},
+		{Name: "Error", Key: "Error"},
+	}
+	if err := fp.Formatter.PrintList(rows, columns); err != nil {
+		return err
+	}
+
+	// Print summary to stderr
+	prefix := ""
+	if globals.DryRun {
+		prefix = "[DRY RUN] "
+	}
+	fmt.Fprintf(os.Stderr, "%s%d message(s) checked, %d matched, %d action(s) failed", prefix, len(messages), matched, failed)
+	if failed > 0 && !globals.DryRun {
+		fmt.Fprint(os.Stderr, "; the next run retries them")
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if failed > 0 {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to apply rules: %d action(s) failed", failed),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+	return nil
+}
+
+// watch applies the rules to each message newer than the cursor, polling
+// until interrupted. Like mail watch, the first run starts from the newest
+// message instead of replaying the folder.
+func (cmd *MailRulesRunCmd) watch(ctx context.Context, runner *ruleRunner, globals *Globals, cfg *config.Config) error {
+	folder := runner.folder
+	cursorPath, cursor, started, err := cmd.loadCursor(runner, cfg)
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		newest, err := folder.list(ctx, runner.mc, 0, watchPageSize)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		for _, msg := range newest {
+			received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+			cursor.advance(received, msg.MessageID)
+		}
+		if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+			return err
+		}
+		if cmd.Once {
+			return nil
+		}
+	}
+
+	jsonMode := globals.ResolvedOutput() == "json"
+	for {
+		messages, err := pollNewMessages(ctx, runner.mc, folder, cursor)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && cmd.Once:
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		case err != nil:
+			// Keep watching through transient failures
+			fmt.Fprintf(os.Stderr, "Failed to list %s: %v (retrying in %s)\n", folder.Path, err, cmd.Interval)
+		default:
+			if forgetVanished(cursor, messages, folder) {
+				if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+					return err
+				}
+			}
+			for _, msg := range messages {
+				for _, row := range runner.run(ctx, msg, cursor, false) {
+					if err := emitRuleResult(row, jsonMode); err != nil {
+						return err
+					}
+				}
+				if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+					return err
+				}
+			}
+		}
+
+		if cmd.Once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cmd.Interval):
+		}
+	}
+}
+
+// loadCursor loads the cursor recording which messages the rules file has
+// processed in the folder, shared by single passes and --watch
+func (cmd *MailRulesRunCmd) loadCursor(runner *ruleRunner, cfg *config.Config) (path string, cursor *watchCursor, started bool, err error) {
+	abs, err := filepath.Abs(runner.rules.Path)
+	if err != nil {
+		abs = runner.rules.Path
+	}
+	key := "rules:" + abs
+
+	path = cmd.Cursor
+	if path == "" {
+		path = watchCursorPath(cfg.Profile, runner.folder.FolderID, key)
+	}
+	cursor, started, err = loadWatchCursor(path, runner.folder.FolderID, key)
+	return path, cursor, started, err
+}
+
+// emitRuleResult prints one action result as it happens, as an NDJSON line in JSON mode
+func emitRuleResult(row RuleResultRow, jsonMode bool) error {
+	if jsonMode {
+		return json.NewEncoder(os.Stdout).Encode(row)
+	}
+	result := row.Result
+	if row.Error != "" {
+		result += ": " + row.Error
+	}
+	_, err := fmt.Fprintf(os.Stdout, "%s\t%s\t%s\t%s\t%s\n", row.MessageID, row.Subject, row.Rule, row.Action, result)
+	return err
+}
+
+// MailRulesCheckCmd validates a rules file without touching any mail
+type MailRulesCheckCmd struct {
+	File string `arg:"" optional:"" help:"Rules file (default: rules.json5 in the config directory)" env:"ZOH_RULES" predictor:"file"`
+}
+
+// Run executes the rules check command
+func (cmd *MailRulesCheckCmd) Run(fp *FormatterProvider) error {
+	file, err := loadRules(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]RuleRow, len(file.Rules))
+	for i, r := range file.Rules {
+		var conditions []string
+		if r.Query != "" {
+			conditions = append(conditions, r.Query)
+		}
+		for _, c := range r.Match {
+			conditions = append(conditions, describeCondition(c))
+		}
+		actions := make([]string, len(r.Actions))
+		for j, a := range r.Actions {
+			actions[j] = a.String()
+		}
+		rows[i] = RuleRow{
+			Name:       r.Name,
+			Conditions: strings.Join(conditions, "; "),
+			Actions:    strings.Join(actions, "; "),
+			Stop:       yesNo(r.Stop),
+			Enabled:    yesNo(!r.Disabled),
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Name", Key: "Name"},
+		{Name: "Conditions", Key: "Conditions", Width: 50},
+		{Name: "Actions", Key: "Actions"},
+		{Name: "Stop", Key: "Stop"},
+		{Name: "Enabled", Key: "Enabled"},
+	}
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// describeCondition renders a field condition for display, e.g. subject ~ ^\[PROD\]
+func describeCondition(c rules.Condition) string {
+	op, value := "contains", c.Contains
+	switch {
+	case c.Equals != nil:
+		op, value = "=", strconv.Quote(*c.Equals)
+	case c.Matches != "":
+		op, value = "~", c.Matches
+	}
+	if c.Not {
+		op = map[string]string{"contains": "not contains", "=": "!=", "~": "!~"}[op]
+	}
+	return c.Field + " " + op + " " + value
+}
+
+// yesNo renders a boolean as "yes" or "no"
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// loadRules loads a rules file, defaulting to the one in the config directory
+func loadRules(path string) (*rules.File, error) {
+	if path == "" {
+		path = config.RulesPath()
+	}
+	file, err := rules.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, (&output.CLIError{
+			Message:  fmt.Sprintf("Rules file not found: %s", path),
+			ExitCode: output.ExitNotFound,
+		}).WithHint("Pass --file, or create " + config.RulesPath())
+	}
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid rules file: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	return file, nil
+}
+
+// ruleRunner carries out rule actions. Folders, labels and reply templates
+// are looked up once, before any message is touched.
+type ruleRunner struct {
+	mc        zoho.MailService
+	rules     *rules.File
+	folder    *messageSource
+	dryRun    bool
+	folders   map[string]string
+	labels    map[string]string
+	templates map[string]*mailTemplate
+}
+
+// newRuleRunner resolves everything the rules refer to, so a typo fails the
+// run up front rather than halfway through a folder
+func newRuleRunner(ctx context.Context, mc zoho.MailService, file *rules.File, folder *messageSource, dryRun bool) (*ruleRunner, error) {
+	r := &ruleRunner{
+		mc:        mc,
+		rules:     file,
+		folder:    folder,
+		dryRun:    dryRun,
+		folders:   make(map[string]string),
+		labels:    make(map[string]string),
+		templates: make(map[string]*mailTemplate),
+	}
+
+	err := file.Resolve(searchResolver(ctx, mc))
+	if err != nil {
+		var cliErr *output.CLIError
+		if errors.As(err, &cliErr) {
+			return nil, err
+		}
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid rules file: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	for _, rule := range file.Rules {
+		if rule.Disabled {
+			continue
+		}
+		for _, a := range rule.Actions {
+			switch {
+			case a.Move != "" && r.folders[a.Move] == "":
+				if r.folders[a.Move], err = resolveFolderID(ctx, mc, a.Move); err != nil {
+					return nil, err
+				}
+			case a.Label != "" && r.labels[a.Label] == "":
+				if r.labels[a.Label], err = resolveLabelID(ctx, mc, a.Label); err != nil {
+					return nil, err
+				}
+			case a.Reply != "" && r.templates[a.Reply] == nil:
+				html := strings.EqualFold(filepath.Ext(a.Reply), ".html")
+				if r.templates[a.Reply], err = loadMailTemplate(a.Reply, nil, html); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// ruleMaxAttempts is how many times a message's failed actions are tried
+// before the failure is given up on
+const ruleMaxAttempts = 3
+
+// ruleFailure records a message whose rule actions failed, so later passes
+// retry it without repeating the actions that succeeded
+type ruleFailure struct {
+	ReceivedTime int64    `json:"receivedTime"`
+	Done         []string `json:"done,omitempty"` // actions that succeeded, as "rule: action"
+	Attempts     int      `json:"attempts"`
+}
+
+// run processes a message and moves the cursor past it. A message with a
+// failed action is also recorded in the cursor, until it succeeds or has
+// been tried ruleMaxAttempts times; force runs every action again.
+func (r *ruleRunner) run(ctx context.Context, msg zoho.MessageSummary, cursor *watchCursor, force bool) []RuleResultRow {
+	prev := cursor.Failed[msg.MessageID]
+	failure := &ruleFailure{Attempts: 1}
+	if prev != nil && !force {
+		failure.Done, failure.Attempts = prev.Done, prev.Attempts+1
+	}
+	delete(cursor.Failed, msg.MessageID)
+
+	rows := r.process(ctx, msg, failure.Done)
+	failed := false
+	for _, row := range rows {
+		switch row.Result {
+		case "ok":
+			failure.Done = append(failure.Done, actionKey(row.Rule, row.Action))
+		case "failed":
+			failed = true
+		}
+	}
+
+	received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+	switch {
+	case !failed:
+	case failure.Attempts >= ruleMaxAttempts:
+		fmt.Fprintf(os.Stderr, "Warning: giving up on message %s after %d failed attempts\n", msg.MessageID, failure.Attempts)
+	default:
+		if cursor.Failed == nil {
+			cursor.Failed = make(map[string]*ruleFailure)
+		}
+		failure.ReceivedTime = received
+		cursor.Failed[msg.MessageID] = failure
+	}
+	cursor.advance(received, msg.MessageID)
+	return rows
+}
+
+// forgetVanished drops the retries for messages a listing no longer holds,
+// such as ones an earlier action moved away, reporting whether there were any
+func forgetVanished(cursor *watchCursor, listed []zoho.MessageSummary, src *messageSource) bool {
+	dropped := false
+	for id := range cursor.Failed {
+		if !slices.ContainsFunc(listed, func(msg zoho.MessageSummary) bool { return msg.MessageID == id }) {
+			fmt.Fprintf(os.Stderr, "Warning: message %s has left %s; its failed actions are not retried\n", id, src.Path)
+			delete(cursor.Failed, id)
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+// actionKey identifies a rule's action in a ruleFailure
+func actionKey(rule, action string) string {
+	return rule + ": " + action
+}
+
+// process evaluates the rules against a message and runs the actions of
+// every matching rule, returning one row per action. Actions listed in done
+// are skipped.
+func (r *ruleRunner) process(ctx context.Context, msg zoho.MessageSummary, done []string) []RuleResultRow {
+	row := RuleResultRow{MessageID: msg.MessageID, FromAddress: msg.FromAddress, Subject: msg.Subject}
+	msg.FolderID = r.folder.folderOf(msg)
+
+	matched, err := r.rules.Evaluate(&rules.Message{
+		Summary: &msg,
+		Metadata: func() (*zoho.MessageMetadata, error) {
+			return r.mc.GetMessageMetadata(ctx, msg.FolderID, msg.MessageID)
+		},
+	})
+	var rows []RuleResultRow
+	if err != nil {
+		failed := row
+		failed.Result, failed.Error = "failed", err.Error()
+		rows = append(rows, failed)
+	}
+
+	for _, rule := range matched {
+		for _, action := range rule.Actions {
+			if slices.Contains(done, actionKey(rule.Name, action.String())) {
+				continue
+			}
+			result := row
+			result.Rule, result.Action, result.Result = rule.Name, action.String(), "ok"
+			if r.dryRun {
+				result.Result = "dry-run"
+			} else if err := r.apply(ctx, msg, action); err != nil {
+				result.Result, result.Error = "failed", err.Error()
+			}
+			rows = append(rows, result)
+		}
+	}
+	return rows
+}
+
+// apply carries out one action on a message
+func (r *ruleRunner) apply(ctx context.Context, msg zoho.MessageSummary, action rules.Action) error {
+	ids := []string{msg.MessageID}
+	switch {
+	case action.Move != "":
+		return r.mc.MoveMessages(ctx, ids, r.folders[action.Move])
+	case action.Label != "":
+		return r.mc.ApplyLabels(ctx, ids, []string{r.labels[action.Label]})
+	case action.MarkRead:
+		return r.mc.MarkRead(ctx, ids)
+	case action.Forward != "":
+		return r.mc.ForwardEmail(ctx, msg.MessageID, &zoho.SendEmailRequest{
+			ToAddress:  action.Forward,
+			Subject:    "Fwd: " + msg.Subject,
+			MailFormat: "plaintext",
+		})
+	case action.Reply != "":
+		mail, err := r.templates[action.Reply].render(newWatchEvent(msg, r.folder))
+		if err != nil {
+			return fmt.Errorf("render %s: %w", filepath.Base(action.Reply), err)
+		}
+		req := &zoho.SendEmailRequest{
+			ToAddress:  msg.FromAddress,
+			Subject:    mail.Subject,
+			Content:    mail.Body,
+			MailFormat: "plaintext",
+		}
+		if req.Subject == "" {
+			req.Subject = "Re: " + msg.Subject
+		}
+		if strings.EqualFold(filepath.Ext(action.Reply), ".html") {
+			req.MailFormat = "html"
+		}
+		return r.mc.ReplyToEmail(ctx, msg.MessageID, req)
+	default:
+		return runWatchHook(ctx, action.Run, newWatchEvent(msg, r.folder))
+	}
+}
+
+// listNewest lists up to limit of a folder's newest messages, newest first
+func listNewest(ctx context.Context, mc zoho.MailService, src *messageSource, limit int) ([]zoho.MessageSummary, error) {
+	var messages []zoho.MessageSummary
+	for len(messages) < limit {
+		n := min(watchPageSize, limit-len(messages))
+		page, err := src.list(ctx, mc, len(messages), n)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page...)
+		if len(page) < n {
+			break
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// SavedSearchRow is a display struct for a saved search
+type SavedSearchRow struct {
+	Name    string
+	Query   string
+	Created string
+}
+
+// MailSearchSaveCmd saves a search under a name
+type MailSearchSaveCmd struct {
+	Name  string `arg:"" help:"Name to save the search as (use it as @name in place of a folder)"`
+	Query string `arg:"" optional:"" help:"Search query (see mail messages search)"`
+	MessageFilterFlags
+	Label string `help:"Only messages with this label (name or ID)"`
+}
+
+// Run executes the save search command
+func (cmd *MailSearchSaveCmd) Run(cfg *config.Config, globals *Globals) error {
+	if err := config.ValidateSearchName(cmd.Name); err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	expr, err := cmd.searchExpr(cmd.Query)
+	if err != nil {
+		return err
+	}
+	if cmd.Label != "" {
+		label, _ := zoho.ParseSearch((&zoho.SearchTerm{Field: "label", Value: cmd.Label}).String())
+		expr = &zoho.SearchAnd{Terms: []zoho.SearchExpr{expr, label}}
+	}
+	query := expr.String()
+
+	searches, err := config.LoadSearches(cfg.Profile)
+	if err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if existing := config.FindSearch(searches, cmd.Name); existing != nil {
+		if !globals.Force {
+			return (&output.CLIError{
+				Message:  fmt.Sprintf("Saved search already exists: %s (%s)", cmd.Name, existing.Query),
+				ExitCode: output.ExitUsage,
+			}).WithHint("Use --force to replace it")
+		}
+		existing.Query = query
+		existing.Created = time.Now().UTC()
+	} else {
+		searches = append(searches, config.SavedSearch{Name: cmd.Name, Query: query, Created: time.Now().UTC()})
+	}
+
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would save search @%s: %s\n", cmd.Name, query)
+		return nil
+	}
+	if err := config.SaveSearches(cfg.Profile, searches); err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Saved search @%s: %s\n", cmd.Name, query)
+	return nil
+}
+
+// MailSearchListCmd lists saved searches
+type MailSearchListCmd struct{}
+
+// Run executes the list saved searches command
+func (cmd *MailSearchListCmd) Run(fp *FormatterProvider, cfg *config.Config) error {
+	searches, err := config.LoadSearches(cfg.Profile)
+	if err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	rows := make([]SavedSearchRow, len(searches))
+	for i, s := range searches {
+		rows[i] = SavedSearchRow{
+			Name:    s.Name,
+			Query:   s.Query,
+			Created: s.Created.Local().Format("2006-01-02 15:04"),
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Name", Key: "Name"},
+		{Name: "Query", Key: "Query"},
+		{Name: "Created", Key: "Created"},
+	}
+
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// MailSearchRunCmd runs a saved search
+type MailSearchRunCmd struct {
+	Name  string `arg:"" help:"Saved search name (with or without @)"`
+	Limit int    `help:"Maximum results" short:"l" default:"50"`
+	All   bool   `help:"Fetch every match (no limit)" short:"a"`
+}
+
+// Run executes the run saved search command
+func (cmd *MailSearchRunCmd) Run(sp *ServiceProvider, fp *FormatterProvider, cfg *config.Config) error {
+	saved, err := loadSavedSearch(cfg.Profile, strings.TrimPrefix(cmd.Name, "@"))
+	if err != nil {
+		return err
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	limit := cmd.Limit
+	if cmd.All {
+		limit = 0
+	}
+	messages, err := runSavedSearch(context.Background(), mailClient, saved, nil, limit)
+	if err != nil {
+		return err
+	}
+	return printMessageList(fp, messages)
+}
+
+// MailSearchDeleteCmd deletes saved searches
+type MailSearchDeleteCmd struct {
+	Names []string `arg:"" help:"Saved search names (with or without @)"`
+}
+
+// Run executes the delete saved search command
+func (cmd *MailSearchDeleteCmd) Run(cfg *config.Config, globals *Globals) error {
+	searches, err := config.LoadSearches(cfg.Profile)
+	if err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	for _, name := range cmd.Names {
+		name = strings.TrimPrefix(name, "@")
+		if config.FindSearch(searches, name) == nil {
+			return savedSearchNotFound(name)
+		}
+		searches = deleteSavedSearch(searches, name)
+	}
+
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would delete saved search(es): %s\n", strings.Join(cmd.Names, ", "))
+		return nil
+	}
+	if err := config.SaveSearches(cfg.Profile, searches); err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Deleted saved search(es): %s\n", strings.Join(cmd.Names, ", "))
+	return nil
+}
+
+// deleteSavedSearch returns searches without the one called name
+func deleteSavedSearch(searches []config.SavedSearch, name string) []config.SavedSearch {
+	kept := searches[:0]
+	for _, s := range searches {
+		if s.Name != name {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// messageSource is what the --folder argument of a command that lists
+// messages names: a folder, or a saved search (@name) standing in for one
+type messageSource struct {
+	*zoho.Folder // for a saved search, a stand-in whose ID and path are @name
+	saved        *config.SavedSearch
+
+	// The saved search's results fetched so far for the current listing
+	matches  []zoho.MessageSummary
+	complete bool // matches holds every result
+}
+
+// resolveMessageSource resolves a folder name, path or ID, or @name for a
+// saved search. An @name that is neither a saved search nor a folder is not
+// found, so a typo is not taken for a folder ID.
+func resolveMessageSource(ctx context.Context, mc zoho.MailService, profile, arg string) (*messageSource, error) {
+	name, ok := strings.CutPrefix(arg, "@")
+	if !ok {
+		folder, err := resolveFolder(ctx, mc, arg)
+		if err != nil {
+			return nil, err
+		}
+		return &messageSource{Folder: folder}, nil
+	}
+
+	searches, err := config.LoadSearches(profile)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	if saved := config.FindSearch(searches, name); saved != nil {
+		return &messageSource{Folder: &zoho.Folder{FolderID: arg, FolderName: arg, Path: arg}, saved: saved}, nil
+	}
+	if folder, err := resolveFolder(ctx, mc, arg); err == nil {
+		return &messageSource{Folder: folder}, nil
+	}
+	return nil, savedSearchNotFound(name)
+}
+
+// list returns up to limit messages from start, newest first; for a saved
+// search a limit of 0 means all of them. A saved search is run afresh when a
+// listing starts at 0; later pages come from its results, fetched in doubling
+// batches so paging through n results runs the search only about log(n) times.
+func (src *messageSource) list(ctx context.Context, mc zoho.MailService, start, limit int) ([]zoho.MessageSummary, error) {
+	if src.saved == nil {
+		return mc.ListMessages(ctx, src.FolderID, start, limit)
+	}
+	if start == 0 {
+		src.matches, src.complete = nil, false
+	}
+	if want := start + limit; !src.complete && (limit <= 0 || len(src.matches) < want) {
+		n := max(want, 2*len(src.matches))
+		if limit <= 0 {
+			n = 0
+		}
+		matches, err := runSavedSearch(ctx, mc, src.saved, nil, n)
+		if err != nil {
+			return nil, err
+		}
+		src.matches, src.complete = matches, n == 0 || len(matches) < n
+	}
+	end := len(src.matches)
+	if limit > 0 {
+		end = min(start+limit, end)
+	}
+	return src.matches[min(start, end):end], nil
+}
+
+// folderOf returns the folder holding msg, which for a saved search is not
+// the source's own
+func (src *messageSource) folderOf(msg zoho.MessageSummary) string {
+	if src.saved != nil && msg.FolderID != "" {
+		return msg.FolderID
+	}
+	return src.FolderID
+}
+
+// expandSavedSearches replaces in:@name terms naming a saved search with its
+// query, so a saved search can scope a query as a folder does. Other in:@
+// terms are left for folder lookup.
+func expandSavedSearches(profile string, expr zoho.SearchExpr) (zoho.SearchExpr, error) {
+	x := &savedSearchExpander{profile: profile}
+	return x.expand(expr, nil)
+}
+
+// savedSearchExpander loads the saved searches once, on the first in:@ term
+type savedSearchExpander struct {
+	profile  string
+	searches []config.SavedSearch
+	loaded   bool
+}
+
+// expand expands expr; active lists the saved searches being expanded, to
+// catch one that refers to itself
+func (x *savedSearchExpander) expand(expr zoho.SearchExpr, active []string) (zoho.SearchExpr, error) {
+	switch e := expr.(type) {
+	case *zoho.SearchTerm:
+		name, ok := strings.CutPrefix(e.Value, "@")
+		if e.Field != "in" || !ok {
+			return e, nil
+		}
+		if !x.loaded {
+			searches, err := config.LoadSearches(x.profile)
+			if err != nil {
+				return nil, &output.CLIError{
+					Message:  err.Error(),
+					ExitCode: output.ExitGeneral,
+				}
+			}
+			x.searches, x.loaded = searches, true
+		}
+		saved := config.FindSearch(x.searches, name)
+		if saved == nil {
+			return e, nil
+		}
+		if slices.Contains(active, name) {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Saved search @%s refers to itself", name),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		inner, err := zoho.ParseSearch(saved.Query)
+		if err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Saved search @%s is invalid: %v", name, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		return x.expand(inner, append(active, name))
+	case *zoho.SearchAnd:
+		terms, err := x.expandAll(e.Terms, active)
+		return &zoho.SearchAnd{Terms: terms}, err
+	case *zoho.SearchOr:
+		terms, err := x.expandAll(e.Terms, active)
+		return &zoho.SearchOr{Terms: terms}, err
+	case *zoho.SearchNot:
+		term, err := x.expand(e.Term, active)
+		return &zoho.SearchNot{Term: term}, err
+	}
+	return expr, nil
+}
+
+// expandAll expands each of terms
+func (x *savedSearchExpander) expandAll(terms []zoho.SearchExpr, active []string) ([]zoho.SearchExpr, error) {
+	out := make([]zoho.SearchExpr, len(terms))
+	for i, t := range terms {
+		var err error
+		if out[i], err = x.expand(t, active); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// loadSavedSearch returns the profile's saved search called name
+func loadSavedSearch(profile, name string) (*config.SavedSearch, error) {
+	searches, err := config.LoadSearches(profile)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	saved := config.FindSearch(searches, name)
+	if saved == nil {
+		return nil, savedSearchNotFound(name)
+	}
+	return saved, nil
+}
+
+// savedSearchNotFound reports an unknown saved search name
+func savedSearchNotFound(name string) error {
+	return (&output.CLIError{
+		Message:  fmt.Sprintf("Saved search not found: %s", name),
+		ExitCode: output.ExitNotFound,
+	}).WithHint("Run 'zoh mail search list' to see saved searches")
+}
+
+// runSavedSearch runs a saved search, narrowed by extra when it is not nil,
+// and returns up to limit matches (0 = all)
+func runSavedSearch(ctx context.Context, mc zoho.MailService, saved *config.SavedSearch, extra zoho.SearchExpr, limit int) ([]zoho.MessageSummary, error) {
+	expr, err := zoho.ParseSearch(saved.Query)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Saved search @%s is invalid: %v", saved.Name, err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if extra != nil {
+		expr = &zoho.SearchAnd{Terms: []zoho.SearchExpr{expr, extra}}
+	}
+
+	plan, err := compileSearch(ctx, mc, expr)
+	if err != nil {
+		return nil, err
+	}
+	return searchMessages(ctx, mc, plan, limit)
+}
+
+// printMessageList prints message summaries as the rows messages list and search show
+func printMessageList(fp *FormatterProvider, messages []zoho.MessageSummary) error {
+	rows := make([]MessageListRow, len(messages))
+	for i, msg := range messages {
+		timestamp := formatReceivedTime(msg.ReceivedTime)
+		attachment := ""
+		if msg.HasAttachment == "1" || msg.HasAttachment == "true" {
+			attachment = "Y"
+		}
+
+		rows[i] = MessageListRow{
+			Status:      msg.Status,
+			FromAddress: msg.FromAddress,
+			Subject:     msg.Subject,
+			Date:        timestamp,
+			Attachment:  attachment,
+			MessageID:   msg.MessageID,
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Status", Key: "Status"},
+		{Name: "From", Key: "FromAddress"},
+		{Name: "Subject", Key: "Subject"},
+		{Name: "Date", Key: "Date"},
+		{Name: "Attachment", Key: "Attachment"},
+		{Name: "ID", Key: "MessageID"},
+	}
+
+	return fp.Formatter.PrintList(rows, columns)
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// sendAtLayouts are the accepted --send-at formats
+var sendAtLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+}
+
+// ScheduleFlags are the scheduled-send flags shared by the send commands
+type ScheduleFlags struct {
+	SendAt   string `help:"Send later, at this time (YYYY-MM-DD HH:MM or RFC3339)" name:"send-at"`
+	Timezone string `help:"IANA time zone for --send-at, e.g. Europe/Berlin (default: local time)"`
+}
+
+// schedule returns the time the message should be sent, or the zero time to send now
+func (f *ScheduleFlags) schedule(now time.Time, draft bool) (time.Time, error) {
+	if f.SendAt == "" {
+		if f.Timezone != "" {
+			return time.Time{}, &output.CLIError{Message: "--timezone requires --send-at", ExitCode: output.ExitUsage}
+		}
+		return time.Time{}, nil
+	}
+	if draft {
+		return time.Time{}, &output.CLIError{Message: "--send-at cannot be combined with --draft", ExitCode: output.ExitUsage}
+	}
+	return parseSendAt(f.SendAt, f.Timezone, now)
+}
+
+// parseSendAt parses a --send-at value in the given IANA time zone (local time
+// when empty) and checks it is in the future. Local times are converted to UTC
+// so the result always carries a zone name Zoho understands.
+func parseSendAt(value, timezone string, now time.Time) (time.Time, error) {
+	loc := time.Local
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, &output.CLIError{
+				Message:  fmt.Sprintf("Invalid time zone %q: %v", timezone, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		at = at.In(loc)
+	} else {
+		for _, layout := range sendAtLayouts {
+			if at, err = time.ParseInLocation(layout, value, loc); err == nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return time.Time{}, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid --send-at %q (use YYYY-MM-DD HH:MM)", value),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	if !at.After(now) {
+		return time.Time{}, &output.CLIError{
+			Message:  fmt.Sprintf("--send-at %s is in the past", value),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	if timezone == "" {
+		at = at.UTC()
+	}
+	return at, nil
+}
+
+// formatScheduled formats a scheduled send time for confirmations
+func formatScheduled(at time.Time) string {
+	return at.Format("2006-01-02 15:04 MST")
+}
+
+// ScheduledListRow is a display struct for scheduled message list output
+type ScheduledListRow struct {
+	Subject   string
+	SendAt    string
+	MessageID string
+}
+
+// MailScheduledListCmd lists messages waiting to be sent
+type MailScheduledListCmd struct {
+	Limit int `help:"Maximum messages to show" short:"l" default:"50"`
+}
+
+// Run executes the list scheduled command
+func (cmd *MailScheduledListCmd) Run(sp *ServiceProvider, fp *FormatterProvider) error {
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Scheduled messages wait in Outbox until they are sent
+	folderID, err := resolveFolderID(ctx, mailClient, "Outbox")
+	if err != nil {
+		return err
+	}
+
+	messages, err := mailClient.ListMessages(ctx, folderID, 0, cmd.Limit)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch scheduled messages: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	rows := make([]ScheduledListRow, len(messages))
+	for i, msg := range messages {
+		rows[i] = ScheduledListRow{
+			Subject:   msg.Subject,
+			SendAt:    formatReceivedTime(msg.ReceivedTime),
+			MessageID: msg.MessageID,
+		}
+	}
+
+	columns := []output.Column{
+		{Name: "Subject", Key: "Subject", Width: 50},
+		{Name: "Send At", Key: "SendAt"},
+		{Name: "ID", Key: "MessageID"},
+	}
+
+	return fp.Formatter.PrintList(rows, columns)
+}
+
+// MailScheduledCancelCmd cancels scheduled messages, keeping them as drafts
+type MailScheduledCancelCmd struct {
+	MessageIDs []string `arg:"" optional:"" name:"message-id" help:"Scheduled message IDs (read from stdin if omitted or \"-\")"`
+}
+
+// Run executes the cancel scheduled command
+func (cmd *MailScheduledCancelCmd) Run(sp *ServiceProvider, globals *Globals) error {
+	return runMessageAction(sp, globals, cmd.MessageIDs, "cancel %s", "Cancelled %s (moved to Drafts)",
+		func(ctx context.Context, mc zoho.MailService, ids []string) error {
+			draftsID, err := resolveFolderID(ctx, mc, "Drafts")
+			if err != nil {
+				return err
+			}
+			return mc.MoveMessages(ctx, ids, draftsID)
+		})
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+func TestParseSendAt(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	at, err := parseSendAt("2026-10-20 09:00", "Europe/Berlin", now)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", at.Location().String())
+	assert.Equal(t, time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC), at.UTC())
+
+	req := &zoho.SendEmailRequest{}
+	req.Schedule(at)
+	assert.True(t, req.IsSchedule)
+	assert.Equal(t, zoho.ScheduleTypeCustom, req.ScheduleType)
+	assert.Equal(t, "Europe/Berlin", req.TimeZone)
+	assert.Equal(t, "10/20/2026 09:00:00", req.ScheduleTime)
+
+	at, err = parseSendAt("2026-10-20T09:00:00+02:00", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, at.Location())
+	assert.Equal(t, 7, at.Hour())
+
+	_, err = parseSendAt("2026-10-01 09:00", "UTC", now)
+	assert.ErrorContains(t, err, "in the past")
+	_, err = parseSendAt("tomorrow", "UTC", now)
+	assert.ErrorContains(t, err, "Invalid --send-at")
+	_, err = parseSendAt("2026-10-20 09:00", "Mars/Olympus", now)
+	assert.ErrorContains(t, err, "Invalid time zone")
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// searchPageSize is how many messages each request fetches while filtering
+const searchPageSize = 200
+
+// searchScanLimit caps how many results of each search are checked against a
+// client-side filter, so a filter that rarely matches cannot page through a
+// whole mailbox
+var searchScanLimit = 5000
+
+// searchExpr combines the filter flags with a query in the search language
+// (see zoho.ParseSearch); every flag and the query must match
+func (f *MessageFilterFlags) searchExpr(query string) (zoho.SearchExpr, error) {
+	var terms []zoho.SearchExpr
+	add := func(field, value string) {
+		term, _ := zoho.ParseSearch((&zoho.SearchTerm{Field: field, Value: value}).String())
+		terms = append(terms, term)
+	}
+
+	if f.From != "" {
+		add("from", f.From)
+	}
+	if f.Subject != "" {
+		add("subject", f.Subject)
+	}
+	for _, date := range []struct{ field, value string }{{"after", f.After}, {"before", f.Before}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date.value); err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Invalid %s date format (use YYYY-MM-DD): %v", date.field, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		add(date.field, date.value)
+	}
+	if f.Unread {
+		add("is", "unread")
+	}
+	if f.HasAttachment {
+		add("has", "attachment")
+	}
+
+	expr, err := zoho.ParseSearch(query)
+	if err != nil {
+		return nil, searchSyntaxError(err)
+	}
+	if and, ok := expr.(*zoho.SearchAnd); ok {
+		terms = append(terms, and.Terms...)
+	} else if expr != nil {
+		terms = append(terms, expr)
+	}
+
+	switch len(terms) {
+	case 0:
+		return nil, &output.CLIError{
+			Message:  "Specify at least one search criterion",
+			ExitCode: output.ExitUsage,
+		}
+	case 1:
+		return terms[0], nil
+	}
+	return &zoho.SearchAnd{Terms: terms}, nil
+}
+
+// searchSyntaxError reports a query syntax error with a pointer to its position
+func searchSyntaxError(err error) error {
+	var syntaxErr *zoho.SearchSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	return &output.CLIError{
+		Message:  fmt.Sprintf("Invalid search query: %v\n\n  %s", err, strings.ReplaceAll(syntaxErr.Pointer(), "\n", "\n  ")),
+		ExitCode: output.ExitUsage,
+	}
+}
+
+// searchResolver looks up the folders and labels named by in: and label: terms
+func searchResolver(ctx context.Context, mc zoho.MailService) zoho.SearchResolver {
+	return func(field, name string) (string, error) {
+		if field == "label" {
+			return resolveLabelID(ctx, mc, name)
+		}
+		folder, err := resolveFolder(ctx, mc, name)
+		if err != nil {
+			return "", err
+		}
+		return folder.FolderID, nil
+	}
+}
+
+// compileSearch compiles a query, resolving the folders and labels it names
+func compileSearch(ctx context.Context, mc zoho.MailService, expr zoho.SearchExpr) (*zoho.SearchPlan, error) {
+	plan, err := zoho.CompileSearch(expr, searchResolver(ctx, mc))
+	if err == nil {
+		return plan, nil
+	}
+	var cliErr *output.CLIError
+	if errors.As(err, &cliErr) {
+		return nil, err
+	}
+	return nil, &output.CLIError{
+		Message:  fmt.Sprintf("Cannot run search: %v", err),
+		ExitCode: output.ExitUsage,
+	}
+}
+
+// searchMessages runs a compiled search and returns up to limit matches,
+// newest first (0 = all). Results are paged through until enough of them
+// pass the client-side filter, checking at most searchScanLimit per search.
+func searchMessages(ctx context.Context, mc zoho.MailService, plan *zoho.SearchPlan, limit int) ([]zoho.MessageSummary, error) {
+	var sources []func(start, limit int) ([]zoho.MessageSummary, error)
+	for _, key := range plan.Keys {
+		sources = append(sources, func(start, limit int) ([]zoho.MessageSummary, error) {
+			return mc.SearchMessages(ctx, key, start, limit)
+		})
+	}
+	if plan.Folder != "" {
+		sources = append(sources, func(start, limit int) ([]zoho.MessageSummary, error) {
+			return mc.ListMessages(ctx, plan.Folder, start, limit)
+		})
+	}
+
+	// Without a filter every result counts, so fetch just what is needed
+	pageSize := searchPageSize
+	if plan.Filter == nil && limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	seen := make(map[string]bool)
+	var matches []zoho.MessageSummary
+	for _, fetch := range sources {
+		found := 0
+	pages:
+		for start := 0; ; start += pageSize {
+			page, err := fetch(start, pageSize)
+			if err != nil {
+				return nil, &output.CLIError{
+					Message:  fmt.Sprintf("Failed to search messages: %v", err),
+					ExitCode: output.ExitAPIError,
+				}
+			}
+			for i := range page {
+				if !plan.Match(&page[i]) {
+					continue
+				}
+				found++
+				if !seen[page[i].MessageID] {
+					seen[page[i].MessageID] = true
+					matches = append(matches, page[i])
+				}
+				if limit > 0 && found >= limit {
+					break pages
+				}
+			}
+			if len(page) < pageSize {
+				break
+			}
+			if plan.Filter != nil && start+len(page) >= searchScanLimit {
+				fmt.Fprintf(os.Stderr, "Warning: stopped after checking %d messages; narrow the query to search further\n", start+len(page))
+				break
+			}
+		}
+	}
+
+	// Results of several searches are merged back into one newest-first list
+	if len(sources) > 1 {
+		slices.SortStableFunc(matches, func(a, b zoho.MessageSummary) int {
+			at, _ := strconv.ParseInt(a.ReceivedTime, 10, 64)
+			bt, _ := strconv.ParseInt(b.ReceivedTime, 10, 64)
+			return cmp.Compare(bt, at)
+		})
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/SeMmyT/zohcli/internal/output"
 	"github.com/SeMmyT/zohcli/internal/zoho"
@@ -12,31 +13,115 @@ import (
 
 // MailSendComposeCmd composes and sends a new email
 type MailSendComposeCmd struct {
-	To      string   `help:"Recipient email address" required:""`
-	Cc      string   `help:"CC recipient(s)" short:"c"`
-	Bcc     string   `help:"BCC recipient(s)" short:"b"`
-	Subject string   `help:"Email subject" required:""`
-	Body    string   `help:"Email body content" required:""`
-	HTML    bool     `help:"Send as HTML (default: plain text)" name:"html"`
-	Attach  []string `help:"File path(s) to attach (repeatable)" name:"attach" predictor:"file"`
+	To       string   `help:"Recipient email address (required unless set by --template)"`
+	Cc       string   `help:"CC recipient(s)" short:"c"`
+	Bcc      string   `help:"BCC recipient(s)" short:"b"`
+	Subject  string   `help:"Email subject (required unless set by --template)"`
+	Body     string   `help:"Email body content (required unless --template is used)"`
+	Template string   `help:"Go template file for the message: optional Subject/To/Cc/Bcc header lines, a blank line, then the body" predictor:"file"`
+	Vars     string   `help:"JSON file of variables for --template" predictor:"file"`
+	HTML     bool     `help:"Send as HTML (default: plain text)" name:"html"`
+	Text     string   `help:"Plain-text version of an --html body, sent with it as multipart/alternative (default: derived from the HTML)"`
+	Attach   []string `help:"File path(s) to attach (repeatable)" name:"attach" predictor:"file"`
+	Inline   []string `help:"Image(s) to embed, referenced from the HTML body as cid:<file name> (repeatable)" name:"inline" predictor:"file"`
+	Draft    bool     `help:"Save as a draft instead of sending"`
+	ScheduleFlags
+}
+
+// message returns the recipients, subject and body from flags, or from
+// --template rendered with --vars
+func (cmd *MailSendComposeCmd) message() (*renderedMail, error) {
+	var msg *renderedMail
+	switch {
+	case cmd.Template == "" && cmd.Vars != "":
+		return nil, &output.CLIError{Message: "--vars requires --template", ExitCode: output.ExitUsage}
+	case cmd.Template == "":
+		msg = &renderedMail{To: cmd.To, Cc: cmd.Cc, Bcc: cmd.Bcc, Subject: cmd.Subject, Body: cmd.Body}
+		if cmd.Body == "" {
+			return nil, &output.CLIError{Message: "missing flags: --body (or use --template)", ExitCode: output.ExitUsage}
+		}
+	case cmd.Body != "":
+		return nil, &output.CLIError{Message: "--body cannot be combined with --template", ExitCode: output.ExitUsage}
+	default:
+		overrides := map[string]string{"to": cmd.To, "cc": cmd.Cc, "bcc": cmd.Bcc, "subject": cmd.Subject}
+		tmpl, err := loadMailTemplate(cmd.Template, overrides, cmd.HTML)
+		if err != nil {
+			return nil, err
+		}
+		var vars map[string]any
+		if cmd.Vars != "" {
+			if vars, err = loadTemplateVars(cmd.Vars); err != nil {
+				return nil, err
+			}
+		}
+		if msg, err = tmpl.render(vars); err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Failed to render template: %v", err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+	}
+
+	if msg.To == "" {
+		return nil, &output.CLIError{Message: "missing flags: --to", ExitCode: output.ExitUsage}
+	}
+	if msg.Subject == "" {
+		return nil, &output.CLIError{Message: "missing flags: --subject", ExitCode: output.ExitUsage}
+	}
+	return msg, nil
 }
 
 // Run executes the compose command
-func (cmd *MailSendComposeCmd) Run(sp *ServiceProvider, globals *Globals) error {
+func (cmd *MailSendComposeCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	sendAt, err := cmd.schedule(time.Now(), cmd.Draft)
+	if err != nil {
+		return err
+	}
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+	msg, err := cmd.message()
+	if err != nil {
+		return err
+	}
+	if len(cmd.Inline) > 0 && !cmd.HTML {
+		return &output.CLIError{Message: "--inline requires --html", ExitCode: output.ExitUsage}
+	}
+	if cmd.Text != "" && !cmd.HTML {
+		return &output.CLIError{Message: "--text requires --html", ExitCode: output.ExitUsage}
+	}
+	inline, err := inlineParts(cmd.Inline)
+	if err != nil {
+		return err
+	}
+
 	// Dry-run preview
 	if globals.DryRun {
-		fmt.Fprintf(os.Stderr, "[DRY RUN] Would send email:\n")
-		fmt.Fprintf(os.Stderr, "  To: %s\n", cmd.To)
-		if cmd.Cc != "" {
-			fmt.Fprintf(os.Stderr, "  Cc: %s\n", cmd.Cc)
+		if cmd.Draft {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would save draft:\n")
+		} else {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would send email:\n")
+		}
+		fmt.Fprintf(os.Stderr, "  To: %s\n", msg.To)
+		if msg.Cc != "" {
+			fmt.Fprintf(os.Stderr, "  Cc: %s\n", msg.Cc)
+		}
+		if msg.Bcc != "" {
+			fmt.Fprintf(os.Stderr, "  Bcc: %s\n", msg.Bcc)
 		}
-		if cmd.Bcc != "" {
-			fmt.Fprintf(os.Stderr, "  Bcc: %s\n", cmd.Bcc)
+		fmt.Fprintf(os.Stderr, "  Subject: %s\n", msg.Subject)
+		if cmd.Text != "" {
+			fmt.Fprintf(os.Stderr, "  Format: html with a plain-text alternative\n")
 		}
-		fmt.Fprintf(os.Stderr, "  Subject: %s\n", cmd.Subject)
 		if len(cmd.Attach) > 0 {
 			fmt.Fprintf(os.Stderr, "  Attachments: %d file(s)\n", len(cmd.Attach))
 		}
+		if len(cmd.Inline) > 0 {
+			fmt.Fprintf(os.Stderr, "  Inline images: %d file(s)\n", len(cmd.Inline))
+		}
+		if !sendAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "  Send at: %s\n", formatScheduled(sendAt))
+		}
 		return nil
 	}
 
@@ -48,35 +133,41 @@ func (cmd *MailSendComposeCmd) Run(sp *ServiceProvider, globals *Globals) error
 	ctx := context.Background()
 
 	// Upload attachments if provided
-	var attachments []zoho.AttachmentReference
-	for _, filePath := range cmd.Attach {
-		ref, err := mailClient.UploadAttachment(ctx, filePath)
-		if err != nil {
-			return &output.CLIError{
-				Message:  fmt.Sprintf("Failed to upload attachment %s: %v", filePath, err),
-				ExitCode: output.ExitAPIError,
-			}
-		}
-		attachments = append(attachments, *ref)
+	attachments, err := uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals))
+	if err != nil {
+		return err
 	}
 
 	// Build send request
 	req := &zoho.SendEmailRequest{
-		ToAddress:   cmd.To,
-		CcAddress:   cmd.Cc,
-		BccAddress:  cmd.Bcc,
-		Subject:     cmd.Subject,
-		Content:     cmd.Body,
+		ToAddress:   msg.To,
+		CcAddress:   msg.Cc,
+		BccAddress:  msg.Bcc,
+		Subject:     msg.Subject,
+		Content:     msg.Body,
 		Attachments: attachments,
 	}
 
 	// Set mail format
 	if cmd.HTML {
 		req.MailFormat = "html"
+		req.TextContent = cmd.Text
 	} else {
 		req.MailFormat = "plaintext"
 	}
 
+	// Upload inline images and point their cid: references at them
+	if err := uploadRawParts(ctx, mailClient, req, inline, showProgress(globals)); err != nil {
+		return err
+	}
+
+	if cmd.Draft {
+		return saveDraft(ctx, mailClient, fp, "", req)
+	}
+	if !sendAt.IsZero() {
+		req.Schedule(sendAt)
+	}
+
 	// Send email
 	err = mailClient.SendEmail(ctx, req)
 	if err != nil {
@@ -87,7 +178,11 @@ func (cmd *MailSendComposeCmd) Run(sp *ServiceProvider, globals *Globals) error
 	}
 
 	// Print confirmation to stderr
-	fmt.Fprintf(os.Stderr, "Email sent to %s\n", cmd.To)
+	if !sendAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "Email to %s scheduled for %s\n", msg.To, formatScheduled(sendAt))
+	} else {
+		fmt.Fprintf(os.Stderr, "Email sent to %s\n", msg.To)
+	}
 	return nil
 }
 
@@ -99,12 +194,30 @@ type MailSendReplyCmd struct {
 	HTML      bool     `help:"Send as HTML (default: plain text)" name:"html"`
 	Attach    []string `help:"File path(s) to attach (repeatable)" name:"attach" predictor:"file"`
 	All       bool     `help:"Reply to all recipients" name:"all"`
+	Draft     bool     `help:"Save as a draft instead of sending"`
+	ScheduleFlags
 }
 
 // Run executes the reply command
-func (cmd *MailSendReplyCmd) Run(sp *ServiceProvider, globals *Globals) error {
+func (cmd *MailSendReplyCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	sendAt, err := cmd.schedule(time.Now(), cmd.Draft)
+	if err != nil {
+		return err
+	}
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+
 	// Dry-run preview
 	if globals.DryRun {
+		if cmd.Draft {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would save reply draft for message %s (reply-all=%v)\n", cmd.MessageID, cmd.All)
+			return nil
+		}
+		if !sendAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would schedule reply to message %s for %s (reply-all=%v)\n", cmd.MessageID, formatScheduled(sendAt), cmd.All)
+			return nil
+		}
 		fmt.Fprintf(os.Stderr, "[DRY RUN] Would reply to message %s (reply-all=%v)\n", cmd.MessageID, cmd.All)
 		return nil
 	}
@@ -132,16 +245,9 @@ func (cmd *MailSendReplyCmd) Run(sp *ServiceProvider, globals *Globals) error {
 	}
 
 	// Upload attachments if provided
-	var attachments []zoho.AttachmentReference
-	for _, filePath := range cmd.Attach {
-		ref, err := mailClient.UploadAttachment(ctx, filePath)
-		if err != nil {
-			return &output.CLIError{
-				Message:  fmt.Sprintf("Failed to upload attachment %s: %v", filePath, err),
-				ExitCode: output.ExitAPIError,
-			}
-		}
-		attachments = append(attachments, *ref)
+	attachments, err := uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals))
+	if err != nil {
+		return err
 	}
 
 	// Build send request
@@ -171,6 +277,17 @@ func (cmd *MailSendReplyCmd) Run(sp *ServiceProvider, globals *Globals) error {
 		req.CcAddress = strings.Join(ccList, ",")
 	}
 
+	if cmd.Draft {
+		req.Action = "reply"
+		if cmd.All {
+			req.Action = "replyall"
+		}
+		return saveDraft(ctx, mailClient, fp, cmd.MessageID, req)
+	}
+	if !sendAt.IsZero() {
+		req.Schedule(sendAt)
+	}
+
 	// Send reply
 	if cmd.All {
 		err = mailClient.ReplyAllToEmail(ctx, cmd.MessageID, req)
@@ -186,7 +303,9 @@ func (cmd *MailSendReplyCmd) Run(sp *ServiceProvider, globals *Globals) error {
 	}
 
 	// Print confirmation to stderr
-	if cmd.All {
+	if !sendAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "Reply scheduled for %s\n", formatScheduled(sendAt))
+	} else if cmd.All {
 		fmt.Fprintf(os.Stderr, "Reply sent to all recipients\n")
 	} else {
 		fmt.Fprintf(os.Stderr, "Reply sent to %s\n", metadata.FromAddress)
@@ -202,12 +321,30 @@ type MailSendForwardCmd struct {
 	Body      string   `help:"Additional message body" default:""`
 	HTML      bool     `help:"Send as HTML (default: plain text)" name:"html"`
 	Attach    []string `help:"File path(s) to attach (repeatable)" name:"attach" predictor:"file"`
+	Draft     bool     `help:"Save as a draft instead of sending"`
+	ScheduleFlags
 }
 
 // Run executes the forward command
-func (cmd *MailSendForwardCmd) Run(sp *ServiceProvider, globals *Globals) error {
+func (cmd *MailSendForwardCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals) error {
+	sendAt, err := cmd.schedule(time.Now(), cmd.Draft)
+	if err != nil {
+		return err
+	}
+	if err := checkAttachmentSizes(cmd.Attach); err != nil {
+		return err
+	}
+
 	// Dry-run preview
 	if globals.DryRun {
+		if cmd.Draft {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would save forward draft of message %s to %s\n", cmd.MessageID, cmd.To)
+			return nil
+		}
+		if !sendAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would schedule forward of message %s to %s for %s\n", cmd.MessageID, cmd.To, formatScheduled(sendAt))
+			return nil
+		}
 		fmt.Fprintf(os.Stderr, "[DRY RUN] Would forward message %s to %s\n", cmd.MessageID, cmd.To)
 		return nil
 	}
@@ -235,16 +372,9 @@ func (cmd *MailSendForwardCmd) Run(sp *ServiceProvider, globals *Globals) error
 	}
 
 	// Upload attachments if provided
-	var attachments []zoho.AttachmentReference
-	for _, filePath := range cmd.Attach {
-		ref, err := mailClient.UploadAttachment(ctx, filePath)
-		if err != nil {
-			return &output.CLIError{
-				Message:  fmt.Sprintf("Failed to upload attachment %s: %v", filePath, err),
-				ExitCode: output.ExitAPIError,
-			}
-		}
-		attachments = append(attachments, *ref)
+	attachments, err := uploadAttachments(ctx, mailClient, cmd.Attach, showProgress(globals))
+	if err != nil {
+		return err
 	}
 
 	// Build send request
@@ -262,6 +392,14 @@ func (cmd *MailSendForwardCmd) Run(sp *ServiceProvider, globals *Globals) error
 		req.MailFormat = "plaintext"
 	}
 
+	if cmd.Draft {
+		req.Action = "forward"
+		return saveDraft(ctx, mailClient, fp, cmd.MessageID, req)
+	}
+	if !sendAt.IsZero() {
+		req.Schedule(sendAt)
+	}
+
 	// Send forward
 	err = mailClient.ForwardEmail(ctx, cmd.MessageID, req)
 	if err != nil {
@@ -272,12 +410,39 @@ func (cmd *MailSendForwardCmd) Run(sp *ServiceProvider, globals *Globals) error
 	}
 
 	// Print confirmation to stderr
-	fmt.Fprintf(os.Stderr, "Message forwarded to %s\n", cmd.To)
+	if !sendAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "Forward to %s scheduled for %s\n", cmd.To, formatScheduled(sendAt))
+	} else {
+		fmt.Fprintf(os.Stderr, "Message forwarded to %s\n", cmd.To)
+	}
 	return nil
 }
 
-// resolveFolderID resolves a folder name to folder ID, fallback to treating input as ID
+// resolveFolderID resolves a folder name or path (e.g. "Projects/2025") to folder ID,
+// fallback to treating input as ID. A saved search (@name) is not a folder
+// and is rejected here unless a folder really has that name.
 func resolveFolderID(ctx context.Context, mc zoho.MailService, folderNameOrID string) (string, error) {
+	if name, ok := strings.CutPrefix(folderNameOrID, "@"); ok {
+		folder, err := mc.GetFolderByName(ctx, folderNameOrID)
+		if err == nil {
+			return folder.FolderID, nil
+		}
+		return "", (&output.CLIError{
+			Message:  fmt.Sprintf("@%s is a saved search, not a folder", name),
+			ExitCode: output.ExitUsage,
+		}).WithHint("Saved searches only work where messages are listed, e.g. 'zoh mail messages list --folder @" + name + "'")
+	}
+
+	if strings.Contains(folderNameOrID, "/") {
+		folders, err := mc.ListFolders(ctx)
+		if err == nil {
+			if folder := findFolderByPath(folders, folderNameOrID); folder != nil {
+				return folder.FolderID, nil
+			}
+		}
+		return folderNameOrID, nil
+	}
+
 	folder, err := mc.GetFolderByName(ctx, folderNameOrID)
 	if err != nil {
 		// If GetFolderByName fails, treat input as folder ID
@@ -124,72 +124,54 @@ func (cmd *MailSettingsVacationGetCmd) Run(sp *ServiceProvider, fp *FormatterPro
 		}
 	}
 
-	// Check if vacation response exists
-	if len(accountDetails.VacationResponse) == 0 || string(accountDetails.VacationResponse) == "null" {
-		fmt.Fprintln(os.Stderr, "No vacation reply configured")
-		return nil
-	}
-
-	// Parse vacation response
-	var vacationReply zoho.VacationReply
-	if err := json.Unmarshal(accountDetails.VacationResponse, &vacationReply); err != nil {
+	vacationReply, err := zoho.ParseVacationReply(accountDetails.VacationResponse)
+	if err != nil {
 		return &output.CLIError{
 			Message:  fmt.Sprintf("Failed to parse vacation response: %v", err),
 			ExitCode: output.ExitAPIError,
 		}
 	}
+	if vacationReply == nil {
+		fmt.Fprintln(os.Stderr, "No vacation reply configured")
+		return nil
+	}
 
-	// Display vacation details
-	return fp.Formatter.Print(vacationReply)
+	// Display vacation details with dates in the mailbox's time zone
+	return fp.Formatter.Print(newVacationStatus(vacationReply, accountDetails.TimeZone, time.Now()))
 }
 
 // MailSettingsVacationSetCmd enables vacation auto-reply
 type MailSettingsVacationSetCmd struct {
-	From     string `help:"Start date (MM/DD/YYYY HH:MM:SS)" required:""`
-	To       string `help:"End date (MM/DD/YYYY HH:MM:SS)" required:""`
-	Subject  string `help:"Auto-reply subject" required:""`
-	Content  string `help:"Auto-reply message content" required:""`
-	Interval int    `help:"Reply interval in minutes" default:"1440"`
-	SendTo   string `help:"Send to: all/contacts/noncontacts/org/nonOrgAll" default:"all" enum:"all,contacts,noncontacts,org,nonOrgAll"`
+	VacationFlags
 }
 
 // Run executes the set vacation command
 func (cmd *MailSettingsVacationSetCmd) Run(sp *ServiceProvider, globals *Globals) error {
-	// Validate date format
-	dateLayout := "01/02/2006 15:04:05"
-	if _, err := time.Parse(dateLayout, cmd.From); err != nil {
-		return &output.CLIError{
-			Message:  fmt.Sprintf("Invalid --from date format (expected MM/DD/YYYY HH:MM:SS): %v", err),
-			ExitCode: output.ExitUsage,
-		}
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
 	}
-	if _, err := time.Parse(dateLayout, cmd.To); err != nil {
+
+	ctx := context.Background()
+
+	// The mailbox's time zone and owner are needed for the dates and templates
+	accountDetails, err := mailClient.GetAccountDetails(ctx)
+	if err != nil {
 		return &output.CLIError{
-			Message:  fmt.Sprintf("Invalid --to date format (expected MM/DD/YYYY HH:MM:SS): %v", err),
-			ExitCode: output.ExitUsage,
+			Message:  fmt.Sprintf("Failed to get account details: %v", err),
+			ExitCode: output.ExitAPIError,
 		}
 	}
 
-	// Dry-run preview
-	if globals.DryRun {
-		fmt.Fprintf(os.Stderr, "[DRY RUN] Would enable vacation reply: subject=%s\n", cmd.Subject)
-		return nil
-	}
-
-	mailClient, err := sp.Mail()
+	vacation, err := cmd.reply(time.Now(), accountDetails.TimeZone, accountDetails.DisplayName, accountDetails.PrimaryEmailAddress)
 	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
-
-	vacation := &zoho.VacationReply{
-		FromDate:   cmd.From,
-		ToDate:     cmd.To,
-		SendingInt: cmd.Interval,
-		Subject:    cmd.Subject,
-		Content:    cmd.Content,
-		SendTo:     cmd.SendTo,
+	// Dry-run preview
+	if globals.DryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would enable vacation reply (%s to %s): subject=%s\n", vacation.FromDate, vacation.ToDate, vacation.Subject)
+		return nil
 	}
 
 	if err := mailClient.AddVacationReply(ctx, vacation); err != nil {
@@ -199,7 +181,7 @@ func (cmd *MailSettingsVacationSetCmd) Run(sp *ServiceProvider, globals *Globals
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "Vacation reply enabled (%s to %s)\n", cmd.From, cmd.To)
+	fmt.Fprintf(os.Stderr, "Vacation reply enabled (%s to %s)\n", vacation.FromDate, vacation.ToDate)
 	return nil
 }
 
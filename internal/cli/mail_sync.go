@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/mirror"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// syncSaveEvery is how many changes are applied between sync-state saves
+const syncSaveEvery = 25
+
+// SyncSummary is a display struct for the result of a sync
+type SyncSummary struct {
+	Out     string
+	Folders int
+	New     int
+	Moved   int
+	Deleted int
+	Total   int
+	State   string
+}
+
+// folderListing is the message listing of one folder, oldest first
+type folderListing struct {
+	folder   zoho.Folder
+	messages []zoho.MessageSummary
+}
+
+// MailSyncCmd keeps a local mirror of the mailbox up to date
+type MailSyncCmd struct {
+	Out   string `help:"Local mirror directory (one subdirectory per folder, one .eml per message)" required:"" predictor:"file"`
+	Quick bool   `help:"Only fetch messages newer than each folder's high-water mark (skips move and deletion detection)"`
+	Prune bool   `help:"Remove local copies of messages deleted from Zoho (default: keep them and record the deletion)"`
+}
+
+// Run executes the sync command
+func (cmd *MailSyncCmd) Run(sp *ServiceProvider, fp *FormatterProvider, globals *Globals, cfg *config.Config) error {
+	statePath, err := mirror.StatePath(cfg.Profile, cmd.Out)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Invalid --out %s: %v", cmd.Out, err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	state, err := mirror.Load(statePath, cmd.Out)
+	if err != nil {
+		return &output.CLIError{
+			Message:  err.Error(),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels in-flight requests; progress so far is saved below
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	folders, err := mailClient.ListFolders(ctx)
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to fetch folders: %v", err),
+			ExitCode: output.ExitAPIError,
+		}
+	}
+
+	listings := make([]folderListing, 0, len(folders))
+	for _, folder := range folders {
+		messages, err := listFolderForSync(ctx, mailClient, folder, state, cmd.Quick)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		listings = append(listings, folderListing{folder: folder, messages: messages})
+	}
+
+	// Dry-run preview
+	if globals.DryRun {
+		pending := 0
+		for _, l := range listings {
+			for _, msg := range l.messages {
+				if _, known := state.Messages[msg.MessageID]; !known {
+					pending++
+				}
+			}
+		}
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would download %d new message(s) from %d folder(s) into %s\n", pending, len(listings), cmd.Out)
+		return nil
+	}
+
+	summary := SyncSummary{Out: cmd.Out, Folders: len(listings), State: statePath}
+	changes := 0
+	save := func() error {
+		if err := state.Save(statePath); err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to save sync state: %v", err),
+				ExitCode: output.ExitGeneral,
+			}
+		}
+		return nil
+	}
+	changed := func() error {
+		if changes++; changes%syncSaveEvery == 0 {
+			return save()
+		}
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, l := range listings {
+		fstate := state.Folder(l.folder.FolderID, l.folder.Path)
+		for _, msg := range l.messages {
+			seen[msg.MessageID] = true
+			received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+			file := mirror.MessageFile(l.folder.Path, msg.MessageID)
+
+			entry, known := state.Messages[msg.MessageID]
+			if known && !entry.Pruned && fileExists(filepath.Join(cmd.Out, entry.File)) {
+				if entry.File != file || entry.DeletedAt != nil {
+					if err := moveMirrored(cmd.Out, entry.File, file); err != nil {
+						if saveErr := save(); saveErr != nil {
+							return saveErr
+						}
+						return &output.CLIError{
+							Message:  fmt.Sprintf("Failed to move %s: %v", entry.File, err),
+							ExitCode: output.ExitGeneral,
+						}
+					}
+					if entry.FolderID != l.folder.FolderID {
+						summary.Moved++
+					}
+					entry.FolderID, entry.File, entry.DeletedAt = l.folder.FolderID, file, nil
+					if err := changed(); err != nil {
+						return err
+					}
+				}
+				fstate.Advance(received, msg.MessageID)
+				continue
+			}
+
+			raw, err := mailClient.GetOriginalMessage(ctx, l.folder.FolderID, msg.MessageID)
+			if err == nil {
+				err = writeMirrored(cmd.Out, file, raw)
+			}
+			if err != nil {
+				if saveErr := save(); saveErr != nil {
+					return saveErr
+				}
+				if ctx.Err() != nil {
+					return &output.CLIError{
+						Message:  fmt.Sprintf("Sync interrupted after %d new message(s); re-run to resume", summary.New),
+						ExitCode: output.ExitGeneral,
+					}
+				}
+				return &output.CLIError{
+					Message:  fmt.Sprintf("Failed to mirror message %s in %s: %v (re-run to resume)", msg.MessageID, l.folder.Path, err),
+					ExitCode: output.ExitAPIError,
+				}
+			}
+
+			state.Messages[msg.MessageID] = &mirror.Message{
+				FolderID:     l.folder.FolderID,
+				File:         file,
+				ReceivedTime: received,
+				Subject:      msg.Subject,
+				From:         msg.FromAddress,
+			}
+			fstate.Advance(received, msg.MessageID)
+			summary.New++
+			if err := changed(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A full listing shows every message, so anything unseen was deleted
+	if !cmd.Quick {
+		now := time.Now().UTC()
+		for id, entry := range state.Messages {
+			if seen[id] {
+				continue
+			}
+			if entry.DeletedAt == nil {
+				entry.DeletedAt = &now
+				summary.Deleted++
+			}
+			if cmd.Prune && !entry.Pruned {
+				if err := os.Remove(filepath.Join(cmd.Out, entry.File)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					fmt.Fprintf(os.Stderr, "Failed to prune %s: %v\n", entry.File, err)
+					continue
+				}
+				entry.Pruned = true
+			}
+		}
+		for id := range state.Folders {
+			if !slices.ContainsFunc(folders, func(f zoho.Folder) bool { return f.FolderID == id }) {
+				delete(state.Folders, id)
+			}
+		}
+	}
+
+	state.LastSync = time.Now().UTC()
+	if err := save(); err != nil {
+		return err
+	}
+
+	for _, entry := range state.Messages {
+		if entry.DeletedAt == nil {
+			summary.Total++
+		}
+	}
+	return fp.Formatter.Print(summary)
+}
+
+// listFolderForSync lists a folder's messages, oldest first. In quick mode it
+// stops paging at the folder's high-water mark and returns only newer messages.
+func listFolderForSync(ctx context.Context, mc zoho.MailService, folder zoho.Folder, state *mirror.State, quick bool) ([]zoho.MessageSummary, error) {
+	const pageSize = 200
+	fstate, synced := state.Folders[folder.FolderID]
+
+	iterator := zoho.NewPageIterator(func(start, limit int) ([]zoho.MessageSummary, error) {
+		return mc.ListMessages(ctx, folder.FolderID, start, limit)
+	}, pageSize)
+
+	var messages []zoho.MessageSummary
+	if quick && synced {
+	pages:
+		for start := 0; ; start += pageSize {
+			page, err := iterator.FetchPage(start)
+			if err != nil {
+				return nil, err
+			}
+			for _, msg := range page {
+				received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+				if received < fstate.HighWater {
+					break pages
+				}
+				// Messages sharing the high-water timestamp are not ordered by ID
+				if !fstate.Reached(received, msg.MessageID) {
+					messages = append(messages, msg)
+				}
+			}
+			if len(page) < pageSize {
+				break
+			}
+		}
+	} else {
+		var err error
+		if messages, err = iterator.FetchAll(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Oldest first, so the high-water mark only ever covers mirrored messages
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// writeMirrored atomically writes a message into the mirror
+func writeMirrored(out, file string, raw []byte) error {
+	path := filepath.Join(out, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, raw)
+}
+
+// moveMirrored moves a mirrored message to its new location
+func moveMirrored(out, from, to string) error {
+	if from == to {
+		return nil
+	}
+	dest := filepath.Join(out, to)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(out, from), dest)
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+)
+
+// templateHeaderPattern matches a header line at the top of a mail template
+var templateHeaderPattern = regexp.MustCompile(`(?i)^(subject|to|cc|bcc):\s?(.*)$`)
+
+// mailTemplate is a parsed --template file: optional Subject/To/Cc/Bcc header
+// lines, a blank line, then the body. Every field is a Go template.
+type mailTemplate struct {
+	headers map[string]*template.Template // keyed by lower-case header name
+	body    interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// renderedMail is a mail template executed with one set of variables
+type renderedMail struct {
+	To      string
+	Cc      string
+	Bcc     string
+	Subject string
+	Body    string
+}
+
+// loadMailTemplate parses a template file. Non-empty overrides (keyed like
+// headers, e.g. "subject") replace the file's headers and are templates too.
+// HTML bodies escape substituted values.
+func loadMailTemplate(path string, overrides map[string]string, html bool) (*mailTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read template %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	headers, body := splitTemplateHeaders(string(data))
+	for name, value := range overrides {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+
+	tmpl := &mailTemplate{headers: make(map[string]*template.Template)}
+	for name, text := range headers {
+		t, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, templateError(path, err)
+		}
+		tmpl.headers[name] = t
+	}
+
+	if html {
+		tmpl.body, err = htmltemplate.New("body").Option("missingkey=error").Parse(body)
+	} else {
+		tmpl.body, err = template.New("body").Option("missingkey=error").Parse(body)
+	}
+	if err != nil {
+		return nil, templateError(path, err)
+	}
+
+	return tmpl, nil
+}
+
+// splitTemplateHeaders splits leading header lines from the body. Without a
+// header block terminated by a blank line, the whole text is the body.
+func splitTemplateHeaders(text string) (map[string]string, string) {
+	headers := make(map[string]string)
+	rest := text
+	for rest != "" {
+		line, after, _ := strings.Cut(rest, "\n")
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			if len(headers) > 0 {
+				return headers, after
+			}
+			break
+		}
+		m := templateHeaderPattern.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		headers[strings.ToLower(m[1])] = m[2]
+		rest = after
+	}
+	return make(map[string]string), text
+}
+
+// render executes the template with vars
+func (t *mailTemplate) render(vars any) (*renderedMail, error) {
+	exec := func(name string) (string, error) {
+		h, ok := t.headers[name]
+		if !ok {
+			return "", nil
+		}
+		var buf bytes.Buffer
+		if err := h.Execute(&buf, vars); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	var mail renderedMail
+	var err error
+	if mail.To, err = exec("to"); err != nil {
+		return nil, err
+	}
+	if mail.Cc, err = exec("cc"); err != nil {
+		return nil, err
+	}
+	if mail.Bcc, err = exec("bcc"); err != nil {
+		return nil, err
+	}
+	if mail.Subject, err = exec("subject"); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	if err := t.body.Execute(&body, vars); err != nil {
+		return nil, err
+	}
+	mail.Body = body.String()
+
+	return &mail, nil
+}
+
+// loadTemplateVars reads a JSON object of template variables
+func loadTemplateVars(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Failed to read variables %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+
+	var vars map[string]any
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid variables file %s (expected a JSON object): %v", path, err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	return vars, nil
+}
+
+// templateError wraps a template parse error as a usage error
+func templateError(path string, err error) error {
+	return &output.CLIError{
+		Message:  fmt.Sprintf("Invalid template %s: %v", path, err),
+		ExitCode: output.ExitUsage,
+	}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailTemplateRender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "welcome.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("Subject: Welcome, {{.name}}\r\nCc: hr@example.com\r\n\r\nHi {{.name}},\nyour desk is {{.desk}}.\n"), 0o600))
+
+	tmpl, err := loadMailTemplate(path, nil, false)
+	require.NoError(t, err)
+	mail, err := tmpl.render(map[string]string{"name": "Ada", "desk": "4B"})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome, Ada", mail.Subject)
+	assert.Equal(t, "hr@example.com", mail.Cc)
+	assert.Equal(t, "", mail.To)
+	assert.Equal(t, "Hi Ada,\nyour desk is 4B.\n", mail.Body)
+
+	// Missing variables are errors rather than "<no value>"
+	_, err = tmpl.render(map[string]string{"name": "Ada"})
+	assert.Error(t, err)
+
+	// Overrides replace headers; HTML bodies escape values
+	tmpl, err = loadMailTemplate(path, map[string]string{"subject": "Hello {{.name}}"}, true)
+	require.NoError(t, err)
+	mail, err = tmpl.render(map[string]string{"name": "<Ada>", "desk": "4B"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello <Ada>", mail.Subject)
+	assert.Contains(t, mail.Body, "Hi &lt;Ada&gt;")
+}
+
+func TestSplitTemplateHeaders(t *testing.T) {
+	headers, body := splitTemplateHeaders("Dear team,\n\nSubject: not a header\n")
+	assert.Empty(t, headers)
+	assert.Equal(t, "Dear team,\n\nSubject: not a header\n", body)
+
+	headers, body = splitTemplateHeaders("To: {{.email}}\nsubject: Hi\n\nBody")
+	assert.Equal(t, map[string]string{"to": "{{.email}}", "subject": "Hi"}, headers)
+	assert.Equal(t, "Body", body)
+}
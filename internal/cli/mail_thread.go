@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/htmltext"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// ConversationEntry is one message of a thread's conversation view
+type ConversationEntry struct {
+	MessageID     string
+	From          string
+	Subject       string
+	Date          string
+	ReceivedTime  string
+	HasAttachment bool
+	Body          string // plain text with quoted replies removed
+}
+
+// printConversation fetches the body of every message in a thread and prints
+// them oldest first: a transcript in plain/rich mode, an array in JSON mode
+func printConversation(ctx context.Context, mc zoho.MailService, fp *FormatterProvider, src *messageSource, messages []zoho.MessageSummary, outputMode string) error {
+	if len(messages) == 0 {
+		return (&output.CLIError{
+			Message:  "No messages found in this thread",
+			ExitCode: output.ExitNotFound,
+		}).WithHint("Pass the thread's folder with --folder, or scan further back with --limit")
+	}
+
+	sorted := slices.Clone(messages)
+	slices.SortStableFunc(sorted, func(a, b zoho.MessageSummary) int {
+		at, _ := strconv.ParseInt(a.ReceivedTime, 10, 64)
+		bt, _ := strconv.ParseInt(b.ReceivedTime, 10, 64)
+		return cmp.Compare(at, bt)
+	})
+
+	entries := make([]ConversationEntry, 0, len(sorted))
+	for _, msg := range sorted {
+		content, err := mc.GetMessageContent(ctx, src.folderOf(msg), msg.MessageID)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to fetch message content %s: %v", msg.MessageID, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		entries = append(entries, ConversationEntry{
+			MessageID:     msg.MessageID,
+			From:          msg.FromAddress,
+			Subject:       msg.Subject,
+			Date:          formatReceivedTime(msg.ReceivedTime),
+			ReceivedTime:  msg.ReceivedTime,
+			HasAttachment: msg.HasAttachment == "1" || msg.HasAttachment == "true",
+			Body:          stripQuotedReply(content.Content),
+		})
+	}
+
+	if outputMode == "json" {
+		columns := []output.Column{
+			{Name: "From", Key: "From"},
+			{Name: "Date", Key: "Date"},
+			{Name: "Body", Key: "Body"},
+		}
+		return fp.Formatter.PrintList(entries, columns)
+	}
+	renderConversation(os.Stdout, entries)
+	return nil
+}
+
+// renderConversation writes a readable transcript of a conversation
+func renderConversation(w io.Writer, entries []ConversationEntry) {
+	fmt.Fprintf(w, "%s (%d messages)\n", entries[0].Subject, len(entries))
+	for i, e := range entries {
+		attachment := ""
+		if e.HasAttachment {
+			attachment = " [attachment]"
+		}
+		fmt.Fprintf(w, "\n[%d/%d] %s, %s%s\n", i+1, len(entries), e.From, e.Date, attachment)
+		if e.Body != "" {
+			fmt.Fprintf(w, "%s\n", e.Body)
+		}
+	}
+}
+
+var (
+	// htmlTag matches one HTML tag, capturing whether it closes and its name
+	htmlTag = regexp.MustCompile(`<(/?)([A-Za-z][A-Za-z0-9]*)[^>]*>`)
+
+	// quotedClass matches the wrappers mail clients put around quoted replies
+	quotedClass = regexp.MustCompile(`(?i)class="[^"]*\b(gmail_quote|zmail_extra|yahoo_quoted|moz-cite-prefix)\b`)
+
+	// replyHeader matches the line a mail client writes above a quoted reply
+	replyHeader = regexp.MustCompile(`(?i)^(on .+ wrote:|-+ ?original message ?-+|-{2,} ?on .+ wrote ?-{2,}|_{10,})$`)
+
+	// footnote matches a link footnote line written by htmltext.Text
+	footnote = regexp.MustCompile(`^\[(\d+)\] \S+$`)
+)
+
+// stripQuotedReply converts an HTML body to text without the quoted earlier
+// messages a reply carries. A body that is nothing but a quote is kept whole.
+func stripQuotedReply(htmlContent string) string {
+	lines := strings.Split(htmltext.Text(removeQuotedElements(htmlContent)), "\n")
+
+	// Set the link footnotes aside so cutting at a reply header keeps them
+	notesAt := len(lines)
+	for notesAt > 0 && footnote.MatchString(lines[notesAt-1]) {
+		notesAt--
+	}
+	notes := lines[notesAt:]
+
+	var kept []string
+	for _, line := range lines[:notesAt] {
+		trimmed := strings.TrimSpace(line)
+		if replyHeader.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	body := strings.TrimSpace(strings.Join(kept, "\n"))
+	if body == "" {
+		return htmltext.Text(htmlContent)
+	}
+
+	var used []string
+	for _, note := range notes {
+		if strings.Contains(body, "["+footnote.FindStringSubmatch(note)[1]+"]") {
+			used = append(used, note)
+		}
+	}
+	if len(used) > 0 {
+		body += "\n\n" + strings.Join(used, "\n")
+	}
+	return body
+}
+
+// removeQuotedElements drops <blockquote> elements and quote wrappers,
+// including everything nested inside them
+func removeQuotedElements(s string) string {
+	var b strings.Builder
+	skipName, depth, last := "", 0, 0
+	for _, m := range htmlTag.FindAllStringSubmatchIndex(s, -1) {
+		closing := m[3] > m[2]
+		name := strings.ToLower(s[m[4]:m[5]])
+		tag := s[m[0]:m[1]]
+
+		if depth > 0 {
+			if name == skipName {
+				if closing {
+					depth--
+				} else if !strings.HasSuffix(tag, "/>") {
+					depth++
+				}
+			}
+			if depth == 0 {
+				last = m[1]
+			}
+			continue
+		}
+
+		if !closing && (name == "blockquote" || quotedClass.MatchString(tag)) {
+			b.WriteString(s[last:m[0]])
+			skipName, depth = name, 1
+		}
+	}
+	if depth == 0 {
+		b.WriteString(s[last:])
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripQuotedReply(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{"plain", "<p>Sounds good.</p><p>See you &amp; Bob<br>at 5</p>", "Sounds good.\n\nSee you & Bob\nat 5"},
+		{"blockquote", "<div>Yes<blockquote><div>Lunch?<blockquote>older</blockquote></div></blockquote></div><div>-- Ann</div>", "Yes\n-- Ann"},
+		{"zoho wrapper", `<div>Done.</div><div class="zmail_extra"><div>---- On Mon, 1 Jan 2025 Bob wrote ----</div><div>Ping</div></div>`, "Done."},
+		{"reply header", "Thanks!<br><br>On Mon, Jan 1, 2025 at 9:00 AM Bob &lt;bob@example.com&gt; wrote:<br>&gt; Ping", "Thanks!"},
+		{"quote markers", "Agreed<br>&gt; earlier line<br>more", "Agreed\nmore"},
+		{"footnotes kept", `<p>See <a href="https://x.test/a">this</a></p><p>On Mon, Bob wrote:</p><p><a href="https://x.test/b">old</a></p>`, "See this[1]\n\n[1] https://x.test/a"},
+		{"only a quote", "<blockquote>Forwarded text</blockquote>", "> Forwarded text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stripQuotedReply(tt.html))
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+const (
+	// watchPageSize is how many messages each poll request lists
+	watchPageSize = 50
+
+	// watchMinInterval keeps a watcher well within the 25 requests/minute limit
+	watchMinInterval = 5 * time.Second
+)
+
+// WatchEvent is emitted for each new message, as an NDJSON line in JSON mode
+type WatchEvent struct {
+	MessageID     string
+	ThreadID      string
+	FolderID      string
+	Folder        string
+	From          string
+	Subject       string
+	Summary       string
+	ReceivedTime  string
+	Date          string
+	Unread        bool
+	HasAttachment bool
+}
+
+// watchCursor records the newest message a watcher has seen, so restarts
+// never emit a message twice
+type watchCursor struct {
+	FolderID  string   `json:"folderId"`
+	Search    string   `json:"search,omitempty"`
+	HighWater int64    `json:"highWater"` // ReceivedTime in Unix milliseconds
+	SeenIDs   []string `json:"seenIds"`   // messages received exactly at HighWater
+
+	// Messages whose rule actions failed, by ID, retried by the next poll
+	Failed map[string]*ruleFailure `json:"failed,omitempty"`
+}
+
+// seen reports whether a message is at or below the cursor
+func (c *watchCursor) seen(received int64, messageID string) bool {
+	return received < c.HighWater || (received == c.HighWater && slices.Contains(c.SeenIDs, messageID))
+}
+
+// advance moves the cursor past a message
+func (c *watchCursor) advance(received int64, messageID string) {
+	switch {
+	case received > c.HighWater:
+		c.HighWater, c.SeenIDs = received, []string{messageID}
+	case received == c.HighWater && !slices.Contains(c.SeenIDs, messageID):
+		c.SeenIDs = append(c.SeenIDs, messageID)
+	}
+}
+
+// MailWatchCmd polls a folder and emits each new message as it arrives
+type MailWatchCmd struct {
+	Folder   string        `help:"Folder name, path or ID, or @name for a saved search" default:"Inbox" short:"f"`
+	Search   string        `help:"Only emit messages matching this query (see mail messages search), e.g. 'from:alerts@example.com (subject:down OR subject:error)'"`
+	Exec     string        `help:"Shell command to run per message; fields are passed as ZOH_* env vars and the event as JSON on stdin"`
+	Interval time.Duration `help:"Time between polls" default:"30s"`
+	Cursor   string        `help:"Cursor file (default: one per profile, folder and search under the data directory)" predictor:"file"`
+	Once     bool          `help:"Poll once and exit (for cron)"`
+}
+
+// Run executes the watch command
+func (cmd *MailWatchCmd) Run(sp *ServiceProvider, globals *Globals, cfg *config.Config) error {
+	query, err := zoho.ParseSearch(cmd.Search)
+	if err != nil {
+		return searchSyntaxError(err)
+	}
+	if cmd.Interval < watchMinInterval && !cmd.Once {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("--interval must be at least %s", watchMinInterval),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	mailClient, err := sp.Mail()
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C stops watching; the cursor is saved after every message
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	src, err := resolveMessageSource(ctx, mailClient, cfg.Profile, cmd.Folder)
+	if err != nil {
+		return err
+	}
+	folder := src.Folder
+	if query != nil {
+		if err := zoho.ResolveSearch(query, searchResolver(ctx, mailClient)); err != nil {
+			return err
+		}
+	}
+
+	cursorPath := cmd.Cursor
+	if cursorPath == "" {
+		cursorPath = watchCursorPath(cfg.Profile, folder.FolderID, cmd.Search)
+	}
+	cursor, started, err := loadWatchCursor(cursorPath, folder.FolderID, cmd.Search)
+	if err != nil {
+		return err
+	}
+
+	// First run: start from the newest message instead of replaying the folder
+	if !started {
+		newest, err := src.list(ctx, mailClient, 0, watchPageSize)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		}
+		for _, msg := range newest {
+			received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+			cursor.advance(received, msg.MessageID)
+		}
+		if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+			return err
+		}
+		if cmd.Once {
+			return nil
+		}
+	}
+
+	jsonMode := globals.ResolvedOutput() == "json"
+	for {
+		messages, err := pollNewMessages(ctx, mailClient, src, cursor)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && cmd.Once:
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Failed to list %s: %v", folder.Path, err),
+				ExitCode: output.ExitAPIError,
+			}
+		case err != nil:
+			// Keep watching through transient failures
+			fmt.Fprintf(os.Stderr, "Failed to list %s: %v (retrying in %s)\n", folder.Path, err, cmd.Interval)
+		default:
+			for _, msg := range messages {
+				if query == nil || zoho.MatchSearch(query, &msg) {
+					if err := cmd.emit(ctx, newWatchEvent(msg, src), jsonMode, globals.DryRun); err != nil {
+						return err
+					}
+				}
+				received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+				cursor.advance(received, msg.MessageID)
+				if err := saveWatchCursor(cursorPath, cursor, globals.DryRun); err != nil {
+					return err
+				}
+			}
+		}
+
+		if cmd.Once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cmd.Interval):
+		}
+	}
+}
+
+// emit prints an event and runs the --exec hook for it
+func (cmd *MailWatchCmd) emit(ctx context.Context, event WatchEvent, jsonMode, dryRun bool) error {
+	if jsonMode {
+		// One compact object per line (NDJSON)
+		if err := json.NewEncoder(os.Stdout).Encode(event); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(os.Stdout, "%s\t%s\t%s\t%s\n", event.Date, event.From, event.Subject, event.MessageID)
+	}
+
+	if cmd.Exec == "" {
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would run %q for message %s\n", cmd.Exec, event.MessageID)
+		return nil
+	}
+	if err := runWatchHook(ctx, cmd.Exec, event); err != nil && ctx.Err() == nil {
+		// A failing hook is reported but does not stop the watcher
+		fmt.Fprintf(os.Stderr, "Hook failed for message %s: %v\n", event.MessageID, err)
+	}
+	return nil
+}
+
+// pollNewMessages lists the messages newer than the cursor, and older ones it
+// holds for a retry, oldest first. Paging stops at the first page that
+// reaches past them, so a quiet folder costs one request per poll.
+func pollNewMessages(ctx context.Context, mc zoho.MailService, src *messageSource, cursor *watchCursor) ([]zoho.MessageSummary, error) {
+	floor := cursor.HighWater
+	for _, f := range cursor.Failed {
+		floor = min(floor, f.ReceivedTime)
+	}
+
+	var messages []zoho.MessageSummary
+	for start := 0; ; start += watchPageSize {
+		page, err := src.list(ctx, mc, start, watchPageSize)
+		if err != nil {
+			return nil, err
+		}
+		reached := false
+		for _, msg := range page {
+			received, _ := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+			if received < floor {
+				reached = true
+				continue
+			}
+			if !cursor.seen(received, msg.MessageID) || cursor.Failed[msg.MessageID] != nil {
+				messages = append(messages, msg)
+			}
+		}
+		if reached || len(page) < watchPageSize {
+			break
+		}
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// newWatchEvent converts a message summary into an event
+func newWatchEvent(msg zoho.MessageSummary, src *messageSource) WatchEvent {
+	return WatchEvent{
+		MessageID:     msg.MessageID,
+		ThreadID:      msg.ThreadID,
+		FolderID:      src.folderOf(msg),
+		Folder:        src.Path,
+		From:          msg.FromAddress,
+		Subject:       msg.Subject,
+		Summary:       msg.Summary,
+		ReceivedTime:  msg.ReceivedTime,
+		Date:          formatReceivedTime(msg.ReceivedTime),
+		Unread:        msg.Status == "0",
+		HasAttachment: msg.HasAttachment == "1" || msg.HasAttachment == "true",
+	}
+}
+
+// runWatchHook runs command through the shell with the event in its environment and on stdin
+func runWatchHook(ctx context.Context, command string, event WatchEvent) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	c.Stdin = bytes.NewReader(append(data, '\n'))
+	// Hook output goes to stderr so stdout stays a clean event stream
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"ZOH_MESSAGE_ID="+event.MessageID,
+		"ZOH_THREAD_ID="+event.ThreadID,
+		"ZOH_FOLDER_ID="+event.FolderID,
+		"ZOH_FOLDER="+event.Folder,
+		"ZOH_FROM="+event.From,
+		"ZOH_SUBJECT="+event.Subject,
+		"ZOH_SUMMARY="+event.Summary,
+		"ZOH_RECEIVED_TIME="+event.ReceivedTime,
+		"ZOH_UNREAD="+strconv.FormatBool(event.Unread),
+		"ZOH_HAS_ATTACHMENT="+strconv.FormatBool(event.HasAttachment),
+	)
+	return c.Run()
+}
+
+// watchCursorPath returns the default cursor file for a profile, folder and search.
+// Typically ~/.local/share/zoh/watch/<profile>-<hash>.json on Linux
+func watchCursorPath(profile, folderID, search string) string {
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	sum := sha256.Sum256([]byte(folderID + "\x00" + search))
+	return filepath.Join(config.DataDir(), "watch", profile+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadWatchCursor reads a cursor file; started is false when there is none yet
+func loadWatchCursor(path, folderID, search string) (cursor *watchCursor, started bool, err error) {
+	cursor = &watchCursor{FolderID: folderID, Search: search}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cursor, false, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, cursor)
+	}
+	if err == nil && cursor.FolderID != folderID {
+		err = fmt.Errorf("it belongs to folder %s", cursor.FolderID)
+	}
+	if err != nil {
+		return nil, false, &output.CLIError{
+			Message:  fmt.Sprintf("Cannot use cursor %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return cursor, true, nil
+}
+
+// saveWatchCursor writes the cursor file; dry runs leave it untouched
+func saveWatchCursor(path string, cursor *watchCursor, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	data, err := json.MarshalIndent(cursor, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+	}
+	if err == nil {
+		err = fileutil.WriteAtomic(path, append(data, '\n'))
+	}
+	if err != nil {
+		return &output.CLIError{
+			Message:  fmt.Sprintf("Failed to save cursor %s: %v", path, err),
+			ExitCode: output.ExitGeneral,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// progressWidth is the width of the bar itself, in characters
+const progressWidth = 24
+
+// showProgress reports whether transfers should draw a progress bar:
+// only in rich mode, and only when stderr is a terminal
+func showProgress(globals *Globals) bool {
+	return globals.ResolvedOutput() == "rich" && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// progressBar draws a single-line transfer progress bar, redrawn in place
+type progressBar struct {
+	w     io.Writer
+	label string
+	last  time.Time
+	drawn bool
+	mu    sync.Mutex
+}
+
+// update redraws the bar, at most every 100ms until the transfer completes
+func (p *progressBar) update(done, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if p.drawn && (total < 0 || done < total) && now.Sub(p.last) < 100*time.Millisecond {
+		return
+	}
+	p.last, p.drawn = now, true
+
+	if total <= 0 {
+		fmt.Fprintf(p.w, "\r\x1b[K%s  %s", p.label, formatBytes(done))
+		return
+	}
+	filled := int(min(done, total) * progressWidth / total)
+	fmt.Fprintf(p.w, "\r\x1b[K%s [%s%s] %3d%%  %s / %s", p.label,
+		strings.Repeat("=", filled), strings.Repeat(" ", progressWidth-filled),
+		min(done, total)*100/total, formatBytes(done), formatBytes(total))
+}
+
+// finish ends the bar's line so later output starts on a fresh one
+func (p *progressBar) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// withProgress returns a context that draws a progress bar labelled label on
+// stderr for the attachment transfers made with it. Call done once the
+// transfer returns. When enabled is false the context is returned unchanged.
+func withProgress(ctx context.Context, enabled bool, label string) (_ context.Context, done func()) {
+	if !enabled {
+		return ctx, func() {}
+	}
+	bar := &progressBar{w: os.Stderr, label: label}
+	return zoho.WithProgress(ctx, bar.update), bar.finish
+}
+
+// checkAttachmentSizes fails before anything is uploaded if a file is missing
+// or larger than Zoho Mail accepts
+func checkAttachmentSizes(paths []string) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return &output.CLIError{
+				Message:  fmt.Sprintf("Cannot attach %s: %v", path, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+		if err := zoho.CheckAttachmentSize(path, info.Size()); err != nil {
+			return attachmentTooLarge(err)
+		}
+	}
+	return nil
+}
+
+// attachmentTooLarge converts an *zoho.AttachmentTooLargeError into a usage error
+func attachmentTooLarge(err error) error {
+	return (&output.CLIError{
+		Message:  err.Error(),
+		ExitCode: output.ExitUsage,
+	}).WithHint("Share large files as a link instead, e.g. from Zoho WorkDrive")
+}
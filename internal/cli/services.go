@@ -5,6 +5,8 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/oauth2"
+
 	"github.com/SeMmyT/zohcli/internal/auth"
 	"github.com/SeMmyT/zohcli/internal/config"
 	"github.com/SeMmyT/zohcli/internal/output"
@@ -12,6 +14,45 @@ import (
 	"github.com/SeMmyT/zohcli/internal/zoho"
 )
 
+// newTokenSource returns the token source for API clients.
+// A static access token (--access-token / ZOH_ACCESS_TOKEN) takes precedence
+// over the cached OAuth tokens in the secrets store. Replaying a cassette needs
+// no credentials at all.
+func newTokenSource(cfg *config.Config) (oauth2.TokenSource, error) {
+	if cfg.AccessToken != "" {
+		return oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: cfg.AccessToken,
+			TokenType:   "Bearer",
+		}), nil
+	}
+
+	// Replayed cassettes are already scrubbed, so no real credentials are needed
+	if cfg.Cassette != "" && cfg.CassetteMode == zoho.CassetteReplay {
+		return oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: "replay",
+			TokenType:   "Bearer",
+		}), nil
+	}
+
+	store, err := secrets.NewStore()
+	if err != nil {
+		return nil, &output.CLIError{
+			ExitCode: output.ExitGeneral,
+			Message:  fmt.Sprintf("Failed to initialize secrets store: %v", err),
+		}
+	}
+
+	tokenCache, err := auth.NewTokenCache(cfg, store)
+	if err != nil {
+		return nil, &output.CLIError{
+			ExitCode: output.ExitGeneral,
+			Message:  fmt.Sprintf("Failed to initialize token cache: %v", err),
+		}
+	}
+
+	return tokenCache, nil
+}
+
 // ServiceProvider lazily creates and caches Zoho service clients.
 type ServiceProvider struct {
 	cfg *config.Config
@@ -33,25 +74,13 @@ func NewServiceProvider(cfg *config.Config) *ServiceProvider {
 // Admin returns the AdminService, creating it on first call.
 func (sp *ServiceProvider) Admin() (zoho.AdminService, error) {
 	sp.adminOnce.Do(func() {
-		store, err := secrets.NewStore()
+		tokenSource, err := newTokenSource(sp.cfg)
 		if err != nil {
-			sp.adminErr = &output.CLIError{
-				ExitCode: output.ExitGeneral,
-				Message:  fmt.Sprintf("Failed to initialize secrets store: %v", err),
-			}
+			sp.adminErr = err
 			return
 		}
 
-		tokenCache, err := auth.NewTokenCache(sp.cfg, store)
-		if err != nil {
-			sp.adminErr = &output.CLIError{
-				ExitCode: output.ExitGeneral,
-				Message:  fmt.Sprintf("Failed to initialize token cache: %v", err),
-			}
-			return
-		}
-
-		adminClient, err := zoho.NewAdminClient(sp.cfg, tokenCache)
+		adminClient, err := zoho.NewAdminClient(sp.cfg, tokenSource)
 		if err != nil {
 			if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "unauthorized") {
 				sp.adminErr = &output.CLIError{
@@ -75,25 +104,13 @@ func (sp *ServiceProvider) Admin() (zoho.AdminService, error) {
 // Mail returns the MailService, creating it on first call.
 func (sp *ServiceProvider) Mail() (zoho.MailService, error) {
 	sp.mailOnce.Do(func() {
-		store, err := secrets.NewStore()
-		if err != nil {
-			sp.mailErr = &output.CLIError{
-				ExitCode: output.ExitGeneral,
-				Message:  fmt.Sprintf("Failed to initialize secrets store: %v", err),
-			}
-			return
-		}
-
-		tokenCache, err := auth.NewTokenCache(sp.cfg, store)
+		tokenSource, err := newTokenSource(sp.cfg)
 		if err != nil {
-			sp.mailErr = &output.CLIError{
-				ExitCode: output.ExitGeneral,
-				Message:  fmt.Sprintf("Failed to initialize token cache: %v", err),
-			}
+			sp.mailErr = err
 			return
 		}
 
-		mailClient, err := zoho.NewMailClient(sp.cfg, tokenCache)
+		mailClient, err := zoho.NewMailClient(sp.cfg, tokenSource)
 		if err != nil {
 			if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "unauthorized") {
 				sp.mailErr = &output.CLIError{
@@ -149,7 +149,8 @@ func (cmd *SetupCmd) Run(cfg *config.Config, fp *FormatterProvider) error {
 	fmt.Fprintf(os.Stderr, "    Region:      %s\n", region)
 	fmt.Fprintf(os.Stderr, "    Credentials: %s\n", storageType)
 	fmt.Fprintf(os.Stderr, "    Expires:     %s\n", token.Expiry.Format(time.RFC3339))
-	fmt.Fprintf(os.Stderr, "    Config:      %s\n\n", config.ConfigPath())
+	fmt.Fprintf(os.Stderr, "    Profile:     %s\n", cfg.Profile)
+	fmt.Fprintf(os.Stderr, "    Config:      %s\n\n", config.ProfilePath(cfg.Profile))
 	fmt.Fprintf(os.Stderr, "  Try it out:\n\n")
 	fmt.Fprintf(os.Stderr, "    zoh admin users list\n")
 	fmt.Fprintf(os.Stderr, "    zoh mail messages list\n")
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/output"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// vacationDayLayout formats dates for vacation reply templates
+const vacationDayLayout = "Monday, January 2, 2006"
+
+// relativeVacationPattern matches relative times such as +4h, +3d and +2w
+var relativeVacationPattern = regexp.MustCompile(`^\+(\d+)([hdw])$`)
+
+// VacationFlags are the flags describing a vacation auto-reply, shared by
+// mail settings vacation set and admin users vacation set
+type VacationFlags struct {
+	From     string `help:"Start: now, today, tomorrow, +4h, +3d, +2w, a weekday, YYYY-MM-DD [HH:MM] or MM/DD/YYYY HH:MM:SS" default:"now"`
+	Until    string `help:"End, in the same forms; a day without a time means the end of that day" required:"" aliases:"to"`
+	TZ       string `help:"IANA time zone of --from and --until, e.g. Europe/Berlin (default: the mailbox's time zone, or local time)" name:"tz"`
+	Subject  string `help:"Auto-reply subject (overrides the template's Subject header)"`
+	Content  string `help:"Auto-reply message content"`
+	Template string `help:"Go template file for the reply: an optional Subject header line, a blank line, then the message. Fields: .Name, .Email, .From, .Until, .Back and --vars" predictor:"file"`
+	Vars     string `help:"JSON file of variables for --template" predictor:"file"`
+	Interval int    `help:"Minutes before replying to the same sender again" default:"1440"`
+	SendTo   string `help:"Who gets the reply: all, contacts, noncontacts, org, nonOrgAll" default:"all" enum:"all,contacts,noncontacts,org,nonOrgAll"`
+}
+
+// reply builds the vacation reply for a mailbox owned by name and email.
+// mailboxTZ is the mailbox's time zone, or "" when unknown; Zoho reads the
+// dates in it, so they are converted to it from --tz.
+func (f *VacationFlags) reply(now time.Time, mailboxTZ, name, email string) (*zoho.VacationReply, error) {
+	mailboxLoc, err := time.LoadLocation(mailboxTZ)
+	if mailboxTZ == "" || err != nil {
+		mailboxLoc = nil
+	}
+	loc := mailboxLoc
+	if f.TZ != "" {
+		if loc, err = time.LoadLocation(f.TZ); err != nil {
+			return nil, &output.CLIError{
+				Message:  fmt.Sprintf("Invalid time zone %q: %v", f.TZ, err),
+				ExitCode: output.ExitUsage,
+			}
+		}
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	if mailboxLoc == nil {
+		mailboxLoc = loc
+	}
+
+	from, err := parseVacationTime(f.From, loc, now, false)
+	if err != nil {
+		return nil, &output.CLIError{Message: fmt.Sprintf("Invalid --from: %v", err), ExitCode: output.ExitUsage}
+	}
+	until, err := parseVacationTime(f.Until, loc, now, true)
+	if err != nil {
+		return nil, &output.CLIError{Message: fmt.Sprintf("Invalid --until: %v", err), ExitCode: output.ExitUsage}
+	}
+	if !until.After(from) {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("--until (%s) must be after --from (%s)", formatScheduled(until), formatScheduled(from)),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if !until.After(now) {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("--until %s is in the past", f.Until),
+			ExitCode: output.ExitUsage,
+		}
+	}
+
+	subject, content, err := f.message(map[string]any{
+		"Name":  name,
+		"Email": email,
+		"From":  from.Format(vacationDayLayout),
+		"Until": until.Format(vacationDayLayout),
+		"Back":  until.Add(time.Second).Format(vacationDayLayout),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vacation := &zoho.VacationReply{
+		FromDate:   from.In(mailboxLoc).Format(zoho.VacationDateLayout),
+		ToDate:     until.In(mailboxLoc).Format(zoho.VacationDateLayout),
+		SendingInt: f.Interval,
+		Subject:    subject,
+		Content:    content,
+		SendTo:     f.SendTo,
+	}
+	if err := vacation.Validate(); err != nil {
+		return nil, &output.CLIError{
+			Message:  fmt.Sprintf("Invalid vacation reply: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	return vacation, nil
+}
+
+// message returns the subject and content from flags, or from --template
+// rendered with vars and --vars. Templates ending in .html are HTML.
+func (f *VacationFlags) message(vars map[string]any) (subject, content string, err error) {
+	switch {
+	case f.Template == "" && f.Vars != "":
+		return "", "", &output.CLIError{Message: "--vars requires --template", ExitCode: output.ExitUsage}
+	case f.Template == "":
+		if f.Subject == "" || f.Content == "" {
+			return "", "", &output.CLIError{Message: "missing flags: --subject and --content (or use --template)", ExitCode: output.ExitUsage}
+		}
+		return f.Subject, f.Content, nil
+	case f.Content != "":
+		return "", "", &output.CLIError{Message: "--content cannot be combined with --template", ExitCode: output.ExitUsage}
+	}
+
+	html := strings.EqualFold(filepath.Ext(f.Template), ".html")
+	tmpl, err := loadMailTemplate(f.Template, map[string]string{"subject": f.Subject}, html)
+	if err != nil {
+		return "", "", err
+	}
+	if f.Vars != "" {
+		extra, err := loadTemplateVars(f.Vars)
+		if err != nil {
+			return "", "", err
+		}
+		for k, v := range extra {
+			vars[k] = v
+		}
+	}
+	msg, err := tmpl.render(vars)
+	if err != nil {
+		return "", "", &output.CLIError{
+			Message:  fmt.Sprintf("Failed to render template: %v", err),
+			ExitCode: output.ExitUsage,
+		}
+	}
+	if msg.Subject == "" {
+		return "", "", &output.CLIError{Message: "missing flags: --subject (or a Subject header in the template)", ExitCode: output.ExitUsage}
+	}
+	return msg.Subject, strings.TrimSpace(msg.Body), nil
+}
+
+// parseVacationTime parses a --from or --until value in loc. Values naming a
+// day without a time of day mean its start, or its end when end is set.
+func parseVacationTime(value string, loc *time.Location, now time.Time, end bool) (time.Time, error) {
+	now = now.In(loc)
+	day := func(t time.Time) time.Time {
+		if end {
+			return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc)
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch v {
+	case "now":
+		return now, nil
+	case "today":
+		return day(now), nil
+	case "tomorrow":
+		return day(now.AddDate(0, 0, 1)), nil
+	}
+
+	if m := relativeVacationPattern.FindStringSubmatch(v); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is out of range", value)
+		}
+		switch m[2] {
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "d":
+			return day(now.AddDate(0, 0, n)), nil
+		default:
+			return day(now.AddDate(0, 0, 7*n)), nil
+		}
+	}
+
+	// A weekday means its next occurrence after today
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if v == name || v == name[:3] {
+			days := (int(wd) - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return day(now.AddDate(0, 0, days)), nil
+		}
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return day(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range append(slices.Clone(sendAtLayouts), zoho.VacationDateLayout) {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (use e.g. tomorrow, +3d, friday or YYYY-MM-DD [HH:MM])", value)
+}
+
+// VacationStatus is the typed view of a configured vacation reply
+type VacationStatus struct {
+	Status          string `json:"status"` // scheduled, active or ended
+	From            string `json:"from"`
+	Until           string `json:"until"`
+	TimeZone        string `json:"timeZone"`
+	Subject         string `json:"subject"`
+	Content         string `json:"content"`
+	SendTo          string `json:"sendTo"`
+	IntervalMinutes int    `json:"intervalMinutes"`
+}
+
+// newVacationStatus describes a vacation reply at now; its dates are read in
+// the mailbox's time zone, or local time when that is unknown
+func newVacationStatus(v *zoho.VacationReply, mailboxTZ string, now time.Time) VacationStatus {
+	loc, err := time.LoadLocation(mailboxTZ)
+	if mailboxTZ == "" || err != nil {
+		loc = time.Local
+	}
+
+	status := VacationStatus{
+		Status:          "unknown",
+		From:            v.FromDate,
+		Until:           v.ToDate,
+		TimeZone:        loc.String(),
+		Subject:         v.Subject,
+		Content:         v.Content,
+		SendTo:          v.SendTo,
+		IntervalMinutes: v.SendingInt,
+	}
+	from, until, err := v.Period(loc)
+	if err != nil {
+		return status
+	}
+
+	status.From = from.Format(time.RFC3339)
+	status.Until = until.Format(time.RFC3339)
+	switch {
+	case now.Before(from):
+		status.Status = "scheduled"
+	case now.Before(until):
+		status.Status = "active"
+	default:
+		status.Status = "ended"
+	}
+	return status
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVacationTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) // a Friday
+
+	for _, tc := range []struct {
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{"now", false, now.In(berlin)},
+		{"tomorrow", false, time.Date(2026, 10, 17, 0, 0, 0, 0, berlin)},
+		{"tomorrow", true, time.Date(2026, 10, 17, 23, 59, 59, 0, berlin)},
+		{"+3d", true, time.Date(2026, 10, 19, 23, 59, 59, 0, berlin)},
+		{"+4h", false, now.Add(4 * time.Hour).In(berlin)},
+		{"friday", false, time.Date(2026, 10, 23, 0, 0, 0, 0, berlin)},
+		{"Mon", true, time.Date(2026, 10, 19, 23, 59, 59, 0, berlin)},
+		{"2026-11-03", true, time.Date(2026, 11, 3, 23, 59, 59, 0, berlin)},
+		{"2026-11-03 09:30", true, time.Date(2026, 11, 3, 9, 30, 0, 0, berlin)},
+		{"11/03/2026 18:00:00", false, time.Date(2026, 11, 3, 18, 0, 0, 0, berlin)},
+	} {
+		got, err := parseVacationTime(tc.value, berlin, now, tc.end)
+		require.NoError(t, err, tc.value)
+		assert.True(t, tc.want.Equal(got), "%s: got %s, want %s", tc.value, got, tc.want)
+	}
+
+	_, err = parseVacationTime("next week", berlin, now, false)
+	assert.Error(t, err)
+}
+
+func TestVacationFlagsReply(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	flags := VacationFlags{
+		From: "2026-11-02", Until: "2026-11-03", TZ: "America/New_York",
+		Subject: "Away", Content: "Back Wednesday", Interval: 60, SendTo: "org",
+	}
+
+	// Dates given in --tz are sent in the mailbox's time zone
+	vacation, err := flags.reply(now, "Europe/Berlin", "Jane", "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "11/02/2026 06:00:00", vacation.FromDate)
+	assert.Equal(t, "11/04/2026 05:59:59", vacation.ToDate)
+	assert.Equal(t, 60, vacation.SendingInt)
+	assert.Equal(t, "org", vacation.SendTo)
+
+	flags.Until = "2026-11-01"
+	_, err = flags.reply(now, "Europe/Berlin", "Jane", "jane@example.com")
+	assert.ErrorContains(t, err, "must be after --from")
+
+	flags.Until, flags.Interval = "2026-11-03", 0
+	_, err = flags.reply(now, "", "Jane", "jane@example.com")
+	assert.ErrorContains(t, err, "sendingInt")
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/yosuke-furukawa/json5/encoding/json5"
 )
@@ -17,19 +18,53 @@ type Config struct {
 	ClientSecret  string `json:"client_secret,omitempty"`
 	OrgID         string `json:"org_id,omitempty"`
 	AccountID     string `json:"account_id,omitempty"`
+	Email         string `json:"email,omitempty"`
 	DefaultOutput string `json:"default_output,omitempty"`
+
+	// Profile is the name of the profile this config was loaded from (not persisted)
+	Profile string `json:"-"`
+
+	// APIBase overrides every regional endpoint with a single base URL (not persisted)
+	// Used to point the CLI at a local fake server for offline testing
+	APIBase string `json:"-"`
+
+	// AccessToken is a static bearer token that bypasses the token cache (not persisted)
+	AccessToken string `json:"-"`
+
+	// Cassette is an HTTP cassette file to record to or replay from (not persisted)
+	Cassette string `json:"-"`
+
+	// CassetteMode is "record" or "replay" (not persisted)
+	CassetteMode string `json:"-"`
+
+	// TransferTimeout bounds each attachment upload or download (not persisted)
+	// Zero uses the client default
+	TransferTimeout time.Duration `json:"-"`
 }
 
-// Load reads config from XDG path, returns defaults if file doesn't exist
+// Load reads the active profile's config, returns defaults if file doesn't exist
 func Load() (*Config, error) {
-	path := ConfigPath()
+	return LoadProfile(ActiveProfile())
+}
+
+// LoadProfile reads config for a named profile, returns defaults if file doesn't exist
+func LoadProfile(name string) (*Config, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+
+	path := ProfilePath(name)
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Return defaults when no config file exists
 			return &Config{
-				Region: "", // Empty means "not set" - will be resolved to "us" in cli.BeforeApply
+				Region:  "", // Empty means "not set" - will be resolved to "us" in cli.BeforeApply
+				Profile: name,
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to read config: %w", err)
@@ -39,13 +74,14 @@ func Load() (*Config, error) {
 	if err := json5.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
+	cfg.Profile = name
 
 	return &cfg, nil
 }
 
-// Save writes the config to the XDG config path
+// Save writes the config to its profile's XDG config path
 func (c *Config) Save() error {
-	path := ConfigPath()
+	path := ProfilePath(c.Profile)
 
 	// Ensure parent directory exists
 	dir := filepath.Dir(path)
@@ -118,6 +154,14 @@ func (c *Config) Unset(key string) error {
 }
 
 // GetRegionConfig returns the RegionConfig for the configured region
+// If APIBase is set, all endpoints point at it instead
 func (c *Config) GetRegionConfig() (RegionConfig, error) {
-	return GetRegion(c.Region)
+	region, err := GetRegion(c.Region)
+	if err != nil {
+		return RegionConfig{}, err
+	}
+	if c.APIBase != "" {
+		return region.WithBase(c.APIBase), nil
+	}
+	return region, nil
 }
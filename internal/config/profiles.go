@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile backed by the original config.json5 file
+const DefaultProfile = "default"
+
+// profileNameRe restricts profile names to characters that are safe in file
+// names and secrets-store keys
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateProfileName returns an error if name cannot be used as a profile name
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ProfilesDir returns the directory holding named profile configs
+// Typically ~/.config/zoh/profiles/ on Linux
+func ProfilesDir() string {
+	return filepath.Join(ConfigDir(), "profiles")
+}
+
+// ProfilePath returns the config file path for a profile
+// The default profile keeps using config.json5 so existing setups keep working
+func ProfilePath(name string) string {
+	if name == "" || name == DefaultProfile {
+		return ConfigPath()
+	}
+	return filepath.Join(ProfilesDir(), name+".json5")
+}
+
+// activeProfilePath returns the file that records the active profile name
+func activeProfilePath() string {
+	return filepath.Join(ConfigDir(), "active-profile")
+}
+
+// ActiveProfile returns the profile selected with 'zoh auth switch'
+// Falls back to the default profile when none has been selected
+func ActiveProfile() string {
+	data, err := os.ReadFile(activeProfilePath())
+	if err != nil {
+		return DefaultProfile
+	}
+
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil {
+		return DefaultProfile
+	}
+	return name
+}
+
+// SetActiveProfile persists the active profile name
+func SetActiveProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ConfigDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(activeProfilePath(), []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write active profile: %w", err)
+	}
+
+	return nil
+}
+
+// ProfileExists returns true if a config file exists for the profile
+func ProfileExists(name string) bool {
+	_, err := os.Stat(ProfilePath(name))
+	return err == nil
+}
+
+// ListProfiles returns the sorted names of all profiles with a config file
+// The default profile is always included
+func ListProfiles() ([]string, error) {
+	names := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(ProfilesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json5") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json5")
+		if ValidateProfileName(name) != nil || name == DefaultProfile {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	sort.Strings(names[1:])
+	return names, nil
+}
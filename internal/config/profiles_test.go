@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTempConfigHome points the XDG config home at a temp dir for the test
+func withTempConfigHome(t *testing.T) {
+	t.Helper()
+	orig := xdg.ConfigHome
+	xdg.ConfigHome = t.TempDir()
+	t.Cleanup(func() { xdg.ConfigHome = orig })
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"default", "ops-eu", "acme_1", "A"} {
+		assert.NoError(t, ValidateProfileName(name), name)
+	}
+	for _, name := range []string{"", "ops eu", "../etc", "a.b", "x/y"} {
+		assert.Error(t, ValidateProfileName(name), name)
+	}
+}
+
+func TestProfilePath(t *testing.T) {
+	withTempConfigHome(t)
+
+	assert.Equal(t, ConfigPath(), ProfilePath("default"))
+	assert.Equal(t, ConfigPath(), ProfilePath(""))
+	assert.Equal(t, filepath.Join(ProfilesDir(), "ops-eu.json5"), ProfilePath("ops-eu"))
+}
+
+func TestActiveProfile(t *testing.T) {
+	withTempConfigHome(t)
+
+	assert.Equal(t, DefaultProfile, ActiveProfile())
+
+	require.NoError(t, SetActiveProfile("ops-eu"))
+	assert.Equal(t, "ops-eu", ActiveProfile())
+
+	assert.Error(t, SetActiveProfile("bad name"))
+	assert.Equal(t, "ops-eu", ActiveProfile())
+}
+
+func TestLoadProfileSaveRoundTrip(t *testing.T) {
+	withTempConfigHome(t)
+
+	// Missing profile returns defaults tagged with the profile name
+	cfg, err := LoadProfile("ops-eu")
+	require.NoError(t, err)
+	assert.Equal(t, "ops-eu", cfg.Profile)
+	assert.Empty(t, cfg.Region)
+
+	cfg.Region = "eu"
+	cfg.ClientID = "client-ops"
+	cfg.Email = "ops@example.eu"
+	require.NoError(t, cfg.Save())
+
+	// Saved to the profile file, not the default config
+	_, err = os.Stat(ProfilePath("ops-eu"))
+	require.NoError(t, err)
+	_, err = os.Stat(ConfigPath())
+	assert.True(t, os.IsNotExist(err))
+
+	loaded, err := LoadProfile("ops-eu")
+	require.NoError(t, err)
+	assert.Equal(t, "eu", loaded.Region)
+	assert.Equal(t, "client-ops", loaded.ClientID)
+	assert.Equal(t, "ops@example.eu", loaded.Email)
+
+	_, err = LoadProfile("../escape")
+	assert.Error(t, err)
+}
+
+func TestListProfiles(t *testing.T) {
+	withTempConfigHome(t)
+
+	profiles, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, profiles)
+
+	for _, name := range []string{"zeta", "alpha"} {
+		cfg, err := LoadProfile(name)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Save())
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(ProfilesDir(), "notes.txt"), []byte("x"), 0600))
+
+	profiles, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "alpha", "zeta"}, profiles)
+	assert.True(t, ProfileExists("alpha"))
+	assert.False(t, ProfileExists("missing"))
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // RegionConfig holds the endpoint URLs for a Zoho data center
@@ -66,6 +67,16 @@ func GetRegion(name string) (RegionConfig, error) {
 	return cfg, nil
 }
 
+// WithBase returns a copy of the region with every endpoint replaced by base
+func (r RegionConfig) WithBase(base string) RegionConfig {
+	base = strings.TrimSuffix(base, "/")
+	return RegionConfig{
+		AccountsServer: base,
+		APIBase:        base,
+		MailBase:       base,
+	}
+}
+
 // ValidRegions returns a sorted list of valid region codes
 func ValidRegions() []string {
 	regions := make([]string, 0, len(Regions))
@@ -40,6 +40,18 @@ func TestGetRegion(t *testing.T) {
 	})
 }
 
+func TestRegionWithBase(t *testing.T) {
+	cfg := Regions["eu"].WithBase("http://127.0.0.1:8787/")
+	assert.Equal(t, "http://127.0.0.1:8787", cfg.AccountsServer)
+	assert.Equal(t, "http://127.0.0.1:8787", cfg.APIBase)
+	assert.Equal(t, "http://127.0.0.1:8787", cfg.MailBase)
+
+	c := &Config{Region: "eu", APIBase: "http://localhost:9000"}
+	got, err := c.GetRegionConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000", got.MailBase)
+}
+
 func TestValidRegions(t *testing.T) {
 	regions := ValidRegions()
 
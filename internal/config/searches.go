@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+)
+
+// SavedSearch is a named search query, usable as @name in place of a folder
+type SavedSearch struct {
+	Name    string    `json:"name"`
+	Query   string    `json:"query"`
+	Created time.Time `json:"created"`
+}
+
+// ValidateSearchName returns an error if name cannot be used for a saved search
+func ValidateSearchName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid search name %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// SearchesPath returns the saved searches file for a profile.
+// Typically ~/.config/zoh/searches/<profile>.json on Linux
+func SearchesPath(profile string) string {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	return filepath.Join(ConfigDir(), "searches", profile+".json")
+}
+
+// LoadSearches returns a profile's saved searches sorted by name;
+// a missing file means none have been saved yet
+func LoadSearches(profile string) ([]SavedSearch, error) {
+	path := SearchesPath(profile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved searches: %w", err)
+	}
+
+	var searches []SavedSearch
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, fmt.Errorf("failed to parse saved searches %s: %w", path, err)
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	return searches, nil
+}
+
+// FindSearch returns the saved search called name, or nil
+func FindSearch(searches []SavedSearch, name string) *SavedSearch {
+	for i := range searches {
+		if searches[i].Name == name {
+			return &searches[i]
+		}
+	}
+	return nil
+}
+
+// SaveSearches atomically writes a profile's saved searches, sorted by name
+func SaveSearches(profile string, searches []SavedSearch) error {
+	path := SearchesPath(profile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create searches directory: %w", err)
+	}
+
+	sorted := append([]SavedSearch{}, searches...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	data, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved searches: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+	return nil
+}
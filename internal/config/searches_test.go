@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchesPerProfile(t *testing.T) {
+	withTempConfigHome(t)
+
+	searches, err := LoadSearches("")
+	require.NoError(t, err)
+	assert.Empty(t, searches)
+
+	require.NoError(t, SaveSearches("", []SavedSearch{
+		{Name: "urgent", Query: "is:unread is:flagged"},
+		{Name: "invoices", Query: "subject:invoice has:attachment"},
+	}))
+
+	searches, err = LoadSearches(DefaultProfile)
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "invoices", searches[0].Name)
+	assert.Equal(t, "is:unread is:flagged", FindSearch(searches, "urgent").Query)
+	assert.Nil(t, FindSearch(searches, "missing"))
+
+	// Other profiles keep their own searches
+	searches, err = LoadSearches("ops-eu")
+	require.NoError(t, err)
+	assert.Empty(t, searches)
+
+	assert.NoError(t, ValidateSearchName("needs-reply_2"))
+	assert.Error(t, ValidateSearchName("@urgent"))
+	assert.Error(t, ValidateSearchName("a b"))
+}
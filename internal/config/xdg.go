@@ -17,6 +17,12 @@ func ConfigPath() string {
 	return filepath.Join(ConfigDir(), "config.json5")
 }
 
+// RulesPath returns the default client-side mail rules file
+// Typically ~/.config/zoh/rules.json5 on Linux
+func RulesPath() string {
+	return filepath.Join(ConfigDir(), "rules.json5")
+}
+
 // CacheDir returns the XDG-compliant cache directory for zoh
 // Typically ~/.cache/zoh/ on Linux (for token cache in Plan 02)
 func CacheDir() string {
@@ -24,7 +30,7 @@ func CacheDir() string {
 }
 
 // DataDir returns the XDG-compliant data directory for zoh
-// Typically ~/.local/share/zoh/ on Linux (for future use)
+// Typically ~/.local/share/zoh/ on Linux (holds the mail sync state)
 func DataDir() string {
 	return filepath.Join(xdg.DataHome, "zoh")
 }
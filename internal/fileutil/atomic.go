@@ -0,0 +1,27 @@
+// Package fileutil holds file helpers shared by the state, index and config
+// stores.
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partly written file
+func WriteAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,28 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	require.NoError(t, WriteAtomic(path, []byte("one")))
+	require.NoError(t, WriteAtomic(path, []byte("two")))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.Error(t, WriteAtomic(filepath.Join(dir, "missing", "state.json"), nil))
+}
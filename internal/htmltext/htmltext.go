@@ -0,0 +1,556 @@
+// Package htmltext renders HTML mail bodies as plain text or Markdown.
+//
+// Bodies are parsed with golang.org/x/net/html, so omitted end tags and
+// other HTML5 error recovery behave as in a browser. Paragraphs, headings,
+// lists, block quotes, preformatted text and tables keep their layout, links
+// become footnotes (or Markdown links), entities are decoded, and head, style
+// and script contents are dropped.
+package htmltext
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Text renders HTML as plain text; link targets are listed as numbered footnotes
+func Text(s string) string {
+	return render(s, false)
+}
+
+// Markdown renders HTML as Markdown
+func Markdown(s string) string {
+	return render(s, true)
+}
+
+// blankRuns matches runs of blank lines
+var blankRuns = regexp.MustCompile(`\n{3,}`)
+
+// attr returns the value of an element's attribute, or ""
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// list is an open <ul> or <ol>
+type list struct {
+	ordered bool
+	n       int
+}
+
+// link is an open <a>
+type link struct {
+	href  string
+	buf   *strings.Builder
+	start int
+}
+
+// table is an open <table>. Layout tables, which contain other tables or
+// have a single column, render their cells as blocks; the rest as a grid.
+type table struct {
+	layout bool
+	rows   [][]string
+	row    []string
+	cell   *strings.Builder
+}
+
+// renderer accumulates rendered output
+type renderer struct {
+	markdown bool
+	out      strings.Builder
+	newlines int  // line breaks owed before the next content
+	breaks   int  // line breaks written since the last content
+	space    bool // a space is owed before the next content
+	lineOpen bool // the current output line has its quote prefix
+	quotes   int  // blockquote depth
+	pre      int  // <pre> depth
+	lists    []*list
+	links    []*link
+	tables   []*table
+	notes    []string // footnote URLs, text mode only
+}
+
+// render converts HTML to text or Markdown
+func render(s string, markdown bool) string {
+	r := &renderer{markdown: markdown}
+	if doc, err := html.Parse(strings.NewReader(s)); err == nil {
+		r.walk(doc)
+	}
+
+	result := r.out.String()
+	lines := strings.Split(result, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	result = strings.TrimSpace(blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+	if len(r.notes) > 0 {
+		result += "\n"
+		for i, url := range r.notes {
+			result += fmt.Sprintf("\n[%d] %s", i+1, url)
+		}
+	}
+	return result
+}
+
+// walk renders a node and its children
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+		if hidden(n) {
+			return
+		}
+		r.start(n)
+	case html.DocumentNode:
+	default:
+		// Comments and doctypes
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+	if n.Type == html.ElementNode {
+		r.end(n)
+	}
+}
+
+// hidden reports whether an element is never shown: document metadata,
+// scripts and styles, and display:none content such as preheaders
+func hidden(n *html.Node) bool {
+	switch n.Data {
+	case "head", "title", "template", "script", "style", "noscript":
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none")
+}
+
+// containsTable reports whether a table contains another table
+func containsTable(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "table" || containsTable(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// cell returns the grid cell being filled, if any
+func (r *renderer) cell() *strings.Builder {
+	if len(r.tables) == 0 {
+		return nil
+	}
+	return r.tables[len(r.tables)-1].cell
+}
+
+// buf returns where inline content currently goes
+func (r *renderer) buf() *strings.Builder {
+	if c := r.cell(); c != nil {
+		return c
+	}
+	return &r.out
+}
+
+// block ensures the next content starts on a new line; n == 2 leaves a blank line
+func (r *renderer) block(n int) {
+	if r.cell() != nil {
+		r.space = true
+		return
+	}
+	r.newlines = max(r.newlines, n)
+	r.space = false
+}
+
+// flush writes owed line breaks, quote prefixes and spaces before content
+func (r *renderer) flush() {
+	if c := r.cell(); c != nil {
+		if r.space && c.Len() > 0 {
+			c.WriteByte(' ')
+		}
+		r.space = false
+		return
+	}
+
+	r.breakLines()
+	if !r.lineOpen {
+		r.out.WriteString(r.quotePrefix())
+		r.lineOpen = true
+		r.space = false
+	}
+	if r.space {
+		r.out.WriteByte(' ')
+		r.space = false
+	}
+}
+
+// breakLines writes the owed line breaks; blank lines keep the quote prefix
+func (r *renderer) breakLines() {
+	if r.out.Len() > 0 {
+		for r.breaks < r.newlines {
+			if r.breaks > 0 {
+				r.out.WriteString(strings.TrimSpace(r.quotePrefix()))
+			}
+			r.out.WriteByte('\n')
+			r.breaks++
+			r.lineOpen = false
+		}
+	}
+	r.newlines = 0
+}
+
+// endLine ends the current output line, if it has content
+func (r *renderer) endLine() {
+	if r.cell() == nil && r.out.Len() > 0 && r.breaks == 0 {
+		r.out.WriteByte('\n')
+		r.breaks, r.lineOpen = 1, false
+	}
+}
+
+// quotePrefix is the prefix of every line inside block quotes
+func (r *renderer) quotePrefix() string {
+	return strings.Repeat("> ", r.quotes)
+}
+
+// write emits literal content after any owed separators
+func (r *renderer) write(s string) {
+	r.flush()
+	r.buf().WriteString(s)
+	if r.cell() == nil {
+		r.breaks = 0
+	}
+}
+
+// text emits a text run, collapsing whitespace outside <pre>
+func (r *renderer) text(s string) {
+	if r.pre > 0 && r.cell() == nil {
+		for i, line := range strings.Split(s, "\n") {
+			if i > 0 {
+				r.flush()
+				r.out.WriteByte('\n')
+				r.breaks++
+				r.lineOpen = false
+			}
+			if line != "" {
+				r.write(line)
+			}
+		}
+		return
+	}
+
+	first, _ := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	if unicode.IsSpace(first) {
+		r.space = true
+	}
+	for i, word := range strings.Fields(s) {
+		if i > 0 {
+			r.space = true
+		}
+		r.write(word)
+	}
+	if unicode.IsSpace(last) {
+		r.space = true
+	}
+}
+
+// start handles the start of an element
+func (r *renderer) start(n *html.Node) {
+	switch n.Data {
+	case "p", "dl":
+		r.block(2)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.block(2)
+		if r.markdown {
+			r.write(strings.Repeat("#", headingLevel(n.Data)) + " ")
+		}
+	case "ul", "ol":
+		if len(r.lists) > 0 {
+			r.block(1)
+		} else {
+			r.block(2)
+		}
+		l := &list{ordered: n.Data == "ol"}
+		// Ordered lists may start at another number
+		if start, err := strconv.Atoi(strings.TrimSpace(attr(n, "start"))); err == nil && l.ordered {
+			l.n = start - 1
+		}
+		r.lists = append(r.lists, l)
+	case "table":
+		r.block(2)
+		r.tables = append(r.tables, &table{layout: containsTable(n)})
+	case "div", "section", "article", "header", "footer", "center", "address", "dt", "dd", "figure", "figcaption":
+		r.block(1)
+	case "br":
+		if r.cell() != nil {
+			r.space = true
+		} else if r.pre > 0 {
+			r.text("\n")
+		} else {
+			r.newlines = max(r.newlines, r.breaks) + 1
+		}
+	case "hr":
+		r.block(2)
+		if r.markdown {
+			r.write("---")
+		} else {
+			r.write("----------")
+		}
+		r.block(2)
+	case "blockquote":
+		r.block(2)
+		r.breakLines()
+		r.quotes++
+	case "pre":
+		r.block(2)
+		if r.markdown {
+			r.write("```")
+			r.block(1)
+		}
+		r.pre++
+	case "li":
+		r.block(1)
+		depth := max(len(r.lists), 1)
+		marker := "- "
+		if depth <= len(r.lists) {
+			if l := r.lists[depth-1]; l.ordered {
+				l.n++
+				if value, err := strconv.Atoi(strings.TrimSpace(attr(n, "value"))); err == nil {
+					l.n = value
+				}
+				marker = fmt.Sprintf("%d. ", l.n)
+			}
+		}
+		r.write(strings.Repeat("  ", depth-1) + marker)
+	case "tr":
+		if tb := r.currentTable(); tb != nil && !tb.layout {
+			tb.row = nil
+		} else {
+			r.block(1)
+		}
+	case "td", "th":
+		if tb := r.currentTable(); tb != nil && !tb.layout {
+			tb.cell = &strings.Builder{}
+		} else {
+			r.block(1)
+		}
+	case "a":
+		href := strings.TrimSpace(attr(n, "href"))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			href = ""
+		}
+		if href != "" && r.markdown {
+			r.write("[")
+		}
+		r.flush()
+		b := r.buf()
+		r.links = append(r.links, &link{href: href, buf: b, start: b.Len()})
+	case "img":
+		alt := strings.TrimSpace(attr(n, "alt"))
+		src := attr(n, "src")
+		switch {
+		case r.markdown && (strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")):
+			r.write(fmt.Sprintf("![%s](%s)", alt, src))
+		case alt != "":
+			r.write("[" + alt + "]")
+		}
+	case "b", "strong":
+		if r.markdown {
+			r.write("**")
+		}
+	case "i", "em":
+		if r.markdown {
+			r.write("_")
+		}
+	case "code":
+		if r.markdown && r.pre == 0 {
+			r.write("`")
+		}
+	}
+}
+
+// end handles the end of an element
+func (r *renderer) end(n *html.Node) {
+	switch n.Data {
+	case "p", "h1", "h2", "h3", "h4", "h5", "h6", "dl":
+		r.block(2)
+	case "ul", "ol":
+		if len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+		if len(r.lists) > 0 {
+			r.block(1)
+		} else {
+			r.block(2)
+		}
+	case "div", "section", "article", "header", "footer", "center", "address", "dt", "dd", "figure", "figcaption", "li":
+		r.block(1)
+	case "blockquote":
+		r.endLine()
+		r.quotes = max(r.quotes-1, 0)
+		r.block(2)
+	case "pre":
+		r.pre = max(r.pre-1, 0)
+		if r.markdown {
+			r.block(1)
+			r.write("```")
+		}
+		r.block(2)
+	case "td", "th":
+		if tb := r.currentTable(); tb != nil && tb.cell != nil {
+			tb.row = append(tb.row, strings.TrimSpace(tb.cell.String()))
+			tb.cell = nil
+			r.space = false
+		}
+	case "tr":
+		if tb := r.currentTable(); tb != nil && !tb.layout {
+			if len(tb.row) > 0 {
+				tb.rows = append(tb.rows, tb.row)
+			}
+			tb.row = nil
+		}
+	case "table":
+		if len(r.tables) == 0 {
+			return
+		}
+		tb := r.tables[len(r.tables)-1]
+		r.tables = r.tables[:len(r.tables)-1]
+		r.renderTable(tb)
+		r.block(2)
+	case "a":
+		if len(r.links) == 0 {
+			return
+		}
+		l := r.links[len(r.links)-1]
+		r.links = r.links[:len(r.links)-1]
+		if l.href == "" {
+			return
+		}
+		if r.markdown {
+			r.buf().WriteString("](" + l.href + ")")
+			return
+		}
+		label := ""
+		if l.buf == r.buf() && l.start <= l.buf.Len() {
+			label = strings.TrimSpace(l.buf.String()[l.start:])
+		}
+		target := strings.TrimPrefix(l.href, "mailto:")
+		if label == "" || strings.EqualFold(label, target) || strings.EqualFold(label, l.href) {
+			if label == "" {
+				r.write(target)
+			}
+			return
+		}
+		n := slices.Index(r.notes, l.href) + 1
+		if n == 0 {
+			r.notes = append(r.notes, l.href)
+			n = len(r.notes)
+		}
+		r.buf().WriteString(fmt.Sprintf("[%d]", n))
+	case "b", "strong":
+		if r.markdown {
+			r.buf().WriteString("**")
+		}
+	case "i", "em":
+		if r.markdown {
+			r.buf().WriteString("_")
+		}
+	case "code":
+		if r.markdown && r.pre == 0 {
+			r.buf().WriteString("`")
+		}
+	}
+}
+
+// currentTable returns the innermost open table
+func (r *renderer) currentTable() *table {
+	if len(r.tables) == 0 {
+		return nil
+	}
+	return r.tables[len(r.tables)-1]
+}
+
+// renderTable writes a finished table as aligned columns or a Markdown table.
+// A single-column table is written as one line per cell.
+func (r *renderer) renderTable(tb *table) {
+	if len(tb.rows) == 0 {
+		return
+	}
+	cols := 0
+	for _, row := range tb.rows {
+		cols = max(cols, len(row))
+	}
+
+	if cols == 1 {
+		for _, row := range tb.rows {
+			if row[0] != "" {
+				r.block(1)
+				r.write(row[0])
+			}
+		}
+		return
+	}
+
+	widths := make([]int, cols)
+	if r.markdown {
+		// Separator rows need at least three dashes
+		for i := range widths {
+			widths[i] = 3
+		}
+	}
+	for _, row := range tb.rows {
+		for i, c := range row {
+			if r.markdown {
+				c = strings.ReplaceAll(c, "|", `\|`)
+				row[i] = c
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c))
+		}
+	}
+
+	for n, row := range tb.rows {
+		cells := make([]string, cols)
+		for i := range cells {
+			c := ""
+			if i < len(row) {
+				c = row[i]
+			}
+			cells[i] = c + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+		}
+
+		r.block(1)
+		if r.markdown {
+			r.write("| " + strings.Join(cells, " | ") + " |")
+			if n == 0 {
+				seps := make([]string, cols)
+				for i := range seps {
+					seps[i] = strings.Repeat("-", widths[i])
+				}
+				r.block(1)
+				r.write("| " + strings.Join(seps, " | ") + " |")
+			}
+		} else {
+			r.write(strings.Join(cells, "  "))
+		}
+	}
+}
+
+// headingLevel returns 1-6 for h1-h6 and 0 otherwise
+func headingLevel(name string) int {
+	if len(name) == 2 && name[0] == 'h' && '1' <= name[1] && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+	return 0
+}
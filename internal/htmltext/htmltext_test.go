@@ -0,0 +1,92 @@
+package htmltext
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{
+			"paragraphs and entities",
+			"<p>Hello&nbsp;there,</p><p>Fish &amp; chips &lt;3<br>Ann</p>",
+			"Hello there,\n\nFish & chips <3\nAnn",
+		},
+		{
+			"style, script and head dropped",
+			`<html><head><title>T</title><style>p{color:red}</style></head><body><script>alert(1)</script><p>Body</p></body></html>`,
+			"Body",
+		},
+		{
+			"hidden preheader and comments",
+			`<div style="display: none">Preview text</div><!--[if mso]>x<![endif]--><p>Visible</p>`,
+			"Visible",
+		},
+		{
+			"links as footnotes",
+			`<p>Read the <a href="https://example.com/a">docs</a>, the <a href="https://example.com/a">docs again</a> or mail <a href="mailto:ann@example.com">ann@example.com</a>.</p>`,
+			"Read the docs[1], the docs again[1] or mail ann@example.com.\n\n[1] https://example.com/a",
+		},
+		{
+			"lists",
+			"<p>Steps:</p><ol><li>DNS</li><li>DKIM<ul><li>selector</li></ul></li></ol><p>Done</p>",
+			"Steps:\n\n1. DNS\n2. DKIM\n  - selector\n\nDone",
+		},
+		{
+			"table layout",
+			"<table><tr><th>Host</th><th>Status</th></tr><tr><td>db1</td><td>down</td></tr></table>",
+			"Host  Status\ndb1   down",
+		},
+		{
+			"layout tables become blocks",
+			"<table><tr><td><table><tr><td>Name</td><td>Ann</td></tr></table></td></tr><tr><td>Footer</td></tr></table>",
+			"Name  Ann\n\nFooter",
+		},
+		{
+			"table cells and rows without end tags",
+			"<table><tr><td>a<td>b<tr><td>c<td>d</table>",
+			"a  b\nc  d",
+		},
+		{
+			"paragraphs and list items without end tags",
+			"<p>One<p>Two<ul><li>x<li>y</ul>",
+			"One\n\nTwo\n\n- x\n- y",
+		},
+		{
+			"ordered list start",
+			`<ol start="3"><li>three<li>four<li value="9">nine</ol>`,
+			"3. three\n4. four\n9. nine",
+		},
+		{
+			"blockquote and pre",
+			"<p>Reply</p><blockquote><p>one</p><p>two</p></blockquote><pre>a  b\n  c</pre>",
+			"Reply\n\n> one\n>\n> two\n\na  b\n  c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Text(tt.html))
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := Markdown(`<h2>Status</h2><p>See <a href="https://example.com">the <b>dashboard</b></a>.</p>` +
+		`<ul><li>one</li><li>two</li></ul><table><tr><td>a</td><td>b|c</td></tr><tr><td>1</td><td>2</td></tr></table>`)
+	want := "## Status\n\nSee [the **dashboard**](https://example.com).\n\n- one\n- two\n\n" +
+		"| a   | b\\|c |\n| --- | ---- |\n| 1   | 2    |"
+	assert.Equal(t, want, got)
+}
+
+func TestTextLargeBodyWithScripts(t *testing.T) {
+	body := strings.Repeat("<style>p{color:red}</style><p>x</p>", 10000)
+	start := time.Now()
+	got := Text(body)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, 10000, strings.Count(got, "x"))
+}
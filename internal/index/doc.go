@@ -0,0 +1,37 @@
+package index
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/htmltext"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// ParseDoc builds a document from a message's RFC 822 source and returns it
+// with the body text to index. Callers fill in the message, folder and state fields.
+func ParseDoc(raw []byte) (*Doc, string, error) {
+	msg, err := zoho.ParseRawMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", err
+	}
+
+	req := msg.Request
+	doc := &Doc{
+		Subject: req.Subject,
+		From:    req.FromAddress,
+		To:      strings.Trim(req.ToAddress+","+req.CcAddress, ","),
+	}
+	for _, part := range msg.Parts {
+		if !part.Inline {
+			doc.Attachments = append(doc.Attachments, part.FileName)
+		}
+	}
+	doc.HasAttachment = len(doc.Attachments) > 0
+
+	body := req.Content
+	if req.MailFormat == "html" {
+		body = htmltext.Text(body)
+	}
+	return doc, body, nil
+}
@@ -0,0 +1,206 @@
+// Package index is an offline full-text index over mail messages.
+//
+// Messages are tokenized per field (subject, from, to, body and attachment
+// names) into an inverted index stored as a JSON file under
+// config.DataDir(), so searches need no network round-trips.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+)
+
+// indexVersion is bumped when the index layout changes incompatibly
+const indexVersion = 1
+
+// Indexed fields, used as posting key prefixes
+const (
+	FieldSubject    = "subject"
+	FieldFrom       = "from"
+	FieldTo         = "to"
+	FieldBody       = "body"
+	FieldAttachment = "attachment"
+)
+
+// textFields are the fields a free-text term is matched against
+var textFields = []string{FieldSubject, FieldFrom, FieldTo, FieldBody, FieldAttachment}
+
+// Doc is one indexed message
+type Doc struct {
+	MessageID     string   `json:"messageId"`
+	FolderID      string   `json:"folderId"`
+	Folder        string   `json:"folder"` // folder path
+	Subject       string   `json:"subject"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	Attachments   []string `json:"attachments,omitempty"`
+	ReceivedTime  int64    `json:"receivedTime"` // Unix milliseconds
+	Unread        bool     `json:"unread,omitempty"`
+	HasAttachment bool     `json:"hasAttachment,omitempty"`
+	Terms         []string `json:"terms"` // posting keys, kept so the doc can be removed
+}
+
+// Index is an inverted index from field:term keys to message IDs
+type Index struct {
+	Version  int                 `json:"version"`
+	Docs     map[string]*Doc     `json:"docs"`
+	Postings map[string][]string `json:"postings"` // field:term -> sorted message IDs
+}
+
+// Path returns the index file for a profile.
+// Typically ~/.local/share/zoh/index/<profile>.json on Linux
+func Path(profile string) string {
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	return filepath.Join(config.DataDir(), "index", profile+".json")
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{
+		Version:  indexVersion,
+		Docs:     make(map[string]*Doc),
+		Postings: make(map[string][]string),
+	}
+}
+
+// Load reads an index file; a missing file returns an empty index
+func Load(path string) (*Index, error) {
+	ix := New()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read index: %w", err)
+	}
+	if err := json.Unmarshal(data, ix); err != nil {
+		return nil, fmt.Errorf("parse index %s: %w", path, err)
+	}
+	if ix.Version != indexVersion {
+		return nil, fmt.Errorf("index %s has unsupported version %d (rebuild it with --rebuild)", path, ix.Version)
+	}
+	if ix.Docs == nil {
+		ix.Docs = make(map[string]*Doc)
+	}
+	if ix.Postings == nil {
+		ix.Postings = make(map[string][]string)
+	}
+	return ix, nil
+}
+
+// Save atomically writes the index file, creating its directory if needed
+func (ix *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create index directory: %w", err)
+	}
+
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return fmt.Errorf("marshal index: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, data); err != nil {
+		return fmt.Errorf("write index: %w", err)
+	}
+	return nil
+}
+
+// Has reports whether a message is indexed
+func (ix *Index) Has(messageID string) bool {
+	_, ok := ix.Docs[messageID]
+	return ok
+}
+
+// Add indexes a message with its body text, replacing any previous version
+func (ix *Index) Add(doc *Doc, body string) {
+	ix.Remove(doc.MessageID)
+
+	keys := make(map[string]bool)
+	for field, text := range map[string]string{
+		FieldSubject:    doc.Subject,
+		FieldFrom:       doc.From,
+		FieldTo:         doc.To,
+		FieldBody:       body,
+		FieldAttachment: strings.Join(doc.Attachments, " "),
+	} {
+		for _, term := range Tokenize(text) {
+			keys[field+":"+term] = true
+		}
+	}
+
+	doc.Terms = make([]string, 0, len(keys))
+	for key := range keys {
+		doc.Terms = append(doc.Terms, key)
+		ids := ix.Postings[key]
+		if i, found := slices.BinarySearch(ids, doc.MessageID); !found {
+			ix.Postings[key] = slices.Insert(ids, i, doc.MessageID)
+		}
+	}
+	slices.Sort(doc.Terms)
+	ix.Docs[doc.MessageID] = doc
+}
+
+// Remove drops a message from the index
+func (ix *Index) Remove(messageID string) {
+	doc, ok := ix.Docs[messageID]
+	if !ok {
+		return
+	}
+	for _, key := range doc.Terms {
+		ids := ix.Postings[key]
+		if i, found := slices.BinarySearch(ids, messageID); found {
+			ids = slices.Delete(ids, i, i+1)
+		}
+		if len(ids) == 0 {
+			delete(ix.Postings, key)
+		} else {
+			ix.Postings[key] = ids
+		}
+	}
+	delete(ix.Docs, messageID)
+}
+
+// Tokenize splits text into lowercase terms of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// lookup returns the IDs of messages containing every term of text in one of fields
+func (ix *Index) lookup(text string, fields []string) map[string]bool {
+	var result map[string]bool
+	for _, term := range Tokenize(text) {
+		ids := make(map[string]bool)
+		for _, field := range fields {
+			for _, id := range ix.Postings[field+":"+term] {
+				ids[id] = true
+			}
+		}
+		if result == nil {
+			result = ids
+			continue
+		}
+		for id := range result {
+			if !ids[id] {
+				delete(result, id)
+			}
+		}
+	}
+	if result == nil {
+		result = make(map[string]bool)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+func day(s string) int64 {
+	t, _ := time.Parse("2006-01-02", s)
+	return t.UnixMilli()
+}
+
+func testIndex() *Index {
+	ix := New()
+	ix.Add(&Doc{MessageID: "1", Subject: "Invoice 42", From: "billing@example.com", To: "me@example.com", ReceivedTime: day("2025-01-10")}, "Please pay the invoice")
+	ix.Add(&Doc{MessageID: "2", Subject: "Lunch?", From: "bob@example.com", To: "me@example.com", ReceivedTime: day("2025-02-01"), Unread: true}, "Tacos at noon")
+	ix.Add(&Doc{MessageID: "3", Subject: "Q3 report", From: "alice@example.com", To: "team@example.com", ReceivedTime: day("2025-03-05"),
+		Attachments: []string{"report-q3.csv"}, HasAttachment: true}, "Numbers attached")
+	return ix
+}
+
+func ids(docs []*Doc) []string {
+	out := make([]string, len(docs))
+	for i, d := range docs {
+		out[i] = d.MessageID
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"invoice", []string{"1"}},
+		{"from:billing invoice", []string{"1"}},
+		{"from:bob invoice", []string{}},
+		{"to:team", []string{"3"}},
+		{"subject:lunch", []string{"2"}},
+		{"has:attachment", []string{"3"}},
+		{"csv", []string{"3"}},
+		{"is:unread", []string{"2"}},
+		{"after:2025/02/01", []string{"3", "2"}},
+		{"before:2025-02-01", []string{"1"}},
+		{"example.com", []string{"3", "2", "1"}},
+		{"tacos", []string{"2"}},
+		{"invoice OR lunch", []string{"2", "1"}},
+		{"-from:bob example.com", []string{"3", "1"}},
+		{"(is:unread OR has:attachment) -csv", []string{"2"}},
+	}
+	for _, tt := range tests {
+		q, err := zoho.ParseSearch(tt.query)
+		require.NoError(t, err, tt.query)
+		assert.Equal(t, tt.want, ids(ix.Search(q)), tt.query)
+	}
+}
+
+func TestRemoveAndReload(t *testing.T) {
+	ix := testIndex()
+	ix.Add(&Doc{MessageID: "1", Subject: "Paid", From: "billing@example.com"}, "thanks")
+	ix.Remove("2")
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	require.NoError(t, ix.Save(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	search := func(query string) []*Doc {
+		q, err := zoho.ParseSearch(query)
+		require.NoError(t, err)
+		return loaded.Search(q)
+	}
+	assert.Empty(t, search("invoice"))
+	assert.Empty(t, search("tacos"))
+	assert.Equal(t, []string{"1"}, ids(search("paid")))
+	assert.NotContains(t, loaded.Postings, "body:tacos")
+}
+
+func TestParseDoc(t *testing.T) {
+	raw := "From: a@example.com\r\nTo: b@example.com\r\nCc: c@example.com\r\nSubject: Hi\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n\r\n<p>Caf&eacute; at <b>noon</b></p>"
+	doc, body, err := ParseDoc([]byte(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "b@example.com,c@example.com", doc.To)
+	assert.Equal(t, "Café at noon", body)
+}
@@ -0,0 +1,97 @@
+package index
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// termFields are the indexed fields searched for free text and the from:,
+// to: and subject: terms; other terms are matched against the doc's summary
+var termFields = map[string][]string{
+	"":        textFields,
+	"from":    {FieldFrom},
+	"to":      {FieldTo},
+	"subject": {FieldSubject},
+}
+
+// Search returns the indexed messages matching a query parsed by
+// zoho.ParseSearch, newest first. Text terms are looked up word by word, so
+// free text also finds the body and attachment names; in: terms must have
+// been resolved with zoho.ResolveSearch.
+func (ix *Index) Search(expr zoho.SearchExpr) []*Doc {
+	hits := make(map[*zoho.SearchTerm]map[string]bool)
+	var docs []*Doc
+	for _, doc := range ix.Docs {
+		if ix.match(expr, doc, hits) {
+			docs = append(docs, doc)
+		}
+	}
+
+	slices.SortFunc(docs, func(a, b *Doc) int {
+		return cmp.Or(cmp.Compare(b.ReceivedTime, a.ReceivedTime), strings.Compare(a.MessageID, b.MessageID))
+	})
+	return docs
+}
+
+// match evaluates expr against one doc; hits caches the postings lookup of each text term
+func (ix *Index) match(expr zoho.SearchExpr, doc *Doc, hits map[*zoho.SearchTerm]map[string]bool) bool {
+	switch e := expr.(type) {
+	case *zoho.SearchTerm:
+		fields, ok := termFields[e.Field]
+		if !ok {
+			return zoho.MatchSearch(e, doc.summary())
+		}
+		// Text without any word, e.g. punctuation, constrains nothing
+		if len(Tokenize(e.Value)) == 0 {
+			return true
+		}
+		ids, ok := hits[e]
+		if !ok {
+			ids = ix.lookup(e.Value, fields)
+			hits[e] = ids
+		}
+		return ids[doc.MessageID]
+	case *zoho.SearchAnd:
+		for _, t := range e.Terms {
+			if !ix.match(t, doc, hits) {
+				return false
+			}
+		}
+		return true
+	case *zoho.SearchOr:
+		for _, t := range e.Terms {
+			if ix.match(t, doc, hits) {
+				return true
+			}
+		}
+		return false
+	case *zoho.SearchNot:
+		return !ix.match(e.Term, doc, hits)
+	}
+	return false
+}
+
+// summary describes a doc as a message summary, for the terms that are not text
+func (doc *Doc) summary() *zoho.MessageSummary {
+	msg := &zoho.MessageSummary{
+		MessageID:     doc.MessageID,
+		FolderID:      doc.FolderID,
+		Subject:       doc.Subject,
+		FromAddress:   doc.From,
+		ToAddress:     doc.To,
+		ReceivedTime:  strconv.FormatInt(doc.ReceivedTime, 10),
+		Status:        "1",
+		HasAttachment: "0",
+	}
+	if doc.Unread {
+		msg.Status = "0"
+	}
+	if doc.HasAttachment {
+		msg.HasAttachment = "1"
+	}
+	return msg
+}
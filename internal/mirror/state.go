@@ -0,0 +1,160 @@
+// Package mirror keeps a local copy of a Zoho mailbox in sync.
+//
+// Messages are stored as .eml files under an output directory, one
+// subdirectory per folder path. A State file under config.DataDir() records
+// every mirrored message and a per-folder high-water mark, so each sync only
+// downloads what is new and can tell moved and deleted messages apart.
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/fileutil"
+)
+
+// stateVersion is bumped when the state file layout changes incompatibly
+const stateVersion = 1
+
+// State is the sync-state database for one output directory
+type State struct {
+	Version  int                     `json:"version"`
+	Out      string                  `json:"out"`
+	LastSync time.Time               `json:"lastSync,omitzero"`
+	Folders  map[string]*FolderState `json:"folders"`  // folder ID -> state
+	Messages map[string]*Message     `json:"messages"` // message ID -> entry
+}
+
+// FolderState is the high-water mark of a folder: the newest message mirrored
+type FolderState struct {
+	Path        string `json:"path"`
+	HighWater   int64  `json:"highWater"` // ReceivedTime in Unix milliseconds
+	HighWaterID string `json:"highWaterId"`
+}
+
+// Message is one mirrored message
+type Message struct {
+	FolderID     string     `json:"folderId"`
+	File         string     `json:"file"` // relative to the output directory
+	ReceivedTime int64      `json:"receivedTime"`
+	Subject      string     `json:"subject,omitempty"`
+	From         string     `json:"from,omitempty"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"` // set once the message is gone from Zoho
+	Pruned       bool       `json:"pruned,omitempty"`    // the local file was removed too
+}
+
+// StatePath returns the state file for mirroring a profile into outDir.
+// Typically ~/.local/share/zoh/sync/<profile>-<hash of outDir>.json on Linux
+func StatePath(profile, outDir string) (string, error) {
+	abs, err := filepath.Abs(outDir)
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(config.DataDir(), "sync", profile+"-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// Load reads a state file; a missing file returns an empty state for outDir
+func Load(path, outDir string) (*State, error) {
+	state := &State{
+		Version:  stateVersion,
+		Out:      outDir,
+		Folders:  make(map[string]*FolderState),
+		Messages: make(map[string]*Message),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse sync state %s: %w", path, err)
+	}
+	if state.Version != stateVersion {
+		return nil, fmt.Errorf("sync state %s has unsupported version %d", path, state.Version)
+	}
+	if state.Folders == nil {
+		state.Folders = make(map[string]*FolderState)
+	}
+	if state.Messages == nil {
+		state.Messages = make(map[string]*Message)
+	}
+	return state, nil
+}
+
+// Save atomically writes the state file, creating its directory if needed
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create sync state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sync state: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	return nil
+}
+
+// Folder returns the state of a folder, creating it on first use
+func (s *State) Folder(folderID, path string) *FolderState {
+	f, ok := s.Folders[folderID]
+	if !ok {
+		f = &FolderState{}
+		s.Folders[folderID] = f
+	}
+	f.Path = path
+	return f
+}
+
+// Advance raises the folder's high-water mark if the message is newer
+func (f *FolderState) Advance(receivedTime int64, messageID string) {
+	if receivedTime > f.HighWater || (receivedTime == f.HighWater && messageID > f.HighWaterID) {
+		f.HighWater, f.HighWaterID = receivedTime, messageID
+	}
+}
+
+// Reached reports whether a message is at or below the high-water mark,
+// i.e. it was already there when the folder was last synced
+func (f *FolderState) Reached(receivedTime int64, messageID string) bool {
+	return receivedTime < f.HighWater || (receivedTime == f.HighWater && messageID <= f.HighWaterID)
+}
+
+// unsafeChars matches characters that are not safe in file names
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._ -]+`)
+
+// MessageFile returns where a message is stored, relative to the output
+// directory: the folder path, one directory per segment, then <id>.eml
+func MessageFile(folderPath, messageID string) string {
+	parts := []string{}
+	for _, segment := range strings.Split(folderPath, "/") {
+		segment = strings.Trim(unsafeChars.ReplaceAllString(segment, "_"), " .")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "_")
+	}
+	parts = append(parts, unsafeChars.ReplaceAllString(messageID, "_")+".eml")
+	return filepath.Join(parts...)
+}
@@ -0,0 +1,55 @@
+package mirror
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderHighWater(t *testing.T) {
+	var f FolderState
+	f.Advance(100, "b")
+	f.Advance(50, "z")
+	f.Advance(100, "a")
+	assert.Equal(t, int64(100), f.HighWater)
+	assert.Equal(t, "b", f.HighWaterID)
+
+	assert.True(t, f.Reached(100, "b"))
+	assert.True(t, f.Reached(99, "z"))
+	assert.False(t, f.Reached(100, "c"))
+	assert.False(t, f.Reached(101, "a"))
+}
+
+func TestMessageFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("Inbox", "17.eml"), MessageFile("/Inbox", "17"))
+	assert.Equal(t, filepath.Join("Projects", "Q1 plans", "17.eml"), MessageFile("/Projects/Q1 plans", "17"))
+	assert.Equal(t, filepath.Join("_", "a_b.eml"), MessageFile("/../", "a/b"))
+}
+
+func TestStateSaveLoad(t *testing.T) {
+	orig := xdg.DataHome
+	xdg.DataHome = t.TempDir()
+	t.Cleanup(func() { xdg.DataHome = orig })
+
+	path, err := StatePath("work", "mail")
+	require.NoError(t, err)
+	other, err := StatePath("work", "backup")
+	require.NoError(t, err)
+	assert.NotEqual(t, path, other)
+
+	state, err := Load(path, "mail")
+	require.NoError(t, err)
+	assert.Empty(t, state.Messages)
+
+	state.Folder("1", "/Inbox").Advance(10, "m1")
+	state.Messages["m1"] = &Message{FolderID: "1", File: MessageFile("/Inbox", "m1"), ReceivedTime: 10}
+	require.NoError(t, state.Save(path))
+
+	loaded, err := Load(path, "mail")
+	require.NoError(t, err)
+	assert.Equal(t, "m1", loaded.Folders["1"].HighWaterID)
+	assert.Equal(t, "1", loaded.Messages["m1"].FolderID)
+}
@@ -14,12 +14,11 @@ func RenderTable(w io.Writer, columns []Column, rows []map[string]string) {
 	}
 
 	// Create header row
-	headerFmt := table.New(w)
 	headers := make([]interface{}, len(columns))
 	for i, col := range columns {
 		headers[i] = col.Name
 	}
-	headerFmt.AddRow(headers...)
+	tbl := table.New(headers...).WithWriter(w)
 
 	// Add data rows
 	for _, row := range rows {
@@ -32,8 +31,10 @@ func RenderTable(w io.Writer, columns []Column, rows []map[string]string) {
 			}
 			rowData[i] = value
 		}
-		headerFmt.AddRow(rowData...)
+		tbl.AddRow(rowData...)
 	}
+
+	tbl.Print()
 }
 
 // TruncateString truncates a string to maxLen and adds "..." if needed
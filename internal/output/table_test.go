@@ -1,6 +1,8 @@
 package output
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +51,21 @@ func TestPadString(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderTable(t *testing.T) {
+	columns := []Column{{Name: "Name", Key: "name"}, {Name: "Email", Key: "email", Width: 10}}
+	rows := []map[string]string{{"name": "Alice", "email": "alice@example.com"}}
+
+	var buf bytes.Buffer
+	RenderTable(&buf, columns, rows)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "Name")
+	assert.Contains(t, lines[0], "Email")
+	assert.Contains(t, lines[1], "alice@e...")
+
+	buf.Reset()
+	RenderTable(&buf, columns, nil)
+	assert.Empty(t, buf.String())
+}
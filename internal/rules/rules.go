@@ -0,0 +1,335 @@
+// Package rules loads and evaluates client-side mail rules.
+//
+// A rules file (JSON5 or YAML) lists rules in order. Each rule has
+// conditions, a query in the search syntax and/or field conditions over a
+// message's summary and metadata, and the actions to take when they all
+// hold. The CLI carries the actions out, so rules can do what Zoho's server
+// filters cannot, such as running a local command.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yosuke-furukawa/json5/encoding/json5"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// File is a parsed rules file
+type File struct {
+	Rules []*Rule `json:"rules"`
+
+	// Path is the file the rules were loaded from
+	Path string `json:"-"`
+}
+
+// Rule is a set of conditions and the actions to take on messages meeting all of them
+type Rule struct {
+	Name     string      `json:"name"`
+	Query    string      `json:"query,omitempty"` // search syntax, evaluated on the message summary
+	Match    []Condition `json:"match,omitempty"`
+	Actions  []Action    `json:"actions"`
+	Stop     bool        `json:"stop,omitempty"`     // skip later rules for matching messages
+	Disabled bool        `json:"disabled,omitempty"` // keep the rule in the file without running it
+
+	query zoho.SearchExpr
+}
+
+// Condition tests one message field; exactly one of Equals, Contains and
+// Matches is set. Equals and Contains ignore case.
+type Condition struct {
+	Field    string  `json:"field"`
+	Equals   *string `json:"equals,omitempty"`
+	Contains string  `json:"contains,omitempty"`
+	Matches  string  `json:"matches,omitempty"` // regular expression
+	Not      bool    `json:"not,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Action is one thing to do with a matching message; exactly one field is set
+type Action struct {
+	Move     string `json:"move,omitempty"`  // folder name, path or ID
+	Label    string `json:"label,omitempty"` // label name or ID
+	MarkRead bool   `json:"markRead,omitempty"`
+	Forward  string `json:"forward,omitempty"` // recipient address
+	Reply    string `json:"reply,omitempty"`   // mail template file, relative to the rules file
+	Run      string `json:"run,omitempty"`     // shell command, given the message like mail watch --exec
+}
+
+// Message is what rules are evaluated against: a summary, with the full
+// metadata fetched only when a condition needs a field the summary lacks
+type Message struct {
+	Summary  *zoho.MessageSummary
+	Metadata func() (*zoho.MessageMetadata, error)
+
+	metadata *zoho.MessageMetadata
+}
+
+// summaryFields and metadataFields map JSON field names to struct field
+// indexes of the string fields conditions can test
+var (
+	summaryFields  = stringFields(reflect.TypeFor[zoho.MessageSummary]())
+	metadataFields = stringFields(reflect.TypeFor[zoho.MessageMetadata]())
+)
+
+// stringFields maps the JSON names of a struct's string fields to their index
+func stringFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Type.Kind() != reflect.String {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		fields[name] = i
+	}
+	return fields
+}
+
+// Fields returns the field names conditions can test, sorted
+func Fields() []string {
+	var names []string
+	for name := range summaryFields {
+		names = append(names, name)
+	}
+	for name := range metadataFields {
+		if _, ok := summaryFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Load reads and validates a rules file. Files ending in .yaml or .yml are
+// YAML; anything else is JSON5. Unknown keys are errors, so typos in a
+// rule never silently widen what it matches.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+
+	var doc any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = json5.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	// Decode strictly through plain JSON, whichever syntax the file used
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(normalized))
+	dec.DisallowUnknownFields()
+	f := &File{Path: path}
+	if err := dec.Decode(f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// validate checks every rule and compiles its query and patterns
+func (f *File) validate() error {
+	if len(f.Rules) == 0 {
+		return errors.New("no rules defined")
+	}
+	seen := make(map[string]bool)
+	for i, r := range f.Rules {
+		if r == nil || r.Name == "" {
+			return fmt.Errorf("rule %d: missing name", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rule %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+		if err := r.validate(filepath.Dir(f.Path)); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a rule; relative reply templates are resolved against dir
+func (r *Rule) validate(dir string) error {
+	if r.Query == "" && len(r.Match) == 0 {
+		return errors.New("needs a query or match conditions")
+	}
+	if r.Query != "" {
+		expr, err := zoho.ParseSearch(r.Query)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		r.query = expr
+	}
+
+	for i := range r.Match {
+		c := &r.Match[i]
+		_, inSummary := summaryFields[c.Field]
+		_, inMetadata := metadataFields[c.Field]
+		if !inSummary && !inMetadata {
+			return fmt.Errorf("unknown field %q (use one of %s)", c.Field, strings.Join(Fields(), ", "))
+		}
+		ops := 0
+		for _, set := range []bool{c.Equals != nil, c.Contains != "", c.Matches != ""} {
+			if set {
+				ops++
+			}
+		}
+		if ops != 1 {
+			return fmt.Errorf("condition on %s needs exactly one of equals, contains or matches", c.Field)
+		}
+		if c.Matches != "" {
+			re, err := regexp.Compile(c.Matches)
+			if err != nil {
+				return fmt.Errorf("condition on %s: %w", c.Field, err)
+			}
+			c.re = re
+		}
+	}
+
+	if len(r.Actions) == 0 {
+		return errors.New("no actions")
+	}
+	for i := range r.Actions {
+		a := &r.Actions[i]
+		set := 0
+		for _, v := range []bool{a.Move != "", a.Label != "", a.MarkRead, a.Forward != "", a.Reply != "", a.Run != ""} {
+			if v {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("action %d: set exactly one of move, label, markRead, forward, reply or run", i+1)
+		}
+		if a.Reply != "" && !filepath.IsAbs(a.Reply) {
+			a.Reply = filepath.Join(dir, a.Reply)
+		}
+	}
+	return nil
+}
+
+// Resolve looks up the folders and labels named by in: and label: terms in
+// the rules' queries
+func (f *File) Resolve(resolve zoho.SearchResolver) error {
+	for _, r := range f.Rules {
+		if r.query == nil || r.Disabled {
+			continue
+		}
+		if err := zoho.ResolveSearch(r.query, resolve); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the enabled rules a message meets, in file order, up to
+// and including the first matching rule with Stop set
+func (f *File) Evaluate(msg *Message) ([]*Rule, error) {
+	var matched []*Rule
+	for _, r := range f.Rules {
+		if r.Disabled {
+			continue
+		}
+		ok, err := r.matches(msg)
+		if err != nil {
+			return matched, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		matched = append(matched, r)
+		if r.Stop {
+			break
+		}
+	}
+	return matched, nil
+}
+
+// matches reports whether a message meets the rule's query and every condition
+func (r *Rule) matches(msg *Message) (bool, error) {
+	if r.query != nil && !zoho.MatchSearch(r.query, msg.Summary) {
+		return false, nil
+	}
+	for i := range r.Match {
+		value, err := msg.field(r.Match[i].Field)
+		if err != nil {
+			return false, err
+		}
+		if !r.Match[i].test(value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// test applies the condition to a field value
+func (c *Condition) test(value string) bool {
+	var ok bool
+	switch {
+	case c.Equals != nil:
+		ok = strings.EqualFold(value, *c.Equals)
+	case c.Contains != "":
+		ok = strings.Contains(strings.ToLower(value), strings.ToLower(c.Contains))
+	default:
+		ok = c.re.MatchString(value)
+	}
+	return ok != c.Not
+}
+
+// field returns a field of the summary, fetching the metadata once for fields only it has
+func (m *Message) field(name string) (string, error) {
+	if i, ok := summaryFields[name]; ok {
+		return reflect.ValueOf(m.Summary).Elem().Field(i).String(), nil
+	}
+	if m.metadata == nil {
+		if m.Metadata == nil {
+			return "", fmt.Errorf("no metadata available for field %s", name)
+		}
+		md, err := m.Metadata()
+		if err != nil {
+			return "", fmt.Errorf("fetch metadata: %w", err)
+		}
+		m.metadata = md
+	}
+	return reflect.ValueOf(m.metadata).Elem().Field(metadataFields[name]).String(), nil
+}
+
+// String describes the action, e.g. "move to Archive"
+func (a Action) String() string {
+	switch {
+	case a.Move != "":
+		return "move to " + a.Move
+	case a.Label != "":
+		return "label " + a.Label
+	case a.MarkRead:
+		return "mark read"
+	case a.Forward != "":
+		return "forward to " + a.Forward
+	case a.Reply != "":
+		return "reply with " + filepath.Base(a.Reply)
+	default:
+		return "run " + a.Run
+	}
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// writeRules writes a rules file into a temp dir and returns its path
+func writeRules(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadJSON5AndYAML(t *testing.T) {
+	json5Path := writeRules(t, "rules.json5", `{
+		// Comments and trailing commas are fine
+		rules: [
+			{
+				name: "alerts",
+				query: "from:alerts@example.com",
+				match: [{field: "subject", matches: "^\\[PROD\\]"}],
+				actions: [{label: "Ops"}, {move: "Alerts"}],
+				stop: true,
+			},
+			{name: "ack", match: [{field: "subject", contains: "invoice"}], actions: [{reply: "ack.tmpl"}]},
+		],
+	}`)
+	yamlPath := writeRules(t, "rules.yaml", `
+rules:
+  - name: alerts
+    query: from:alerts@example.com
+    match:
+      - field: subject
+        matches: ^\[PROD\]
+    actions:
+      - label: Ops
+      - move: Alerts
+    stop: true
+  - name: ack
+    match:
+      - {field: subject, contains: invoice}
+    actions:
+      - reply: ack.tmpl
+`)
+
+	for _, path := range []string{json5Path, yamlPath} {
+		f, err := Load(path)
+		require.NoError(t, err, path)
+		require.Len(t, f.Rules, 2)
+		assert.Equal(t, "alerts", f.Rules[0].Name)
+		assert.True(t, f.Rules[0].Stop)
+		assert.Equal(t, []string{"label Ops", "move to Alerts"},
+			[]string{f.Rules[0].Actions[0].String(), f.Rules[0].Actions[1].String()})
+		// Reply templates are found next to the rules file
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "ack.tmpl"), f.Rules[1].Actions[0].Reply)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{`{rules: []}`, "no rules defined"},
+		{`{rules: [{name: "a", actions: [{markRead: true}]}]}`, `rule "a": needs a query or match conditions`},
+		{`{rules: [{name: "a", query: "x", actions: []}]}`, `rule "a": no actions`},
+		{`{rules: [{name: "a", query: "from:", actions: [{markRead: true}]}]}`, `rule "a": query: missing value after from:`},
+		{`{rules: [{name: "a", match: [{field: "colour", equals: "red"}], actions: [{markRead: true}]}]}`, `rule "a": unknown field "colour"`},
+		{`{rules: [{name: "a", match: [{field: "subject"}], actions: [{markRead: true}]}]}`, "needs exactly one of equals, contains or matches"},
+		{`{rules: [{name: "a", match: [{field: "subject", matches: "("}], actions: [{markRead: true}]}]}`, "condition on subject"},
+		{`{rules: [{name: "a", query: "x", actions: [{move: "A", label: "B"}]}]}`, "action 1: set exactly one of"},
+		{`{rules: [{name: "a", query: "x", actions: [{markRead: true}]}, {name: "a", query: "y", actions: [{markRead: true}]}]}`, `rule "a": duplicate name`},
+		{`{rules: [{name: "a", qurey: "x", actions: [{markRead: true}]}]}`, `unknown field "qurey"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			_, err := Load(writeRules(t, "rules.json5", tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json5"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestEvaluate(t *testing.T) {
+	f, err := Load(writeRules(t, "rules.json5", `{rules: [
+		{name: "prod", query: "from:alerts -subject:resolved", match: [{field: "subject", matches: "^\\[PROD\\]"}], actions: [{markRead: true}], stop: true},
+		{name: "alerts", query: "from:alerts", actions: [{label: "Ops"}]},
+		{name: "big", match: [{field: "messageSize", equals: "999"}, {field: "priority", equals: "1", not: true}], actions: [{markRead: true}]},
+		{name: "off", query: "from:alerts", actions: [{markRead: true}], disabled: true},
+	]}`))
+	require.NoError(t, err)
+
+	fetches := 0
+	evaluate := func(subject string) []string {
+		t.Helper()
+		matched, err := f.Evaluate(&Message{
+			Summary: &zoho.MessageSummary{FromAddress: "alerts@example.com", Subject: subject, Priority: "3"},
+			Metadata: func() (*zoho.MessageMetadata, error) {
+				fetches++
+				return &zoho.MessageMetadata{MessageSize: "999"}, nil
+			},
+		})
+		require.NoError(t, err)
+		var names []string
+		for _, r := range matched {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	// The first rule stops evaluation, so metadata is never needed
+	assert.Equal(t, []string{"prod"}, evaluate("[PROD] disk full"))
+	assert.Zero(t, fetches)
+
+	// Metadata-only fields are fetched once per message
+	assert.Equal(t, []string{"alerts", "big"}, evaluate("[PROD] disk full resolved"))
+	assert.Equal(t, 1, fetches)
+}
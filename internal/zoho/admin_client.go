@@ -316,6 +316,50 @@ func (ac *AdminClient) DisableUser(ctx context.Context, zuid int64, opts Disable
 	return nil
 }
 
+// SetUserVacationReply enables a vacation auto-reply on another user's mailbox.
+// Dates are read in that mailbox's time zone.
+func (ac *AdminClient) SetUserVacationReply(ctx context.Context, zuid int64, vacation *VacationReply) error {
+	if err := vacation.Validate(); err != nil {
+		return err
+	}
+
+	return ac.updateUserAccount(ctx, map[string]interface{}{
+		"mode":             "addVacationReply",
+		"zuid":             zuid,
+		"vacationResponse": vacation,
+	})
+}
+
+// DisableUserVacationReply turns off another user's vacation auto-reply
+func (ac *AdminClient) DisableUserVacationReply(ctx context.Context, zuid int64) error {
+	return ac.updateUserAccount(ctx, map[string]interface{}{
+		"mode": "disableVacationReply",
+		"zuid": zuid,
+	})
+}
+
+// updateUserAccount sends a mode-based update for a user account in the organization
+func (ac *AdminClient) updateUserAccount(ctx context.Context, reqBody map[string]interface{}) error {
+	path := fmt.Sprintf("/api/organization/%d/accounts", ac.zoid)
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := ac.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ac.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
 // DeleteUser permanently deletes a user from the organization
 func (ac *AdminClient) DeleteUser(ctx context.Context, zuid int64) error {
 	path := fmt.Sprintf("/api/organization/%d/accounts/%d", ac.zoid, zuid)
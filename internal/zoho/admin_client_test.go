@@ -0,0 +1,109 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func newAdminClient(t *testing.T) (*zohotest.Server, *zoho.AdminClient) {
+	t.Helper()
+	fake, cfg, ts := startFake(t)
+	ac, err := zoho.NewAdminClient(cfg, ts)
+	require.NoError(t, err)
+	return fake, ac
+}
+
+func TestAdminClientUserLifecycle(t *testing.T) {
+	fake, ac := newAdminClient(t)
+	ctx := context.Background()
+
+	user, err := ac.CreateUser(ctx, zoho.CreateUserRequest{
+		PrimaryEmailAddress: "new@example.com",
+		Password:            "s3cret!",
+		FirstName:           "New",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.PrimaryEmail())
+
+	require.NoError(t, ac.UpdateUserRole(ctx, user.ZUID, "admin"))
+	require.NoError(t, ac.DisableUser(ctx, user.ZUID, zoho.DisableUserOpts{BlockIncoming: true}))
+	got := fake.User(user.ZUID)
+	require.NotNil(t, got)
+	assert.Equal(t, "admin", got.Role)
+	assert.Equal(t, "disabled", got.MailboxStatus)
+
+	require.NoError(t, ac.DeleteUser(ctx, user.ZUID))
+	assert.Nil(t, fake.User(user.ZUID))
+}
+
+func TestAdminClientGetUserByEmail(t *testing.T) {
+	fake, ac := newAdminClient(t)
+	fake.AddUser(zoho.User{PrimaryEmailID: "alice@example.com", FirstName: "Alice"})
+
+	user, err := ac.GetUserByEmail(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.FirstName)
+}
+
+func TestAdminClientGroupMembers(t *testing.T) {
+	fake, ac := newAdminClient(t)
+	ctx := context.Background()
+
+	group, err := ac.CreateGroup(ctx, zoho.CreateGroupRequest{
+		GroupName:         "Ops",
+		GroupEmailAddress: "ops@example.com",
+		MembersEmailList:  []string{"a@example.com"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ac.AddGroupMembers(ctx, group.ZGID, []zoho.GroupMemberToAdd{{MemberEmailID: "b@example.com"}}))
+	require.NoError(t, ac.RemoveGroupMembers(ctx, group.ZGID, []zoho.GroupMemberToRemove{{MemberEmailID: "a@example.com"}}))
+	assert.Equal(t, []string{"b@example.com"}, fake.GroupMembers(group.ZGID))
+}
+
+func TestAdminClientDomains(t *testing.T) {
+	fake, ac := newAdminClient(t)
+	ctx := context.Background()
+
+	_, err := ac.AddDomain(ctx, "new.example")
+	require.NoError(t, err)
+	require.NoError(t, ac.VerifyDomain(ctx, "new.example", "verifyDomainByTXT"))
+	require.NoError(t, ac.UpdateDomainSettings(ctx, "new.example", "setPrimary"))
+
+	domains, err := ac.ListDomains(ctx)
+	require.NoError(t, err)
+	assert.Len(t, domains, 2)
+
+	d := fake.Domain("new.example")
+	require.NotNil(t, d)
+	assert.True(t, d.VerificationStatus)
+	assert.True(t, d.Primary)
+	assert.False(t, fake.Domain(zohotest.Domain).Primary)
+}
+
+func TestAdminClientLogPagination(t *testing.T) {
+	fake, ac := newAdminClient(t)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		at := now.Add(-time.Duration(i+1) * time.Hour).UnixMilli()
+		fake.AddAuditLog(zoho.AuditLog{Operation: "op", RequestTime: at})
+		fake.AddSMTPLog(zoho.SMTPLogEntry{Subject: "s", Timestamp: at})
+	}
+	ctx := context.Background()
+	from, to := now.Add(-24*time.Hour), now
+
+	audit, err := ac.GetAuditLogs(ctx, from, to, "", 2)
+	require.NoError(t, err)
+	assert.Len(t, audit, 3)
+
+	smtp, err := ac.GetSMTPLogs(ctx, from, to, "", "", 2)
+	require.NoError(t, err)
+	assert.Len(t, smtp, 3)
+}
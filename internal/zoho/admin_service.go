@@ -16,6 +16,8 @@ type AdminService interface {
 	EnableUser(ctx context.Context, zuid int64) error
 	DisableUser(ctx context.Context, zuid int64, opts DisableUserOpts) error
 	DeleteUser(ctx context.Context, zuid int64) error
+	SetUserVacationReply(ctx context.Context, zuid int64, vacation *VacationReply) error
+	DisableUserVacationReply(ctx context.Context, zuid int64) error
 
 	ListGroups(ctx context.Context, start, limit int) ([]Group, error)
 	GetGroup(ctx context.Context, zgid int64) (*Group, error)
@@ -0,0 +1,408 @@
+package zoho
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Cassette modes for CassetteTransport
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+)
+
+// redacted replaces scrubbed secrets in recorded cassettes
+const redacted = "REDACTED"
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the scrubbed request half of an interaction
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"` // Path and query only, so cassettes are host-independent
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the scrubbed response half of an interaction
+type RecordedResponse struct {
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"` // "base64" for binary bodies
+}
+
+// sensitiveHeaders are replaced with REDACTED when recorded
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// volatileHeaders are dropped when recorded so cassettes are deterministic
+var volatileHeaders = []string{"Date", "Content-Length", "User-Agent", "Accept-Encoding"}
+
+// sensitiveKeys are JSON, form and query keys whose values are replaced with REDACTED
+var sensitiveKeys = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_secret": true,
+	"password":      true,
+}
+
+// sensitiveParams are additionally redacted in query strings and form bodies.
+// "code" is the OAuth authorization code there, but a status field in JSON.
+var sensitiveParams = map[string]bool{
+	"code": true,
+}
+
+// volatileParams are query parameters that take the current time, such as
+// audit log windows. Replay ignores their values; any other difference in a
+// request is a mismatch.
+var volatileParams = map[string]bool{
+	"startTime": true,
+	"endTime":   true,
+	"fromTime":  true,
+	"toTime":    true,
+}
+
+// cassetteFile is the shared in-memory state of one cassette on disk.
+// All clients in a process that use the same path and mode share it, so
+// interactions from mail and admin clients end up in one file in request order.
+type cassetteFile struct {
+	mu       sync.Mutex
+	path     string
+	cassette Cassette
+	used     []bool
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = map[string]*cassetteFile{}
+)
+
+// openCassette returns the shared state for a cassette path, loading it from disk once.
+// In record mode a missing file starts an empty cassette; in replay mode it is an error.
+func openCassette(path, mode string) (*cassetteFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	key := mode + ":" + abs
+	if cf, ok := cassettes[key]; ok {
+		return cf, nil
+	}
+
+	cf := &cassetteFile{path: abs}
+	data, err := os.ReadFile(abs)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &cf.cassette); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+		}
+	case os.IsNotExist(err) && mode == CassetteRecord:
+	default:
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	cf.used = make([]bool, len(cf.cassette.Interactions))
+
+	cassettes[key] = cf
+	return cf, nil
+}
+
+// save writes the cassette atomically. Callers must hold cf.mu.
+func (cf *cassetteFile) save() error {
+	data, err := json.MarshalIndent(cf.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cf.path), 0700); err != nil {
+		return err
+	}
+
+	tmp := cf.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cf.path)
+}
+
+// CassetteTransport records HTTP interactions to a cassette file or replays them.
+// It sits at the bottom of the client transport chain, below oauth2.Transport,
+// so Authorization headers and tokens are scrubbed before anything is written.
+type CassetteTransport struct {
+	Base http.RoundTripper // Used in record mode
+	mode string
+	file *cassetteFile
+}
+
+// NewCassetteTransport opens a cassette for recording or replay.
+// Recording appends to an existing cassette, so one file can capture several commands.
+func NewCassetteTransport(path, mode string, base http.RoundTripper) (*CassetteTransport, error) {
+	if mode == "" {
+		mode = CassetteReplay
+	}
+	if mode != CassetteRecord && mode != CassetteReplay {
+		return nil, fmt.Errorf("invalid cassette mode: %s (must be record or replay)", mode)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	cf, err := openCassette(path, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CassetteTransport{Base: base, mode: mode, file: cf}, nil
+}
+
+// Replaying returns true if the transport serves responses from the cassette
+func (t *CassetteTransport) Replaying() bool {
+	return t.mode == CassetteReplay
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	recReq := recordRequest(req, reqBody)
+
+	if t.Replaying() {
+		return t.replay(req, recReq)
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	t.file.mu.Lock()
+	defer t.file.mu.Unlock()
+	t.file.cassette.Interactions = append(t.file.cassette.Interactions, Interaction{
+		Request:  recReq,
+		Response: recordResponse(resp, respBody),
+	})
+	t.file.used = append(t.file.used, true)
+	if err := t.file.save(); err != nil {
+		return nil, fmt.Errorf("write cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// replay returns the first unused interaction matching the request.
+// An exact match on method, URL and body wins; otherwise the next unused
+// interaction that differs only in volatile parameters is used, so requests
+// carrying the current time (e.g. audit log windows) still replay in order.
+func (t *CassetteTransport) replay(req *http.Request, rec RecordedRequest) (*http.Response, error) {
+	t.file.mu.Lock()
+	defer t.file.mu.Unlock()
+
+	match := -1
+	for i, in := range t.file.cassette.Interactions {
+		if !t.file.used[i] && in.Request.Method == rec.Method && in.Request.URL == rec.URL && in.Request.Body == rec.Body {
+			match = i
+			break
+		}
+	}
+	if match < 0 {
+		stable := stableURL(rec.URL)
+		for i, in := range t.file.cassette.Interactions {
+			if !t.file.used[i] && in.Request.Method == rec.Method && stableURL(in.Request.URL) == stable && in.Request.Body == rec.Body {
+				match = i
+				break
+			}
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("cassette %s: no recorded response for %s %s", filepath.Base(t.file.path), rec.Method, rec.URL)
+	}
+	t.file.used[match] = true
+
+	recResp := t.file.cassette.Interactions[match].Response
+	body := []byte(recResp.Body)
+	if recResp.BodyEncoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(recResp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decode cassette body: %w", err)
+		}
+		body = decoded
+	}
+
+	header := recResp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recResp.StatusCode, http.StatusText(recResp.StatusCode)),
+		StatusCode:    recResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// drainBody reads a body fully and replaces it with an in-memory copy
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// recordRequest builds the scrubbed form of a request
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	u := *req.URL
+	u.RawQuery = scrubQuery(u.Query()).Encode()
+
+	header := req.Header.Clone()
+	scrubHeader(header)
+
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    u.RequestURI(),
+		Header: header,
+		Body:   scrubBody(req.Header.Get("Content-Type"), body),
+	}
+}
+
+// recordResponse builds the scrubbed form of a response
+func recordResponse(resp *http.Response, body []byte) RecordedResponse {
+	header := resp.Header.Clone()
+	scrubHeader(header)
+
+	rec := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+	}
+	if utf8.Valid(body) {
+		rec.Body = scrubBody(resp.Header.Get("Content-Type"), body)
+	} else {
+		rec.Body = base64.StdEncoding.EncodeToString(body)
+		rec.BodyEncoding = "base64"
+	}
+	return rec
+}
+
+// scrubHeader redacts credentials and drops volatile headers in place
+func scrubHeader(h http.Header) {
+	for _, name := range sensitiveHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	for _, name := range volatileHeaders {
+		h.Del(name)
+	}
+}
+
+// scrubQuery redacts sensitive query or form values
+func scrubQuery(q url.Values) url.Values {
+	for key := range q {
+		if sensitiveKeys[key] || sensitiveParams[key] {
+			q.Set(key, redacted)
+		}
+	}
+	return q
+}
+
+// scrubBody redacts sensitive values in JSON and form-encoded bodies
+func scrubBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			return scrubQuery(form).Encode()
+		}
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if scrubJSON(v) {
+			if out, err := json.Marshal(v); err == nil {
+				return string(out)
+			}
+		}
+	}
+
+	return string(body)
+}
+
+// scrubJSON redacts sensitive keys in a decoded JSON value, reporting whether anything changed
+func scrubJSON(v any) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]any:
+		for key, inner := range val {
+			if sensitiveKeys[key] {
+				val[key] = redacted
+				changed = true
+				continue
+			}
+			if scrubJSON(inner) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, inner := range val {
+			if scrubJSON(inner) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// stableURL returns a recorded URL with the values of volatile parameters blanked
+func stableURL(u string) string {
+	path, rawQuery, _ := strings.Cut(u, "?")
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return u
+	}
+	for key := range q {
+		if volatileParams[key] {
+			q.Set(key, "")
+		}
+	}
+	return path + "?" + q.Encode()
+}
@@ -0,0 +1,131 @@
+package zoho_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	fake, cfg, ts := startFake(t)
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Recorded", FromAddress: "a@example.com"},
+	})
+	path := filepath.Join(t.TempDir(), "mail.json")
+	ctx := context.Background()
+
+	cfg.Cassette, cfg.CassetteMode = path, zoho.CassetteRecord
+	mc, err := zoho.NewMailClient(cfg, ts)
+	require.NoError(t, err)
+	recorded, err := mc.ListMessages(ctx, fake.FolderID("Inbox"), 0, 10)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "test-token")
+	assert.Contains(t, string(data), "REDACTED")
+
+	// Replay against an unreachable endpoint with a different token
+	replayCfg := &config.Config{Region: "us", APIBase: "http://127.0.0.1:1", Cassette: path, CassetteMode: zoho.CassetteReplay}
+	replayTS := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "other"})
+	mc, err = zoho.NewMailClient(replayCfg, replayTS)
+	require.NoError(t, err)
+	replayed, err := mc.ListMessages(ctx, fake.FolderID("Inbox"), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+
+	// Every recorded interaction has been used up
+	_, err = mc.ListMessages(ctx, fake.FolderID("Inbox"), 0, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded response")
+}
+
+func TestCassetteReplayMatchesRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "audit.json")
+
+	get := func(ct *zoho.CassetteTransport, base, query string) (string, error) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: ct}).Get(base + "/api/organization/1/activity?" + query)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	ct, err := zoho.NewCassetteTransport(path, zoho.CassetteRecord, nil)
+	require.NoError(t, err)
+	for _, query := range []string{"endTime=2000&limit=10&startTime=1000", "endTime=2000&limit=20&startTime=1000"} {
+		_, err = get(ct, srv.URL, query)
+		require.NoError(t, err)
+	}
+
+	// A different time window replays in order, but any other difference fails
+	ct, err = zoho.NewCassetteTransport(path, zoho.CassetteReplay, nil)
+	require.NoError(t, err)
+	_, err = get(ct, "http://127.0.0.1:1", "endTime=9000&limit=30&startTime=8000")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded response")
+	body, err := get(ct, "http://127.0.0.1:1", "endTime=9000&limit=20&startTime=8000")
+	require.NoError(t, err)
+	assert.Equal(t, "endTime=2000&limit=20&startTime=1000", body)
+	body, err = get(ct, "http://127.0.0.1:1", "endTime=9000&limit=10&startTime=8000")
+	require.NoError(t, err)
+	assert.Equal(t, "endTime=2000&limit=10&startTime=1000", body)
+}
+
+func TestCassetteReplayMissingFile(t *testing.T) {
+	_, err := zoho.NewCassetteTransport(filepath.Join(t.TempDir(), "missing.json"), zoho.CassetteReplay, nil)
+	assert.Error(t, err)
+
+	_, err = zoho.NewCassetteTransport("x.json", "rewind", nil)
+	assert.Error(t, err)
+}
+
+func TestCassetteScrubsTokens(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"at-secret","refresh_token":"rt-secret","expires_in":3600}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "oauth.json")
+	ct, err := zoho.NewCassetteTransport(path, zoho.CassetteRecord, nil)
+	require.NoError(t, err)
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"rt-secret"}, "client_secret": {"cs-secret"}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/oauth/v2/token?code=code-secret", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Zoho-oauthtoken at-secret")
+
+	resp, err := (&http.Client{Transport: ct}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"at-secret", "rt-secret", "cs-secret", "code-secret"} {
+		assert.NotContains(t, string(data), secret)
+	}
+	assert.Contains(t, string(data), "grant_type=refresh_token")
+}
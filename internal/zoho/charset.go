@@ -0,0 +1,43 @@
+package zoho
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// decodeCharset converts text in the named MIME charset to UTF-8. Text
+// without a charset is taken as UTF-8, a superset of the US-ASCII default.
+// Charsets are looked up by their WHATWG names and aliases, so labels such as
+// latin1 or ascii map to the encodings mail clients actually use.
+func decodeCharset(charset string, data []byte) (string, error) {
+	charset = strings.TrimSpace(charset)
+	if charset == "" {
+		return string(data), nil
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return "", fmt.Errorf("unsupported charset %q", charset)
+	}
+	text, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("decode %s: %w", charset, err)
+	}
+	return string(text), nil
+}
+
+// charsetReader is a mime.WordDecoder CharsetReader for the charsets
+// decodeCharset knows
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	text, err := decodeCharset(charset, data)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(text), nil
+}
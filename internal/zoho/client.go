@@ -15,27 +15,43 @@ import (
 
 // Client is a region-aware HTTP client for Zoho APIs.
 type Client struct {
-	httpClient  *http.Client
-	region      config.RegionConfig
-	rateLimiter *rate.Limiter
+	httpClient      *http.Client
+	transferClient  *http.Client      // Same transports without the API timeout, for attachment transfers
+	transferTimeout time.Duration     // Deadline for one attachment transfer
+	baseTransport   http.RoundTripper // Transport without auth or rate limiting, used by DoAuth
+	region          config.RegionConfig
+	rateLimiter     *rate.Limiter
 }
 
 // NewClient creates a new Zoho API client with OAuth2 authentication and rate limiting.
 func NewClient(cfg *config.Config, tokenSource oauth2.TokenSource) (*Client, error) {
 	// Get region configuration
-	regionConfig, err := config.GetRegion(cfg.Region)
+	regionConfig, err := cfg.GetRegionConfig()
 	if err != nil {
 		return nil, fmt.Errorf("invalid region %q: %w", cfg.Region, err)
 	}
 
 	// Create rate limiter
-	rateLimiter := NewRateLimiter()
+	rateLimiter := ClientRateLimiter()
 
 	// Build transport chain:
-	// 1. Base transport (http.DefaultTransport)
+	// 1. Base transport (http.DefaultTransport, or a cassette recorder/replayer)
 	// 2. OAuth2 transport (adds Authorization: Bearer header)
 	// 3. Rate limit transport (enforces rate limits and handles 429)
-	baseTransport := http.DefaultTransport
+	var baseTransport http.RoundTripper = http.DefaultTransport
+
+	if cfg.Cassette != "" {
+		cassette, err := NewCassetteTransport(cfg.Cassette, cfg.CassetteMode, baseTransport)
+		if err != nil {
+			return nil, fmt.Errorf("open cassette: %w", err)
+		}
+		baseTransport = cassette
+
+		// Replayed responses never reach Zoho, so don't throttle them
+		if cassette.Replaying() {
+			rateLimiter = rate.NewLimiter(rate.Inf, 0)
+		}
+	}
 	oauth2Transport := &oauth2.Transport{
 		Base:   baseTransport,
 		Source: tokenSource,
@@ -51,10 +67,18 @@ func NewClient(cfg *config.Config, tokenSource oauth2.TokenSource) (*Client, err
 		Timeout:   30 * time.Second,
 	}
 
+	// Attachment transfers get a per-transfer deadline instead (see transferContext)
+	transferClient := &http.Client{
+		Transport: rateLimitTransport,
+	}
+
 	return &Client{
-		httpClient:  httpClient,
-		region:      regionConfig,
-		rateLimiter: rateLimiter,
+		httpClient:      httpClient,
+		transferClient:  transferClient,
+		transferTimeout: cfg.TransferTimeout,
+		baseTransport:   baseTransport,
+		region:          regionConfig,
+		rateLimiter:     rateLimiter,
 	}, nil
 }
 
@@ -94,7 +118,7 @@ func (c *Client) DoAuth(ctx context.Context, method, path string, body io.Reader
 	// Execute without OAuth2 token (use base client without oauth2.Transport)
 	// Auth endpoints use client_id/client_secret or token introspection
 	baseClient := &http.Client{
-		Transport: http.DefaultTransport,
+		Transport: c.baseTransport,
 		Timeout:   30 * time.Second,
 	}
 	return baseClient.Do(req)
@@ -0,0 +1,66 @@
+package zoho
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+)
+
+// Identity describes the Zoho account a set of credentials belongs to
+type Identity struct {
+	AccountID   string `json:"accountId"`
+	OrgID       string `json:"orgId"`
+	Email       string `json:"email"`
+	DisplayName string `json:"displayName"`
+}
+
+// ResolveIdentity fetches the primary account behind a token source via /api/accounts.
+// Used after login to record which Zoho identity a profile is bound to.
+func ResolveIdentity(ctx context.Context, cfg *config.Config, tokenSource oauth2.TokenSource) (*Identity, error) {
+	client, err := NewClient(cfg, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+
+	resp, err := client.DoMail(ctx, http.MethodGet, "/api/accounts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var acctResp AccountsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&acctResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if acctResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", acctResp.Status.Description, acctResp.Status.Code)
+	}
+
+	if len(acctResp.Data) == 0 {
+		return nil, fmt.Errorf("no accounts found")
+	}
+
+	acct := acctResp.Data[0]
+	identity := &Identity{
+		AccountID:   acct.AccountID,
+		Email:       acct.PrimaryEmailAddress,
+		DisplayName: acct.DisplayName,
+	}
+	if acct.PolicyID.Zoid != 0 {
+		identity.OrgID = fmt.Sprintf("%d", acct.PolicyID.Zoid)
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,139 @@
+package zoho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Flag values accepted by SetFlag
+const (
+	FlagInfo      = "info"
+	FlagImportant = "important"
+	FlagFollowUp  = "followup"
+	FlagNone      = "flag_not_set"
+)
+
+// MarkRead marks messages as read
+func (mc *MailClient) MarkRead(ctx context.Context, messageIDs []string) error {
+	return mc.updateMessages(ctx, "markAsRead", messageIDs, nil)
+}
+
+// MarkUnread marks messages as unread
+func (mc *MailClient) MarkUnread(ctx context.Context, messageIDs []string) error {
+	return mc.updateMessages(ctx, "markAsUnread", messageIDs, nil)
+}
+
+// SetFlag sets the flag on messages (FlagInfo, FlagImportant, FlagFollowUp or FlagNone)
+func (mc *MailClient) SetFlag(ctx context.Context, messageIDs []string, flag string) error {
+	return mc.updateMessages(ctx, "setFlag", messageIDs, map[string]interface{}{
+		"flagid": flag,
+	})
+}
+
+// MoveMessages moves messages to the destination folder
+func (mc *MailClient) MoveMessages(ctx context.Context, messageIDs []string, destFolderID string) error {
+	return mc.updateMessages(ctx, "moveMessage", messageIDs, map[string]interface{}{
+		"destfolderId": destFolderID,
+	})
+}
+
+// ApplyLabels adds labels to messages
+func (mc *MailClient) ApplyLabels(ctx context.Context, messageIDs, labelIDs []string) error {
+	return mc.updateMessages(ctx, "applyLabel", messageIDs, map[string]interface{}{
+		"labelId": labelIDs,
+	})
+}
+
+// RemoveLabels removes labels from messages
+func (mc *MailClient) RemoveLabels(ctx context.Context, messageIDs, labelIDs []string) error {
+	return mc.updateMessages(ctx, "removeLabel", messageIDs, map[string]interface{}{
+		"labelId": labelIDs,
+	})
+}
+
+// ArchiveMessages archives messages
+func (mc *MailClient) ArchiveMessages(ctx context.Context, messageIDs []string) error {
+	return mc.updateMessages(ctx, "archiveMails", messageIDs, nil)
+}
+
+// MarkSpam marks messages as spam and moves them to the Spam folder
+func (mc *MailClient) MarkSpam(ctx context.Context, messageIDs []string) error {
+	return mc.updateMessages(ctx, "markAsSpam", messageIDs, nil)
+}
+
+// MarkNotSpam marks messages as not spam and moves them back to the Inbox
+func (mc *MailClient) MarkNotSpam(ctx context.Context, messageIDs []string) error {
+	return mc.updateMessages(ctx, "markAsNotSpam", messageIDs, nil)
+}
+
+// DeleteMessage moves a message to Trash, or deletes it permanently if permanent is set
+func (mc *MailClient) DeleteMessage(ctx context.Context, folderID, messageID string, permanent bool) error {
+	path := fmt.Sprintf("/api/accounts/%s/folders/%s/messages/%s", mc.accountID, folderID, messageID)
+	if permanent {
+		path += "?expunge=true"
+	}
+
+	resp, err := mc.client.DoMail(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// updateMessages applies a bulk update mode to a set of messages
+func (mc *MailClient) updateMessages(ctx context.Context, mode string, messageIDs []string, extra map[string]interface{}) error {
+	reqBody := map[string]interface{}{
+		"mode":      mode,
+		"messageId": messageIDs,
+	}
+	for k, v := range extra {
+		reqBody[k] = v
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/updatemessage", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// decodeStatus parses a standard status-only response
+func decodeStatus(resp *http.Response) error {
+	var statusResp struct {
+		Status struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"status"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	if statusResp.Status.Code != 200 {
+		return fmt.Errorf("API error: %s (code %d)", statusResp.Status.Description, statusResp.Status.Code)
+	}
+
+	return nil
+}
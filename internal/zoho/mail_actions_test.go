@@ -0,0 +1,65 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func TestMailClientMessageState(t *testing.T) {
+	fake, mc := newMailClient(t)
+	a := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+	b := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "b"}})
+	ctx := context.Background()
+
+	require.NoError(t, mc.MarkRead(ctx, []string{a, b}))
+	assert.Equal(t, "1", fake.Message(a).Status)
+	assert.Equal(t, "1", fake.Message(b).Status)
+
+	require.NoError(t, mc.MarkUnread(ctx, []string{b}))
+	assert.Equal(t, "0", fake.Message(b).Status)
+
+	require.NoError(t, mc.SetFlag(ctx, []string{a}, zoho.FlagFollowUp))
+	assert.Equal(t, zoho.FlagFollowUp, fake.Message(a).FlagID)
+
+	require.NoError(t, mc.ArchiveMessages(ctx, []string{a}))
+	assert.True(t, fake.Message(a).Archived)
+}
+
+func TestMailClientMoveAndSpam(t *testing.T) {
+	fake, mc := newMailClient(t)
+	id := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+	projects := fake.AddFolder("Projects")
+	ctx := context.Background()
+
+	require.NoError(t, mc.MoveMessages(ctx, []string{id}, projects))
+	assert.Equal(t, projects, fake.Message(id).FolderID)
+
+	require.NoError(t, mc.MarkSpam(ctx, []string{id}))
+	assert.Equal(t, fake.FolderID("Spam"), fake.Message(id).FolderID)
+
+	require.NoError(t, mc.MarkNotSpam(ctx, []string{id}))
+	assert.Equal(t, fake.FolderID("Inbox"), fake.Message(id).FolderID)
+
+	err := mc.MoveMessages(ctx, []string{"missing"}, projects)
+	assert.Error(t, err)
+}
+
+func TestMailClientDeleteMessage(t *testing.T) {
+	fake, mc := newMailClient(t)
+	trashed := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+	purged := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "b"}})
+	inbox := fake.FolderID("Inbox")
+	ctx := context.Background()
+
+	require.NoError(t, mc.DeleteMessage(ctx, inbox, trashed, false))
+	assert.Equal(t, fake.FolderID("Trash"), fake.Message(trashed).FolderID)
+
+	require.NoError(t, mc.DeleteMessage(ctx, inbox, purged, true))
+	assert.Nil(t, fake.Message(purged))
+}
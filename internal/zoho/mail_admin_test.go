@@ -0,0 +1,48 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+func TestMailAdminClientSpamLists(t *testing.T) {
+	fake, cfg, ts := startFake(t)
+	mac, err := zoho.NewMailAdminClient(cfg, ts)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, mac.UpdateSpamList(ctx, zoho.SpamEmail, []string{"spam@bad.example"}))
+	require.NoError(t, mac.UpdateSpamList(ctx, zoho.SpamEmail, []string{"spam@bad.example", "more@bad.example"}))
+	assert.Equal(t, []string{"spam@bad.example", "more@bad.example"}, fake.SpamList(zoho.SpamEmail))
+
+	values, err := mac.GetSpamSettings(ctx, zoho.SpamEmail)
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+
+	_, err = mac.GetSpamSettings(ctx, zoho.SpamCategory("Bogus"))
+	assert.Error(t, err)
+}
+
+func TestMailAdminClientLogsAndRetention(t *testing.T) {
+	fake, cfg, ts := startFake(t)
+	mac, err := zoho.NewMailAdminClient(cfg, ts)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	fake.AddDeliveryLog(zoho.DeliveryLog{MessageID: "1", Status: "delivered"})
+	fake.AddDeliveryLog(zoho.DeliveryLog{MessageID: "2", Status: "bounced"})
+
+	logs, err := mac.GetDeliveryLogs(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "bounced", logs[0].Status)
+
+	policy, err := mac.GetRetentionPolicy(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(policy), "retentionPeriod")
+}
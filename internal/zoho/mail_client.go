@@ -1,6 +1,7 @@
 package zoho
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -233,6 +234,62 @@ func (mc *MailClient) GetMessageContent(ctx context.Context, folderID, messageID
 	return &contentResp.Data, nil
 }
 
+// GetOriginalMessage fetches the original RFC 822 source of a message,
+// including headers and every MIME part
+func (mc *MailClient) GetOriginalMessage(ctx context.Context, folderID, messageID string) ([]byte, error) {
+	path := fmt.Sprintf("/api/accounts/%s/folders/%s/messages/%s/originalmessage", mc.accountID, folderID, messageID)
+	resp, err := mc.client.DoMail(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read message source: %w", err)
+	}
+	return data, nil
+}
+
+// ImportMessage stores an RFC 822 message in a folder without sending it and
+// returns the new message ID. The message keeps the date from its Date header.
+func (mc *MailClient) ImportMessage(ctx context.Context, folderID string, raw []byte, read bool) (string, error) {
+	// Build URL manually (bypass doRequest which sets application/json)
+	importURL := mc.client.region.MailBase + fmt.Sprintf("/api/accounts/%s/folders/%s/messages/import?isUnread=%t",
+		mc.accountID, folderID, !read)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, importURL, bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "message/rfc822")
+
+	resp, err := mc.client.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("import failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", mc.parseErrorResponse(resp)
+	}
+
+	var importResp SendEmailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&importResp); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	if importResp.Status.Code != 200 {
+		return "", fmt.Errorf("API error: %s (code %d)", importResp.Status.Description, importResp.Status.Code)
+	}
+
+	return importResp.Data.MessageID, nil
+}
+
 // SearchMessages searches messages using Zoho search syntax
 func (mc *MailClient) SearchMessages(ctx context.Context, searchKey string, start, limit int) ([]MessageSummary, error) {
 	path := fmt.Sprintf("/api/accounts/%s/messages/search?searchKey=%s&start=%d&limit=%d",
@@ -337,33 +394,44 @@ func (mc *MailClient) ListAttachments(ctx context.Context, folderID, messageID s
 	return attachmentResp.Data, nil
 }
 
-// DownloadAttachment downloads an attachment to a file
+// DownloadAttachment downloads an attachment to a file.
+// The content is streamed to destPath.part and renamed to destPath once complete,
+// so destPath never holds a partial file. A .part file left by an interrupted
+// download is resumed when the server honors range requests; which attachment
+// it holds is recorded in destPath.part.json, and a partial file of another
+// attachment, or of content that has since changed, is started over.
 func (mc *MailClient) DownloadAttachment(ctx context.Context, folderID, messageID, attachmentID, destPath string) error {
+	ctx, cancel := mc.client.transferContext(ctx)
+	defer cancel()
+
 	path := fmt.Sprintf("/api/accounts/%s/folders/%s/messages/%s/attachments/%s",
 		mc.accountID, folderID, messageID, attachmentID)
-	resp, err := mc.client.DoMail(ctx, http.MethodGet, path, nil)
+	partPath := destPath + ".part"
+	partial, offset := loadPartialDownload(partPath, path)
+	resp, offset, total, err := mc.openDownload(ctx, path, offset, partial)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return mc.parseErrorResponse(resp)
+	// Append to the partial file when resuming, otherwise start it afresh
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	} else if err := newPartialDownload(path, resp, total).save(partPath); err != nil {
+		return fmt.Errorf("create file %s: %w", partialMetaPath(partPath), err)
 	}
-
-	// Create destination file
-	file, err := os.Create(destPath)
+	file, err := os.OpenFile(partPath, flags, 0o644)
 	if err != nil {
-		return fmt.Errorf("create file %s: %w", destPath, err)
+		return fmt.Errorf("create file %s: %w", partPath, err)
 	}
 
-	// Stream response to file
-	_, err = io.Copy(file, resp.Body)
+	// Stream response to file; an interrupted download keeps its .part file for resuming
+	body := &progressReader{r: resp.Body, done: offset, total: total, fn: progressFrom(ctx)}
+	_, err = io.Copy(file, body)
 	closeErr := file.Close()
 
 	if err != nil {
-		// Best-effort cleanup of partial download
-		os.Remove(destPath)
 		return fmt.Errorf("download failed: %w", err)
 	}
 
@@ -371,5 +439,53 @@ func (mc *MailClient) DownloadAttachment(ctx context.Context, folderID, messageI
 		return fmt.Errorf("close file: %w", closeErr)
 	}
 
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("rename %s: %w", partPath, err)
+	}
+	os.Remove(partialMetaPath(partPath))
+
 	return nil
 }
+
+// openDownload requests an attachment from offset onwards, continuing partial
+// when offset > 0. It returns the response, the offset its body starts at and
+// the full size (-1 if unknown).
+func (mc *MailClient) openDownload(ctx context.Context, path string, offset int64, partial *partialDownload) (*http.Response, int64, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mc.client.region.MailBase+path, nil)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("create request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		// The server sends the whole attachment instead if it has changed
+		if v := partial.validator(); v != "" {
+			req.Header.Set("If-Range", v)
+		}
+	}
+
+	resp, err := mc.client.transferClient.Do(req)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("request failed: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// The server ignored the range, so the download starts over
+		return resp, 0, resp.ContentLength, nil
+	case http.StatusPartialContent:
+		if ok, total := resumed(resp, offset); ok && offset > 0 && partial.continues(resp, total) {
+			return resp, offset, total, nil
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+	default:
+		defer resp.Body.Close()
+		return nil, 0, 0, mc.parseErrorResponse(resp)
+	}
+
+	// The partial file does not match the attachment: start over
+	resp.Body.Close()
+	if offset == 0 {
+		return nil, 0, 0, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return mc.openDownload(ctx, path, 0, nil)
+}
@@ -0,0 +1,230 @@
+package zoho_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+// startFake runs a fake Zoho server and returns a config and token source pointed at it
+func startFake(t *testing.T) (*zohotest.Server, *config.Config, oauth2.TokenSource) {
+	t.Helper()
+	fake := zohotest.New()
+	fake.AccessToken = "test-token"
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	unthrottle(t)
+
+	cfg := &config.Config{Region: "us", APIBase: srv.URL}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+	return fake, cfg, ts
+}
+
+// unthrottle lifts the client rate limit for the rest of the test;
+// the fake server has none
+func unthrottle(t *testing.T) {
+	t.Helper()
+	orig := zoho.ClientRateLimiter
+	zoho.ClientRateLimiter = func() *rate.Limiter { return rate.NewLimiter(rate.Inf, 0) }
+	t.Cleanup(func() { zoho.ClientRateLimiter = orig })
+}
+
+func newMailClient(t *testing.T) (*zohotest.Server, *zoho.MailClient) {
+	t.Helper()
+	fake, cfg, ts := startFake(t)
+	mc, err := zoho.NewMailClient(cfg, ts)
+	require.NoError(t, err)
+	return fake, mc
+}
+
+func TestMailClientRejectsBadToken(t *testing.T) {
+	_, cfg, _ := startFake(t)
+	_, err := zoho.NewMailClient(cfg, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "wrong"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 401")
+}
+
+func TestMailClientListMessagesPaginates(t *testing.T) {
+	fake, mc := newMailClient(t)
+	for _, subject := range []string{"one", "two", "three"} {
+		fake.AddMessage(zohotest.Message{
+			MessageMetadata: zoho.MessageMetadata{Subject: subject, FromAddress: "a@example.com"},
+		})
+	}
+	inbox := fake.FolderID("Inbox")
+	ctx := context.Background()
+
+	first, err := mc.ListMessages(ctx, inbox, 0, 2)
+	require.NoError(t, err)
+	second, err := mc.ListMessages(ctx, inbox, 2, 2)
+	require.NoError(t, err)
+
+	// Newest first, and no overlap between pages
+	require.Len(t, first, 2)
+	require.Len(t, second, 1)
+	assert.Equal(t, "three", first[0].Subject)
+	assert.Equal(t, "two", first[1].Subject)
+	assert.Equal(t, "one", second[0].Subject)
+}
+
+func TestMailClientSearchMessages(t *testing.T) {
+	fake, mc := newMailClient(t)
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Invoice 42", FromAddress: "billing@example.com"},
+	})
+	fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Lunch", FromAddress: "bob@example.com"},
+	})
+
+	query := zoho.NewSearchQuery().From("billing").Subject("invoice").Build()
+	msgs, err := mc.SearchMessages(context.Background(), query, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "Invoice 42", msgs[0].Subject)
+}
+
+func TestMailClientMessageDetailsAndAttachments(t *testing.T) {
+	fake, mc := newMailClient(t)
+	id := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Report", FromAddress: "a@example.com"},
+		Content:         "<p>see attached</p>",
+		Attachments: []zohotest.StoredAttachment{{
+			Attachment: zoho.Attachment{AttachmentName: "r.csv"},
+			Data:       []byte("a,b\n"),
+		}},
+	})
+	inbox := fake.FolderID("Inbox")
+	ctx := context.Background()
+
+	meta, err := mc.GetMessageMetadata(ctx, inbox, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Report", meta.Subject)
+	assert.Equal(t, "1", meta.HasAttachment)
+
+	content, err := mc.GetMessageContent(ctx, inbox, id)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>see attached</p>", content.Content)
+
+	atts, err := mc.ListAttachments(ctx, inbox, id)
+	require.NoError(t, err)
+	require.Len(t, atts, 1)
+
+	dest := filepath.Join(t.TempDir(), "r.csv")
+	require.NoError(t, mc.DownloadAttachment(ctx, inbox, id, atts[0].AttachmentID, dest))
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n", string(data))
+}
+
+func TestMailClientGetOriginalMessage(t *testing.T) {
+	fake, mc := newMailClient(t)
+	raw := "From: a@example.com\r\nSubject: Raw\r\n\r\nbody\r\n"
+	rawID := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Raw", FromAddress: "a@example.com"},
+		Raw:             []byte(raw),
+	})
+	builtID := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Report", FromAddress: "a@example.com"},
+		Content:         "<p>see attached</p>",
+		Attachments: []zohotest.StoredAttachment{{
+			Attachment: zoho.Attachment{AttachmentName: "r.csv", AttachmentType: "text/csv"},
+			Data:       []byte("a,b\n"),
+		}},
+	})
+	inbox := fake.FolderID("Inbox")
+	ctx := context.Background()
+
+	data, err := mc.GetOriginalMessage(ctx, inbox, rawID)
+	require.NoError(t, err)
+	assert.Equal(t, raw, string(data))
+
+	data, err = mc.GetOriginalMessage(ctx, inbox, builtID)
+	require.NoError(t, err)
+	parsed, err := zoho.ParseRawMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "Report", parsed.Request.Subject)
+	require.Len(t, parsed.Parts, 1)
+	assert.Equal(t, "r.csv", parsed.Parts[0].FileName)
+	assert.Equal(t, "a,b\n", string(parsed.Parts[0].Data))
+
+	_, err = mc.GetOriginalMessage(ctx, inbox, "missing")
+	assert.Error(t, err)
+}
+
+func TestMailClientSendWithAttachment(t *testing.T) {
+	fake, mc := newMailClient(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "note.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0600))
+
+	ref, err := mc.UploadAttachment(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "note.txt", ref.AttachmentName)
+
+	err = mc.SendEmail(ctx, &zoho.SendEmailRequest{
+		ToAddress:   "bob@example.com",
+		Subject:     "Hi",
+		Content:     "hello",
+		Attachments: []zoho.AttachmentReference{*ref},
+	})
+	require.NoError(t, err)
+
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "bob@example.com", sent[0].ToAddress)
+	assert.Len(t, sent[0].Attachments, 1)
+}
+
+func TestMailClientAccountSettings(t *testing.T) {
+	fake, mc := newMailClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, mc.UpdateDisplayName(ctx, "Ops"))
+	assert.Equal(t, "Ops", fake.DisplayName())
+
+	vacation := &zoho.VacationReply{
+		FromDate:   "11/02/2026 00:00:00",
+		ToDate:     "11/03/2026 23:59:59",
+		SendingInt: 1440,
+		Subject:    "Away",
+		Content:    "Back on Wednesday",
+		SendTo:     "all",
+	}
+	require.NoError(t, mc.AddVacationReply(ctx, vacation))
+	details, err := mc.GetAccountDetails(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(details.VacationResponse), "Away")
+
+	parsed, err := zoho.ParseVacationReply(details.VacationResponse)
+	require.NoError(t, err)
+	assert.Equal(t, vacation, parsed)
+
+	require.NoError(t, mc.DisableVacationReply(ctx))
+	assert.Nil(t, fake.Vacation())
+
+	// Invalid replies are rejected before reaching Zoho
+	for _, mutate := range []func(v *zoho.VacationReply){
+		func(v *zoho.VacationReply) { v.SendTo = "everyone" },
+		func(v *zoho.VacationReply) { v.SendingInt = 0 },
+		func(v *zoho.VacationReply) { v.ToDate = "11/01/2026 00:00:00" },
+		func(v *zoho.VacationReply) { v.FromDate = "2026-11-02" },
+	} {
+		bad := *vacation
+		mutate(&bad)
+		assert.Error(t, mc.AddVacationReply(ctx, &bad))
+	}
+	assert.Nil(t, fake.Vacation())
+}
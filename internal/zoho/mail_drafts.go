@@ -0,0 +1,33 @@
+package zoho
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SaveDraft saves a message as a draft and returns the draft's message ID.
+// For a reply or forward draft, pass the original messageID and set req.Action.
+func (mc *MailClient) SaveDraft(ctx context.Context, messageID string, req *SendEmailRequest) (string, error) {
+	req.Mode = "draft"
+	path := fmt.Sprintf("/api/accounts/%s/messages", mc.accountID)
+	if messageID != "" {
+		path += "/" + messageID
+	}
+	return mc.sendEmailRequest(ctx, http.MethodPost, path, req)
+}
+
+// UpdateDraft replaces the recipients, subject and content of a saved draft
+func (mc *MailClient) UpdateDraft(ctx context.Context, draftID string, req *SendEmailRequest) error {
+	req.Mode = "draft"
+	path := fmt.Sprintf("/api/accounts/%s/messages/%s", mc.accountID, draftID)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPut, path, req)
+	return err
+}
+
+// SendDraft sends a saved draft; the draft is removed from the Drafts folder
+func (mc *MailClient) SendDraft(ctx context.Context, draftID string) error {
+	path := fmt.Sprintf("/api/accounts/%s/messages/%s", mc.accountID, draftID)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPut, path, &SendEmailRequest{Mode: "sendDraft"})
+	return err
+}
@@ -0,0 +1,43 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+func TestMailClientDraftLifecycle(t *testing.T) {
+	fake, mc := newMailClient(t)
+	ctx := context.Background()
+
+	draftID, err := mc.SaveDraft(ctx, "", &zoho.SendEmailRequest{
+		ToAddress:  "bob@example.com",
+		Subject:    "Draft",
+		Content:    "first",
+		MailFormat: "plaintext",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, draftID)
+	assert.Equal(t, fake.FolderID("Drafts"), fake.Message(draftID).FolderID)
+	assert.Empty(t, fake.Sent())
+
+	require.NoError(t, mc.UpdateDraft(ctx, draftID, &zoho.SendEmailRequest{
+		ToAddress: "bob@example.com",
+		Subject:   "Draft v2",
+		Content:   "second",
+	}))
+	assert.Equal(t, "Draft v2", fake.Drafts()[draftID].Subject)
+
+	require.NoError(t, mc.SendDraft(ctx, draftID))
+	sent := fake.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "Draft v2", sent[0].Subject)
+	assert.Nil(t, fake.Message(draftID))
+
+	// A sent draft is gone
+	assert.Error(t, mc.SendDraft(ctx, draftID))
+}
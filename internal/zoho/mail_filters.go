@@ -0,0 +1,158 @@
+package zoho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ListFilters fetches the account's incoming mail filters in the order they run
+func (mc *MailClient) ListFilters(ctx context.Context) ([]MailFilter, error) {
+	path := fmt.Sprintf("/api/accounts/%s/filters", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	var filterResp MailFilterListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&filterResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if filterResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", filterResp.Status.Description, filterResp.Status.Code)
+	}
+
+	filters := filterResp.Data
+	sort.SliceStable(filters, func(i, j int) bool { return filters[i].FilterOrder < filters[j].FilterOrder })
+	return filters, nil
+}
+
+// GetFilter fetches a single filter
+func (mc *MailClient) GetFilter(ctx context.Context, filterID string) (*MailFilter, error) {
+	path := fmt.Sprintf("/api/accounts/%s/filters/%s", mc.accountID, filterID)
+	resp, err := mc.client.DoMail(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	var filterResp MailFilterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&filterResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if filterResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", filterResp.Status.Description, filterResp.Status.Code)
+	}
+
+	return &filterResp.Data, nil
+}
+
+// CreateFilter adds a filter after the existing ones and returns it with its ID
+func (mc *MailClient) CreateFilter(ctx context.Context, filter *MailFilter) (*MailFilter, error) {
+	req := *filter
+	req.FilterID, req.FilterOrder = "", 0
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/filters", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	var filterResp MailFilterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&filterResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if filterResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", filterResp.Status.Description, filterResp.Status.Code)
+	}
+
+	return &filterResp.Data, nil
+}
+
+// UpdateFilter replaces a filter's name, conditions, actions and enabled state;
+// its position is left unchanged
+func (mc *MailClient) UpdateFilter(ctx context.Context, filterID string, filter *MailFilter) error {
+	req := *filter
+	req.FilterID, req.FilterOrder = "", 0
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/filters/%s", mc.accountID, filterID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// DeleteFilter deletes a filter
+func (mc *MailClient) DeleteFilter(ctx context.Context, filterID string) error {
+	path := fmt.Sprintf("/api/accounts/%s/filters/%s", mc.accountID, filterID)
+	resp, err := mc.client.DoMail(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// ReorderFilters sets the order filters run in; filterIDs must list every filter
+func (mc *MailClient) ReorderFilters(ctx context.Context, filterIDs []string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"mode":      "reorder",
+		"filterIds": filterIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/filters", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
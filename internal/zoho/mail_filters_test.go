@@ -0,0 +1,62 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+func TestMailClientFilterLifecycle(t *testing.T) {
+	fake, mc := newMailClient(t)
+	ctx := context.Background()
+
+	first := fake.AddFilter(zoho.MailFilter{
+		FilterName:    "Newsletters",
+		Enabled:       true,
+		ConditionType: "any",
+		Conditions:    []zoho.FilterCondition{{Field: "sender", Comparison: "contains", Value: "news@"}},
+		Actions:       []zoho.FilterAction{{ActionType: "markAsRead"}},
+	})
+
+	created, err := mc.CreateFilter(ctx, &zoho.MailFilter{
+		FilterName:    "Alerts",
+		Enabled:       true,
+		ConditionType: "all",
+		Conditions:    []zoho.FilterCondition{{Field: "subject", Comparison: "startsWith", Value: "[ALERT]"}},
+		Actions:       []zoho.FilterAction{{ActionType: zoho.FilterActionMove, ActionValue: "f-1"}},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.FilterID)
+	assert.Equal(t, 2, created.FilterOrder)
+
+	created.Enabled = false
+	created.FilterName = "Pager alerts"
+	require.NoError(t, mc.UpdateFilter(ctx, created.FilterID, created))
+	got, err := mc.GetFilter(ctx, created.FilterID)
+	require.NoError(t, err)
+	assert.Equal(t, "Pager alerts", got.FilterName)
+	assert.False(t, got.Enabled)
+	assert.Equal(t, 2, got.FilterOrder)
+
+	require.NoError(t, mc.ReorderFilters(ctx, []string{created.FilterID, first}))
+	filters, err := mc.ListFilters(ctx)
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+	assert.Equal(t, "Pager alerts", filters[0].FilterName)
+	assert.Equal(t, "Newsletters", filters[1].FilterName)
+
+	// Every filter must be placed
+	assert.Error(t, mc.ReorderFilters(ctx, []string{first}))
+
+	require.NoError(t, mc.DeleteFilter(ctx, created.FilterID))
+	filters = fake.Filters()
+	require.Len(t, filters, 1)
+	assert.Equal(t, 1, filters[0].FilterOrder)
+
+	_, err = mc.GetFilter(ctx, created.FilterID)
+	assert.Error(t, err)
+}
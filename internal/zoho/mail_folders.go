@@ -0,0 +1,113 @@
+package zoho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CreateFolder creates a folder, nested under parentFolderID if it is set
+func (mc *MailClient) CreateFolder(ctx context.Context, name, parentFolderID string) (*Folder, error) {
+	reqBody := map[string]interface{}{
+		"folderName": name,
+	}
+	if parentFolderID != "" {
+		reqBody["parentFolderId"] = parentFolderID
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/folders", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	var folderResp FolderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&folderResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if folderResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", folderResp.Status.Description, folderResp.Status.Code)
+	}
+
+	return &folderResp.Data, nil
+}
+
+// RenameFolder renames a folder
+func (mc *MailClient) RenameFolder(ctx context.Context, folderID, name string) error {
+	return mc.updateFolder(ctx, folderID, map[string]interface{}{
+		"mode":       "rename",
+		"folderName": name,
+	})
+}
+
+// MoveFolder moves a folder under a new parent folder
+func (mc *MailClient) MoveFolder(ctx context.Context, folderID, parentFolderID string) error {
+	return mc.updateFolder(ctx, folderID, map[string]interface{}{
+		"mode":           "move",
+		"parentFolderId": parentFolderID,
+	})
+}
+
+// EmptyFolder permanently deletes every message in a folder
+func (mc *MailClient) EmptyFolder(ctx context.Context, folderID string) error {
+	return mc.updateFolder(ctx, folderID, map[string]interface{}{
+		"mode": "emptyFolder",
+	})
+}
+
+// MarkFolderRead marks every message in a folder as read
+func (mc *MailClient) MarkFolderRead(ctx context.Context, folderID string) error {
+	return mc.updateFolder(ctx, folderID, map[string]interface{}{
+		"mode": "markAsRead",
+	})
+}
+
+// DeleteFolder deletes a folder and the messages in it
+func (mc *MailClient) DeleteFolder(ctx context.Context, folderID string) error {
+	path := fmt.Sprintf("/api/accounts/%s/folders/%s", mc.accountID, folderID)
+	resp, err := mc.client.DoMail(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// updateFolder applies an update mode to a folder
+func (mc *MailClient) updateFolder(ctx context.Context, folderID string, reqBody map[string]interface{}) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/folders/%s", mc.accountID, folderID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
@@ -0,0 +1,53 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func TestMailClientFolderHierarchy(t *testing.T) {
+	_, mc := newMailClient(t)
+	ctx := context.Background()
+
+	parent, err := mc.CreateFolder(ctx, "Projects", "")
+	require.NoError(t, err)
+	child, err := mc.CreateFolder(ctx, "Alpha", parent.FolderID)
+	require.NoError(t, err)
+	assert.Equal(t, "/Projects/Alpha", child.Path)
+	assert.Equal(t, parent.FolderID, child.ParentFolderID)
+
+	require.NoError(t, mc.RenameFolder(ctx, parent.FolderID, "Work"))
+	folders, err := mc.ListFolders(ctx)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, f := range folders {
+		paths = append(paths, f.Path)
+	}
+	assert.Contains(t, paths, "/Work/Alpha")
+}
+
+func TestMailClientFolderMessages(t *testing.T) {
+	fake, mc := newMailClient(t)
+	archive := fake.AddFolder("Archive")
+	read := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a", FolderID: archive}})
+	ctx := context.Background()
+
+	require.NoError(t, mc.MarkFolderRead(ctx, archive))
+	assert.Equal(t, "1", fake.Message(read).Status)
+
+	require.NoError(t, mc.EmptyFolder(ctx, archive))
+	assert.Nil(t, fake.Message(read))
+
+	require.NoError(t, mc.DeleteFolder(ctx, archive))
+	assert.Empty(t, fake.FolderID("Archive"))
+
+	// System folders cannot be deleted
+	assert.Error(t, mc.DeleteFolder(ctx, fake.FolderID("Inbox")))
+}
@@ -0,0 +1,91 @@
+package zoho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CreateLabel creates a label with an optional color (e.g. "#1E88E5")
+func (mc *MailClient) CreateLabel(ctx context.Context, name, color string) (*Label, error) {
+	reqBody := map[string]interface{}{
+		"labelName": name,
+	}
+	if color != "" {
+		reqBody["labelColor"] = color
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/labels", mc.accountID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, mc.parseErrorResponse(resp)
+	}
+
+	var labelResp LabelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&labelResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if labelResp.Status.Code != 200 {
+		return nil, fmt.Errorf("API error: %s (code %d)", labelResp.Status.Description, labelResp.Status.Code)
+	}
+
+	return &labelResp.Data, nil
+}
+
+// UpdateLabel renames and/or recolors a label; empty arguments are left unchanged
+func (mc *MailClient) UpdateLabel(ctx context.Context, labelID, name, color string) error {
+	reqBody := map[string]interface{}{}
+	if name != "" {
+		reqBody["labelName"] = name
+	}
+	if color != "" {
+		reqBody["labelColor"] = color
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/accounts/%s/labels/%s", mc.accountID, labelID)
+	resp, err := mc.client.DoMail(ctx, http.MethodPut, path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
+
+// DeleteLabel deletes a label; messages carrying it are kept
+func (mc *MailClient) DeleteLabel(ctx context.Context, labelID string) error {
+	path := fmt.Sprintf("/api/accounts/%s/labels/%s", mc.accountID, labelID)
+	resp, err := mc.client.DoMail(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mc.parseErrorResponse(resp)
+	}
+
+	return decodeStatus(resp)
+}
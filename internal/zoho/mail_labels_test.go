@@ -0,0 +1,41 @@
+package zoho_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+func TestMailClientLabelLifecycle(t *testing.T) {
+	fake, mc := newMailClient(t)
+	id := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "a"}})
+	ctx := context.Background()
+
+	label, err := mc.CreateLabel(ctx, "Urgent", "#FF0000")
+	require.NoError(t, err)
+	assert.Equal(t, "Urgent", label.LabelName)
+	assert.Equal(t, "#FF0000", label.LabelColor)
+
+	require.NoError(t, mc.UpdateLabel(ctx, label.LabelID, "Critical", ""))
+	labels, err := mc.ListLabels(ctx)
+	require.NoError(t, err)
+	require.Len(t, labels, 1)
+	assert.Equal(t, "Critical", labels[0].LabelName)
+	assert.Equal(t, "#FF0000", labels[0].LabelColor)
+
+	require.NoError(t, mc.ApplyLabels(ctx, []string{id}, []string{label.LabelID}))
+	assert.Equal(t, []string{label.LabelID}, fake.Message(id).LabelIDs)
+	require.NoError(t, mc.RemoveLabels(ctx, []string{id}, []string{label.LabelID}))
+	assert.Empty(t, fake.Message(id).LabelIDs)
+
+	require.NoError(t, mc.ApplyLabels(ctx, []string{id}, []string{label.LabelID}))
+	require.NoError(t, mc.DeleteLabel(ctx, label.LabelID))
+	assert.Empty(t, fake.LabelID("Critical"))
+	assert.Empty(t, fake.Message(id).LabelIDs)
+	assert.NotNil(t, fake.Message(id))
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -20,25 +22,49 @@ func (mc *MailClient) UploadAttachment(ctx context.Context, filePath string) (*A
 	}
 	defer file.Close()
 
-	// Extract filename
-	fileName := filepath.Base(filePath)
+	return mc.UploadAttachmentData(ctx, filepath.Base(filePath), file, false)
+}
+
+// UploadAttachmentData uploads attachment content read from r under fileName.
+// Inline uploads are images referenced from an HTML body rather than listed as attachments.
+// Content over MaxAttachmentSize is rejected with an *AttachmentTooLargeError, before
+// the upload starts when the size of r is known and as soon as the limit is passed otherwise.
+func (mc *MailClient) UploadAttachmentData(ctx context.Context, fileName string, r io.Reader, inline bool) (*AttachmentReference, error) {
+	size := readerSize(r)
+	if err := CheckAttachmentSize(fileName, size); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := mc.client.transferContext(ctx)
+	defer cancel()
 
 	// Build URL manually (bypass doRequest which sets application/json)
 	uploadURL := mc.client.region.MailBase + fmt.Sprintf("/api/accounts/%s/messages/attachments?fileName=%s",
 		mc.accountID, url.QueryEscape(fileName))
+	if inline {
+		uploadURL += "&isInline=true"
+	}
 
-	// Create request with file body
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, file)
+	// Create request with file body, counted for progress and the size limit
+	body := &progressReader{r: r, name: fileName, total: size, limit: MaxAttachmentSize, fn: progressFrom(ctx)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
 
 	// CRITICAL: Set Content-Type to application/octet-stream (NOT application/json)
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	// Execute via HTTP client (bypassing DoMail to avoid automatic JSON content-type)
-	resp, err := mc.client.httpClient.Do(req)
+	// Execute via the transfer client (bypassing DoMail to avoid automatic JSON content-type)
+	resp, err := mc.client.transferClient.Do(req)
 	if err != nil {
+		var tooLarge *AttachmentTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, tooLarge
+		}
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 	defer resp.Body.Close()
@@ -62,57 +88,62 @@ func (mc *MailClient) UploadAttachment(ctx context.Context, filePath string) (*A
 // SendEmail sends a new email message
 func (mc *MailClient) SendEmail(ctx context.Context, req *SendEmailRequest) error {
 	path := fmt.Sprintf("/api/accounts/%s/messages", mc.accountID)
-	return mc.sendEmailRequest(ctx, path, req)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPost, path, req)
+	return err
 }
 
 // ReplyToEmail replies to a message
 func (mc *MailClient) ReplyToEmail(ctx context.Context, messageID string, req *SendEmailRequest) error {
 	req.Action = "reply"
 	path := fmt.Sprintf("/api/accounts/%s/messages/%s", mc.accountID, messageID)
-	return mc.sendEmailRequest(ctx, path, req)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPost, path, req)
+	return err
 }
 
 // ReplyAllToEmail replies to all recipients of a message
 func (mc *MailClient) ReplyAllToEmail(ctx context.Context, messageID string, req *SendEmailRequest) error {
 	req.Action = "replyall"
 	path := fmt.Sprintf("/api/accounts/%s/messages/%s", mc.accountID, messageID)
-	return mc.sendEmailRequest(ctx, path, req)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPost, path, req)
+	return err
 }
 
 // ForwardEmail forwards a message to new recipients
 func (mc *MailClient) ForwardEmail(ctx context.Context, messageID string, req *SendEmailRequest) error {
 	req.Action = "forward"
 	path := fmt.Sprintf("/api/accounts/%s/messages/%s", mc.accountID, messageID)
-	return mc.sendEmailRequest(ctx, path, req)
+	_, err := mc.sendEmailRequest(ctx, http.MethodPost, path, req)
+	return err
 }
 
-// sendEmailRequest is a private helper for all send operations
-func (mc *MailClient) sendEmailRequest(ctx context.Context, path string, req *SendEmailRequest) error {
+// sendEmailRequest is a private helper for all send and draft operations,
+// returning the ID of the resulting message
+func (mc *MailClient) sendEmailRequest(ctx context.Context, method, path string, req *SendEmailRequest) (string, error) {
 	// Marshal request to JSON
 	body, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return "", fmt.Errorf("marshal request: %w", err)
 	}
 
-	// POST via DoMail (sets Content-Type: application/json)
-	resp, err := mc.client.DoMail(ctx, http.MethodPost, path, bytes.NewReader(body))
+	// Send via DoMail (sets Content-Type: application/json)
+	resp, err := mc.client.DoMail(ctx, method, path, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return "", fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return mc.parseErrorResponse(resp)
+		return "", mc.parseErrorResponse(resp)
 	}
 
 	var sendResp SendEmailResponse
 	if err := json.NewDecoder(resp.Body).Decode(&sendResp); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+		return "", fmt.Errorf("decode response: %w", err)
 	}
 
 	if sendResp.Status.Code != 200 {
-		return fmt.Errorf("API error: %s (code %d)", sendResp.Status.Description, sendResp.Status.Code)
+		return "", fmt.Errorf("API error: %s (code %d)", sendResp.Status.Description, sendResp.Status.Code)
 	}
 
-	return nil
+	return sendResp.Data.MessageID, nil
 }
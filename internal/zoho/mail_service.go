@@ -1,6 +1,9 @@
 package zoho
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // MailService defines the interface for Zoho mail operations.
 type MailService interface {
@@ -8,18 +11,42 @@ type MailService interface {
 	ListFolders(ctx context.Context) ([]Folder, error)
 	GetFolderByName(ctx context.Context, name string) (*Folder, error)
 	ListLabels(ctx context.Context) ([]Label, error)
+	CreateFolder(ctx context.Context, name, parentFolderID string) (*Folder, error)
+	RenameFolder(ctx context.Context, folderID, name string) error
+	MoveFolder(ctx context.Context, folderID, parentFolderID string) error
+	EmptyFolder(ctx context.Context, folderID string) error
+	MarkFolderRead(ctx context.Context, folderID string) error
+	DeleteFolder(ctx context.Context, folderID string) error
+	CreateLabel(ctx context.Context, name, color string) (*Label, error)
+	UpdateLabel(ctx context.Context, labelID, name, color string) error
+	DeleteLabel(ctx context.Context, labelID string) error
 
 	// Message operations
 	ListMessages(ctx context.Context, folderID string, start, limit int) ([]MessageSummary, error)
 	GetMessageMetadata(ctx context.Context, folderID, messageID string) (*MessageMetadata, error)
 	GetMessageContent(ctx context.Context, folderID, messageID string) (*MessageContent, error)
+	GetOriginalMessage(ctx context.Context, folderID, messageID string) ([]byte, error)
+	ImportMessage(ctx context.Context, folderID string, raw []byte, read bool) (string, error)
 	SearchMessages(ctx context.Context, searchKey string, start, limit int) ([]MessageSummary, error)
 	GetThread(ctx context.Context, folderID, threadID string, limit int) ([]MessageSummary, error)
 
+	// Message state operations
+	MarkRead(ctx context.Context, messageIDs []string) error
+	MarkUnread(ctx context.Context, messageIDs []string) error
+	SetFlag(ctx context.Context, messageIDs []string, flag string) error
+	MoveMessages(ctx context.Context, messageIDs []string, destFolderID string) error
+	ApplyLabels(ctx context.Context, messageIDs, labelIDs []string) error
+	RemoveLabels(ctx context.Context, messageIDs, labelIDs []string) error
+	ArchiveMessages(ctx context.Context, messageIDs []string) error
+	MarkSpam(ctx context.Context, messageIDs []string) error
+	MarkNotSpam(ctx context.Context, messageIDs []string) error
+	DeleteMessage(ctx context.Context, folderID, messageID string, permanent bool) error
+
 	// Attachment operations
 	ListAttachments(ctx context.Context, folderID, messageID string) ([]Attachment, error)
 	DownloadAttachment(ctx context.Context, folderID, messageID, attachmentID, destPath string) error
 	UploadAttachment(ctx context.Context, filePath string) (*AttachmentReference, error)
+	UploadAttachmentData(ctx context.Context, fileName string, r io.Reader, inline bool) (*AttachmentReference, error)
 
 	// Send operations
 	SendEmail(ctx context.Context, req *SendEmailRequest) error
@@ -27,6 +54,11 @@ type MailService interface {
 	ReplyAllToEmail(ctx context.Context, messageID string, req *SendEmailRequest) error
 	ForwardEmail(ctx context.Context, messageID string, req *SendEmailRequest) error
 
+	// Draft operations
+	SaveDraft(ctx context.Context, messageID string, req *SendEmailRequest) (string, error)
+	UpdateDraft(ctx context.Context, draftID string, req *SendEmailRequest) error
+	SendDraft(ctx context.Context, draftID string) error
+
 	// Settings operations
 	ListSignatures(ctx context.Context) ([]Signature, error)
 	AddSignature(ctx context.Context, sig *Signature) (string, error)
@@ -34,6 +66,14 @@ type MailService interface {
 	AddVacationReply(ctx context.Context, vacation *VacationReply) error
 	DisableVacationReply(ctx context.Context) error
 	UpdateDisplayName(ctx context.Context, displayName string) error
+
+	// Filter operations
+	ListFilters(ctx context.Context) ([]MailFilter, error)
+	GetFilter(ctx context.Context, filterID string) (*MailFilter, error)
+	CreateFilter(ctx context.Context, filter *MailFilter) (*MailFilter, error)
+	UpdateFilter(ctx context.Context, filterID string, filter *MailFilter) error
+	DeleteFilter(ctx context.Context, filterID string) error
+	ReorderFilters(ctx context.Context, filterIDs []string) error
 }
 
 // Compile-time interface compliance check
@@ -102,6 +102,10 @@ func (mc *MailClient) GetAccountDetails(ctx context.Context) (*AccountDetails, e
 
 // AddVacationReply enables vacation auto-reply with specified settings
 func (mc *MailClient) AddVacationReply(ctx context.Context, vacation *VacationReply) error {
+	if err := vacation.Validate(); err != nil {
+		return err
+	}
+
 	reqBody := map[string]interface{}{
 		"mode": "addVacationReply",
 		"vacationResponse": map[string]interface{}{
@@ -1,6 +1,13 @@
 package zoho
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
 
 // MailAccount represents a Zoho Mail account
 type MailAccount struct {
@@ -24,12 +31,22 @@ type MailAccountListResponse struct {
 
 // Folder represents a mail folder
 type Folder struct {
-	FolderID     string `json:"folderId"`
-	FolderName   string `json:"folderName"`
-	FolderType   string `json:"folderType"`
-	Path         string `json:"path"`
-	UnreadCount  int    `json:"unreadCount"`
-	MessageCount int    `json:"messageCount"`
+	FolderID       string `json:"folderId"`
+	FolderName     string `json:"folderName"`
+	FolderType     string `json:"folderType"`
+	Path           string `json:"path"`
+	ParentFolderID string `json:"parentFolderId,omitempty"`
+	UnreadCount    int    `json:"unreadCount"`
+	MessageCount   int    `json:"messageCount"`
+}
+
+// FolderResponse is the response for create folder
+type FolderResponse struct {
+	Status struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Data Folder `json:"data"`
 }
 
 // FolderListResponse is the response for list folders
@@ -48,6 +65,15 @@ type Label struct {
 	LabelColor string `json:"labelColor"`
 }
 
+// LabelResponse is the response for create label
+type LabelResponse struct {
+	Status struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Data Label `json:"data"`
+}
+
 // LabelListResponse is the response for list labels
 type LabelListResponse struct {
 	Status struct {
@@ -60,17 +86,22 @@ type LabelListResponse struct {
 // MessageSummary represents a message in list view
 // Note: Zoho returns most numeric fields as quoted strings in message list responses
 type MessageSummary struct {
-	MessageID     string `json:"messageId"`
-	ThreadID      string `json:"threadId"`
-	Subject       string `json:"subject"`
-	FromAddress   string `json:"fromAddress"`
-	Sender        string `json:"sender"`
-	ReceivedTime  string `json:"receivedTime"`  // Unix milliseconds (as string)
-	Status        string `json:"status"`
-	HasAttachment string `json:"hasAttachment"` // "0" or "1"
-	FlagID        string `json:"flagid"`
-	Priority      string `json:"priority"`
-	Summary       string `json:"summary"`
+	MessageID     string   `json:"messageId"`
+	ThreadID      string   `json:"threadId"`
+	FolderID      string   `json:"folderId"`
+	Subject       string   `json:"subject"`
+	FromAddress   string   `json:"fromAddress"`
+	Sender        string   `json:"sender"`
+	ToAddress     string   `json:"toAddress"`
+	CcAddress     string   `json:"ccAddress"`
+	ReceivedTime  string   `json:"receivedTime"` // Unix milliseconds (as string)
+	Status        string   `json:"status"`
+	HasAttachment string   `json:"hasAttachment"` // "0" or "1"
+	Size          string   `json:"size"`          // bytes (as string)
+	FlagID        string   `json:"flagid"`
+	Priority      string   `json:"priority"`
+	Summary       string   `json:"summary"`
+	LabelIDs      []string `json:"labelId,omitempty"`
 }
 
 // MessageListResponse is the response for list messages
@@ -152,9 +183,32 @@ type SendEmailRequest struct {
 	BccAddress  string                 `json:"bccAddress,omitempty"`
 	Subject     string                 `json:"subject"`
 	Content     string                 `json:"content"`
-	MailFormat  string                 `json:"mailFormat,omitempty"` // "html" or "plaintext"
-	Action      string                 `json:"action,omitempty"`     // "reply", "replyall", "forward"
+	MailFormat  string                 `json:"mailFormat,omitempty"`  // "html" or "plaintext"
+	TextContent string                 `json:"textContent,omitempty"` // plain-text alternative of an HTML Content; derived from it when empty
+	Action      string                 `json:"action,omitempty"`      // "reply", "replyall", "forward"
+	Mode        string                 `json:"mode,omitempty"`        // "draft" saves instead of sending, "sendDraft" sends a saved draft
 	Attachments []AttachmentReference `json:"attachments,omitempty"`
+
+	// Scheduled send (see Schedule)
+	IsSchedule   bool   `json:"isSchedule,omitempty"`
+	ScheduleType int    `json:"scheduleType,omitempty"` // ScheduleTypeCustom for a given date and time
+	TimeZone     string `json:"timeZone,omitempty"`     // IANA time zone of ScheduleTime
+	ScheduleTime string `json:"scheduleTime,omitempty"` // ScheduleTimeLayout in TimeZone
+}
+
+// Scheduled send parameters
+const (
+	ScheduleTypeCustom = 6
+	ScheduleTimeLayout = "01/02/2006 15:04:05"
+)
+
+// Schedule marks the request to be sent at t, expressed in t's location.
+// t should carry a named location (e.g. from time.LoadLocation or UTC), not time.Local.
+func (r *SendEmailRequest) Schedule(t time.Time) {
+	r.IsSchedule = true
+	r.ScheduleType = ScheduleTypeCustom
+	r.TimeZone = t.Location().String()
+	r.ScheduleTime = t.Format(ScheduleTimeLayout)
 }
 
 // AttachmentReference represents an uploaded attachment reference
@@ -179,6 +233,9 @@ type SendEmailResponse struct {
 		Code        int    `json:"code"`
 		Description string `json:"description"`
 	} `json:"status"`
+	Data struct {
+		MessageID string `json:"messageId"`
+	} `json:"data"`
 }
 
 // Signature represents an email signature
@@ -213,12 +270,63 @@ type SignatureCreateResponse struct {
 
 // VacationReply represents vacation auto-reply settings
 type VacationReply struct {
-	FromDate   string `json:"fromDate"`   // MM/DD/YYYY HH:MM:SS
-	ToDate     string `json:"toDate"`     // MM/DD/YYYY HH:MM:SS
+	FromDate   string `json:"fromDate"`   // VacationDateLayout, in the mailbox's time zone
+	ToDate     string `json:"toDate"`     // VacationDateLayout, in the mailbox's time zone
 	SendingInt int    `json:"sendingInt"` // reply interval in minutes
 	Subject    string `json:"subject"`
 	Content    string `json:"content"`
-	SendTo     string `json:"sendTo"` // "all"/"contacts"/"noncontacts"/"org"/"nonOrgAll"
+	SendTo     string `json:"sendTo"` // one of VacationSendTo
+}
+
+// VacationDateLayout is the format of VacationReply dates
+const VacationDateLayout = "01/02/2006 15:04:05"
+
+// VacationSendTo lists the senders a vacation reply can be limited to
+var VacationSendTo = []string{"all", "contacts", "noncontacts", "org", "nonOrgAll"}
+
+// Validate checks a vacation reply before it is sent to Zoho
+func (v *VacationReply) Validate() error {
+	if !slices.Contains(VacationSendTo, v.SendTo) {
+		return fmt.Errorf("invalid sendTo %q (use one of %s)", v.SendTo, strings.Join(VacationSendTo, ", "))
+	}
+	if v.SendingInt <= 0 {
+		return fmt.Errorf("invalid sendingInt %d (minutes between replies to the same sender must be positive)", v.SendingInt)
+	}
+	if strings.TrimSpace(v.Subject) == "" || strings.TrimSpace(v.Content) == "" {
+		return errors.New("subject and content are required")
+	}
+	from, to, err := v.Period(time.UTC)
+	if err != nil {
+		return err
+	}
+	if !to.After(from) {
+		return fmt.Errorf("toDate %s is not after fromDate %s", v.ToDate, v.FromDate)
+	}
+	return nil
+}
+
+// Period returns the reply's start and end as times in loc, the mailbox's time zone
+func (v *VacationReply) Period(loc *time.Location) (from, to time.Time, err error) {
+	if from, err = time.ParseInLocation(VacationDateLayout, v.FromDate, loc); err != nil {
+		return from, to, fmt.Errorf("invalid fromDate %q (expected MM/DD/YYYY HH:MM:SS)", v.FromDate)
+	}
+	if to, err = time.ParseInLocation(VacationDateLayout, v.ToDate, loc); err != nil {
+		return from, to, fmt.Errorf("invalid toDate %q (expected MM/DD/YYYY HH:MM:SS)", v.ToDate)
+	}
+	return from, to, nil
+}
+
+// ParseVacationReply decodes AccountDetails.VacationResponse; it returns nil
+// when no vacation reply is configured
+func ParseVacationReply(raw json.RawMessage) (*VacationReply, error) {
+	if len(raw) == 0 || string(raw) == "null" || string(raw) == "{}" {
+		return nil, nil
+	}
+	var v VacationReply
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("parse vacation reply: %w", err)
+	}
+	return &v, nil
 }
 
 // AccountDetails represents account details including vacation and forwarding settings
@@ -227,6 +335,7 @@ type AccountDetails struct {
 	DisplayName         string          `json:"displayName"`
 	PrimaryEmailAddress string          `json:"primaryEmailAddress"`
 	EmailAddress        []EmailAddress  `json:"emailAddress"`
+	TimeZone            string          `json:"timeZone,omitempty"` // IANA name, e.g. Europe/Berlin
 	VacationResponse    json.RawMessage `json:"vacationResponse,omitempty"`
 	ForwardDetails      json.RawMessage `json:"forwardDetails,omitempty"`
 }
@@ -337,3 +446,53 @@ type DeliveryLogListResponse struct {
 	} `json:"status"`
 	Data []DeliveryLog `json:"data"`
 }
+
+// Filter action types that take a folder or label ID as their value
+const (
+	FilterActionMove  = "moveToFolder"
+	FilterActionCopy  = "copyToFolder"
+	FilterActionLabel = "addLabel"
+)
+
+// MailFilter is an incoming mail filter, run by Zoho as messages are delivered.
+// Filters run in ascending FilterOrder.
+type MailFilter struct {
+	FilterID      string            `json:"filterId,omitempty"`
+	FilterName    string            `json:"filterName"`
+	FilterOrder   int               `json:"filterOrder,omitempty"`
+	Enabled       bool              `json:"isEnabled"`
+	ConditionType string            `json:"conditionType"` // "all" or "any" of the conditions
+	Conditions    []FilterCondition `json:"conditions"`
+	Actions       []FilterAction    `json:"actions"`
+}
+
+// FilterCondition is one test a filter makes on an incoming message
+type FilterCondition struct {
+	Field      string `json:"field"`      // sender, recipient, cc, subject, content, ...
+	Comparison string `json:"comparison"` // contains, notContains, is, isNot, startsWith, endsWith
+	Value      string `json:"value"`
+}
+
+// FilterAction is one thing a filter does with a matching message
+type FilterAction struct {
+	ActionType  string `json:"actionType"`            // moveToFolder, addLabel, markAsRead, forward, ...
+	ActionValue string `json:"actionValue,omitempty"` // folder or label ID, address, ...
+}
+
+// MailFilterListResponse is the response for list filters
+type MailFilterListResponse struct {
+	Status struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Data []MailFilter `json:"data"`
+}
+
+// MailFilterResponse is the response for get and create filter
+type MailFilterResponse struct {
+	Status struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Data MailFilter `json:"data"`
+}
@@ -17,6 +17,10 @@ func NewRateLimiter() *rate.Limiter {
 	return rate.NewLimiter(rate.Every(time.Minute/25), 5)
 }
 
+// ClientRateLimiter creates the rate limiter of each new Client. Tests that
+// talk to an in-process fake server replace it with an unlimited limiter.
+var ClientRateLimiter = NewRateLimiter
+
 // RateLimitTransport wraps an http.RoundTripper with rate limiting and 429 retry logic.
 type RateLimitTransport struct {
 	Base    http.RoundTripper
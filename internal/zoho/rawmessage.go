@@ -0,0 +1,195 @@
+package zoho
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// RawMessage is an RFC 5322 message mapped onto a send request.
+// Parts still have to be uploaded and referenced from Request before sending.
+type RawMessage struct {
+	Request  SendEmailRequest
+	Parts    []RawPart
+	Warnings []string // problems that did not stop parsing, such as text in an unknown charset
+}
+
+// RawPart is an attachment or inline image embedded in a raw message
+type RawPart struct {
+	FileName    string
+	ContentType string
+	ContentID   string // without angle brackets; set for inline parts
+	Inline      bool
+	Data        []byte
+}
+
+// ParseRawMessage parses an RFC 5322 message, such as an .eml file.
+// Addresses, subject and body are mapped onto a SendEmailRequest, with text
+// converted to UTF-8 from each part's charset; for multipart/alternative
+// bodies the HTML version is the content and the plain-text version its
+// TextContent. Text in a charset that cannot be decoded is kept as it is,
+// with a warning. Attachments and inline images are returned as parts.
+func ParseRawMessage(r io.Reader) (*RawMessage, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse message: %w", err)
+	}
+
+	raw := &RawMessage{}
+	req := &raw.Request
+
+	dec := &mime.WordDecoder{CharsetReader: charsetReader}
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	req.Subject = subject
+
+	for _, h := range []struct {
+		name string
+		dst  *string
+	}{
+		{"From", &req.FromAddress},
+		{"To", &req.ToAddress},
+		{"Cc", &req.CcAddress},
+		{"Bcc", &req.BccAddress},
+	} {
+		if msg.Header.Get(h.name) == "" {
+			continue
+		}
+		addrs, err := msg.Header.AddressList(h.name)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s header: %w", h.name, err)
+		}
+		list := make([]string, len(addrs))
+		for i, a := range addrs {
+			list[i] = a.Address
+		}
+		*h.dst = strings.Join(list, ",")
+	}
+
+	var text, html string
+	err = walkPart(textproto.MIMEHeader(msg.Header), msg.Body, func(h textproto.MIMEHeader, body []byte) error {
+		mediaType, params, _ := mime.ParseMediaType(h.Get("Content-Type"))
+		if mediaType == "" {
+			mediaType = "text/plain"
+		}
+		disposition, dparams, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+		fileName := dparams["filename"]
+		if fileName == "" {
+			fileName = params["name"]
+		}
+		if decoded, err := dec.DecodeHeader(fileName); err == nil {
+			fileName = decoded
+		}
+		contentID := strings.Trim(h.Get("Content-ID"), "<> ")
+
+		// Body text is the first text part that is not an attachment
+		if disposition != "attachment" && fileName == "" {
+			var dst *string
+			switch {
+			case mediaType == "text/html" && html == "":
+				dst = &html
+			case mediaType == "text/plain" && text == "":
+				dst = &text
+			}
+			if dst != nil {
+				decoded, err := decodeCharset(params["charset"], body)
+				if err != nil {
+					raw.Warnings = append(raw.Warnings, fmt.Sprintf("%s part left undecoded: %v", mediaType, err))
+					decoded = string(body)
+				}
+				*dst = decoded
+				return nil
+			}
+		}
+
+		if fileName == "" {
+			fileName = defaultPartName(mediaType, len(raw.Parts))
+		}
+		raw.Parts = append(raw.Parts, RawPart{
+			FileName:    fileName,
+			ContentType: mediaType,
+			ContentID:   contentID,
+			Inline:      disposition != "attachment" && contentID != "",
+			Data:        body,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if html != "" {
+		req.Content = html
+		req.TextContent = text
+		req.MailFormat = "html"
+	} else {
+		req.Content = text
+		req.MailFormat = "plaintext"
+	}
+
+	return raw, nil
+}
+
+// walkPart calls leaf for every non-multipart part below body, with the
+// transfer encoding already decoded
+func walkPart(h textproto.MIMEHeader, body io.Reader, leaf func(textproto.MIMEHeader, []byte) error) error {
+	mediaType, params, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read MIME part: %w", err)
+			}
+			if err := walkPart(part.Header, part, leaf); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := decodeTransfer(h.Get("Content-Transfer-Encoding"), body)
+	if err != nil {
+		return err
+	}
+	return leaf(h, data)
+}
+
+// decodeTransfer decodes a part body according to its Content-Transfer-Encoding
+func decodeTransfer(encoding string, body io.Reader) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, body))
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 part: %w", err)
+		}
+		return data, nil
+	case "quoted-printable":
+		data, err := io.ReadAll(quotedprintable.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("decode quoted-printable part: %w", err)
+		}
+		return data, nil
+	default:
+		return io.ReadAll(body)
+	}
+}
+
+// defaultPartName names an unnamed part from its media type
+func defaultPartName(mediaType string, index int) string {
+	ext := ".bin"
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		ext = exts[0]
+	}
+	return fmt.Sprintf("part%d%s", index+1, ext)
+}
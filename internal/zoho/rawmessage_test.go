@@ -0,0 +1,149 @@
+package zoho_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+const sampleEML = "From: Monitor <monitor@example.com>\r\n" +
+	"To: Ops <ops@example.com>, oncall@example.com\r\n" +
+	"Cc: lead@example.com\r\n" +
+	"Subject: =?utf-8?q?Disk_usage_=E2=9A=A0?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/related; boundary=related\r\n" +
+	"\r\n" +
+	"--related\r\n" +
+	"Content-Type: multipart/alternative; boundary=alt\r\n" +
+	"\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Disk at 91%\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"<p>Disk at 91%</p><img src=3D\"cid:graph@monitor\">\r\n" +
+	"--alt--\r\n" +
+	"--related\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-ID: <graph@monitor>\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0K\r\n" +
+	"GgoA\r\n" +
+	"--related--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/csv; name=\"usage.csv\"\r\n" +
+	"Content-Disposition: attachment; filename=\"usage.csv\"\r\n" +
+	"\r\n" +
+	"host,pct\r\n" +
+	"--outer--\r\n"
+
+func TestParseRawMessage(t *testing.T) {
+	raw, err := zoho.ParseRawMessage(strings.NewReader(sampleEML))
+	require.NoError(t, err)
+
+	req := raw.Request
+	assert.Equal(t, "monitor@example.com", req.FromAddress)
+	assert.Equal(t, "ops@example.com,oncall@example.com", req.ToAddress)
+	assert.Equal(t, "lead@example.com", req.CcAddress)
+	assert.Equal(t, "Disk usage ⚠", req.Subject)
+	assert.Equal(t, "html", req.MailFormat)
+	assert.Equal(t, `<p>Disk at 91%</p><img src="cid:graph@monitor">`, req.Content)
+	assert.Equal(t, "Disk at 91%", req.TextContent)
+
+	require.Len(t, raw.Parts, 2)
+	assert.True(t, raw.Parts[0].Inline)
+	assert.Equal(t, "graph@monitor", raw.Parts[0].ContentID)
+	assert.Equal(t, "image/png", raw.Parts[0].ContentType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n\x00"), raw.Parts[0].Data)
+
+	assert.False(t, raw.Parts[1].Inline)
+	assert.Equal(t, "usage.csv", raw.Parts[1].FileName)
+	assert.Equal(t, "host,pct", string(raw.Parts[1].Data))
+}
+
+func TestParseRawMessagePlainText(t *testing.T) {
+	raw, err := zoho.ParseRawMessage(strings.NewReader("To: a@example.com\r\nSubject: Hi\r\n\r\nHello\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", raw.Request.MailFormat)
+	assert.Equal(t, "Hello\r\n", raw.Request.Content)
+	assert.Empty(t, raw.Parts)
+}
+
+func TestParseRawMessageCharsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		body    string
+		subject string
+		want    string
+		wantSub string
+	}{
+		{
+			name:    "iso-8859-1",
+			header:  "text/plain; charset=ISO-8859-1",
+			body:    "Gr\xfc\xdfe aus K\xf6ln",
+			subject: "=?iso-8859-1?q?Gr=FC=DFe?=",
+			want:    "Grüße aus Köln",
+			wantSub: "Grüße",
+		},
+		{
+			name:    "windows-1252",
+			header:  "text/plain; charset=windows-1252",
+			body:    "\x93Quoted\x94 \x80 5\x97cheap",
+			subject: "=?windows-1252?q?=93Quoted=94?=",
+			want:    "“Quoted” € 5—cheap",
+			wantSub: "“Quoted”",
+		},
+		{
+			name:    "koi8-r",
+			header:  "text/plain; charset=KOI8-R",
+			body:    "\xf0\xd2\xc9\xd7\xc5\xd4",
+			subject: "=?koi8-r?b?8NLJ18XU?=",
+			want:    "Привет",
+			wantSub: "Привет",
+		},
+		{
+			name:    "shift_jis",
+			header:  "text/plain; charset=Shift_JIS",
+			body:    "\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd",
+			subject: "=?iso-8859-2?q?=A9koda?=",
+			want:    "こんにちは",
+			wantSub: "Škoda",
+		},
+		{
+			name:    "utf-8",
+			header:  "text/plain; charset=utf-8",
+			body:    "Grüße",
+			subject: "Hi",
+			want:    "Grüße",
+			wantSub: "Hi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eml := "To: a@example.com\r\nSubject: " + tt.subject + "\r\nContent-Type: " + tt.header + "\r\n\r\n" + tt.body
+			raw, err := zoho.ParseRawMessage(strings.NewReader(eml))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, raw.Request.Content)
+			assert.Equal(t, tt.wantSub, raw.Request.Subject)
+		})
+	}
+
+	// Unknown charsets pass through with a warning rather than failing the message
+	raw, err := zoho.ParseRawMessage(strings.NewReader("To: a@example.com\r\nContent-Type: text/plain; charset=x-klingon\r\n\r\nHi"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", raw.Request.Content)
+	require.Len(t, raw.Warnings, 1)
+	assert.Contains(t, raw.Warnings[0], `unsupported charset "x-klingon"`)
+}
@@ -0,0 +1,457 @@
+package zoho
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchExpr is a node of a parsed search query: a *SearchTerm, *SearchAnd,
+// *SearchOr or *SearchNot. String renders it back in query syntax.
+type SearchExpr interface {
+	String() string
+}
+
+// SearchTerm is a single criterion: free text, or field:value
+type SearchTerm struct {
+	Field string // "" for free text, otherwise one of searchFields
+	Value string
+	Pos   int // byte offset of the term in the query
+
+	day  time.Time // after:, before:
+	size int64     // larger:, smaller:
+	id   string    // in:, label: once resolved by CompileSearch
+}
+
+// SearchAnd matches messages matching every term
+type SearchAnd struct{ Terms []SearchExpr }
+
+// SearchOr matches messages matching any term
+type SearchOr struct{ Terms []SearchExpr }
+
+// SearchNot matches messages not matching its term
+type SearchNot struct{ Term SearchExpr }
+
+// searchFields lists the field: operators; other words with a colon are free text
+var searchFields = map[string]bool{
+	"from": true, "to": true, "subject": true,
+	"after": true, "before": true,
+	"has": true, "is": true,
+	"in": true, "label": true,
+	"larger": true, "smaller": true,
+}
+
+// SearchSyntaxError reports where a search query stopped making sense
+type SearchSyntaxError struct {
+	Query string
+	Pos   int // byte offset of the error
+	Msg   string
+}
+
+func (e *SearchSyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.column())
+}
+
+// column returns the 1-based character column of the error
+func (e *SearchSyntaxError) column() int {
+	return utf8.RuneCountInString(e.Query[:e.Pos]) + 1
+}
+
+// Pointer returns the query with a caret under the error position
+func (e *SearchSyntaxError) Pointer() string {
+	return e.Query + "\n" + strings.Repeat(" ", e.column()-1) + "^"
+}
+
+// ParseSearch parses a Gmail-like search query:
+//
+//	from:alice OR from:bob -subject:digest in:Support larger:5M is:flagged
+//
+// Terms are free text, "quoted phrases" or field:value pairs (from, to,
+// subject, after, before, has:attachment, is:unread|read|flagged, in:folder,
+// label:name, larger:size, smaller:size). Adjacent terms must all match; OR
+// binds tighter than that, so "a b OR c" means a AND (b OR c). A leading -
+// or NOT negates a term, and parentheses group. A blank query returns nil.
+func ParseSearch(query string) (SearchExpr, error) {
+	p := &searchParser{query: query}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokEOF {
+		return nil, nil
+	}
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokTerm
+	tokLParen
+	tokRParen
+	tokOr
+	tokAnd
+	tokNot
+)
+
+type searchToken struct {
+	kind tokenKind
+	pos  int
+	term *SearchTerm
+	text string
+}
+
+func (t searchToken) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+type searchParser struct {
+	query  string
+	tokens []searchToken
+	next   int
+}
+
+func (p *searchParser) errorf(pos int, format string, args ...any) error {
+	return &SearchSyntaxError{Query: p.query, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.next]
+}
+
+func (p *searchParser) advance() searchToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+// lex splits the query into tokens
+func (p *searchParser) lex() error {
+	q := p.query
+	for i := 0; i < len(q); {
+		r, size := utf8.DecodeRuneInString(q[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			p.tokens = append(p.tokens, searchToken{kind: tokLParen, pos: i, text: "("})
+			i++
+		case r == ')':
+			p.tokens = append(p.tokens, searchToken{kind: tokRParen, pos: i, text: ")"})
+			i++
+		case r == '-':
+			// A leading dash negates the term it is attached to
+			if i+1 >= len(q) || strings.ContainsRune(" \t\r\n)", rune(q[i+1])) {
+				return p.errorf(i, "nothing to negate after -")
+			}
+			p.tokens = append(p.tokens, searchToken{kind: tokNot, pos: i, text: "-"})
+			i++
+		case r == '"':
+			phrase, end, err := p.quoted(i)
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, searchToken{kind: tokTerm, pos: i, text: q[i:end], term: &SearchTerm{Value: phrase, Pos: i}})
+			i = end
+		default:
+			tok, end, err := p.word(i)
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, tok)
+			i = end
+		}
+	}
+	p.tokens = append(p.tokens, searchToken{kind: tokEOF, pos: len(q)})
+	return nil
+}
+
+// quoted reads the "phrase" starting at i, returning it and the offset after it
+func (p *searchParser) quoted(i int) (string, int, error) {
+	end := strings.IndexByte(p.query[i+1:], '"')
+	if end < 0 {
+		return "", 0, p.errorf(i, "unterminated quote")
+	}
+	phrase := p.query[i+1 : i+1+end]
+	if strings.TrimSpace(phrase) == "" {
+		return "", 0, p.errorf(i, "empty quotes")
+	}
+	return phrase, i + end + 2, nil
+}
+
+// word reads an operator keyword or a term starting at i
+func (p *searchParser) word(i int) (searchToken, int, error) {
+	q := p.query
+	end := i
+	for end < len(q) {
+		r, size := utf8.DecodeRuneInString(q[end:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			break
+		}
+		end += size
+	}
+	text := q[i:end]
+
+	switch text {
+	case "OR":
+		return searchToken{kind: tokOr, pos: i, text: text}, end, nil
+	case "AND":
+		return searchToken{kind: tokAnd, pos: i, text: text}, end, nil
+	case "NOT":
+		return searchToken{kind: tokNot, pos: i, text: text}, end, nil
+	}
+
+	field, value, hasField := strings.Cut(text, ":")
+	field = strings.ToLower(field)
+	if !hasField || !searchFields[field] {
+		if end < len(q) && q[end] == '"' {
+			return searchToken{}, 0, p.errorf(end, "unexpected quote inside a word")
+		}
+		// Not an operator, e.g. a time of day or a URL
+		return searchToken{kind: tokTerm, pos: i, text: text, term: &SearchTerm{Value: text, Pos: i}}, end, nil
+	}
+
+	valuePos := i + len(field) + 1
+	if value == "" && end < len(q) {
+		switch q[end] {
+		case '"':
+			phrase, after, err := p.quoted(end)
+			if err != nil {
+				return searchToken{}, 0, err
+			}
+			value, end = phrase, after
+		case '(':
+			return searchToken{}, 0, p.errorf(end, "grouped values are not supported; write (%s:a OR %s:b)", field, field)
+		}
+	}
+	if value == "" {
+		return searchToken{}, 0, p.errorf(valuePos, "missing value after %s:", field)
+	}
+
+	term := &SearchTerm{Field: field, Value: value, Pos: i}
+	if err := p.check(term, valuePos); err != nil {
+		return searchToken{}, 0, err
+	}
+	return searchToken{kind: tokTerm, pos: i, text: q[i:end], term: term}, end, nil
+}
+
+// check validates a field's value and stores its parsed form
+func (p *searchParser) check(t *SearchTerm, valuePos int) error {
+	switch t.Field {
+	case "after", "before":
+		day, err := parseSearchDay(t.Value)
+		if err != nil {
+			return p.errorf(valuePos, "invalid date %q (use YYYY-MM-DD)", t.Value)
+		}
+		t.day = day
+	case "has":
+		t.Value = strings.ToLower(t.Value)
+		if t.Value != "attachment" {
+			return p.errorf(valuePos, "unknown has:%s (use has:attachment)", t.Value)
+		}
+	case "is":
+		t.Value = strings.ToLower(t.Value)
+		if t.Value != "unread" && t.Value != "read" && t.Value != "flagged" {
+			return p.errorf(valuePos, "unknown is:%s (use is:unread, is:read or is:flagged)", t.Value)
+		}
+	case "larger", "smaller":
+		size, err := parseSearchSize(t.Value)
+		if err != nil {
+			return p.errorf(valuePos, "%v (use e.g. 500K, 5M or 1G)", err)
+		}
+		t.size = size
+	}
+	return nil
+}
+
+// parseSearchDay parses a YYYY-MM-DD or YYYY/MM/DD date in UTC
+func parseSearchDay(s string) (time.Time, error) {
+	if day, err := time.Parse("2006/01/02", s); err == nil {
+		return day, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// parseSearchSize parses a byte count with an optional K, M or G suffix (powers of 1024)
+func parseSearchSize(s string) (int64, error) {
+	num := strings.TrimSuffix(strings.ToUpper(s), "B")
+	shift := 0
+	switch {
+	case strings.HasSuffix(num, "K"):
+		shift = 10
+	case strings.HasSuffix(num, "M"):
+		shift = 20
+	case strings.HasSuffix(num, "G"):
+		shift = 30
+	}
+	if shift > 0 {
+		num = num[:len(num)-1]
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	// The negated comparisons also reject NaN, and infinity is out of range
+	if err != nil || !(n >= 0) {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	n *= float64(int64(1) << shift)
+	if !(n < math.MaxInt64) {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+	return int64(n), nil
+}
+
+// parseAnd parses terms that must all match, up to a closing parenthesis
+func (p *searchParser) parseAnd() (SearchExpr, error) {
+	var terms []SearchExpr
+	for {
+		tok := p.peek()
+		switch tok.kind {
+		case tokEOF, tokRParen:
+			if len(terms) == 1 {
+				return terms[0], nil
+			}
+			return &SearchAnd{Terms: terms}, nil
+		case tokAnd:
+			if len(terms) == 0 {
+				return nil, p.errorf(tok.pos, "AND needs a term on both sides")
+			}
+			p.advance()
+			if next := p.peek(); next.kind != tokTerm && next.kind != tokLParen && next.kind != tokNot {
+				return nil, p.errorf(tok.pos, "AND needs a term on both sides")
+			}
+			continue
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, expr)
+	}
+}
+
+// parseOr parses alternatives joined by OR
+func (p *searchParser) parseOr() (SearchExpr, error) {
+	if tok := p.peek(); tok.kind == tokOr {
+		return nil, p.errorf(tok.pos, "OR needs a term on both sides")
+	}
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []SearchExpr{first}
+	for p.peek().kind == tokOr {
+		or := p.advance()
+		if next := p.peek(); next.kind != tokTerm && next.kind != tokLParen && next.kind != tokNot {
+			return nil, p.errorf(or.pos, "OR needs a term on both sides")
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, expr)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &SearchOr{Terms: terms}, nil
+}
+
+// parseUnary parses an optionally negated term or group
+func (p *searchParser) parseUnary() (SearchExpr, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokNot:
+		if next := p.peek(); next.kind != tokTerm && next.kind != tokLParen && next.kind != tokNot {
+			return nil, p.errorf(tok.pos, "nothing to negate after %s", tok.text)
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &SearchNot{Term: expr}, nil
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, p.errorf(tok.pos, "empty parentheses")
+		}
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf(tok.pos, "unclosed parenthesis")
+		}
+		p.advance()
+		return expr, nil
+	case tokTerm:
+		return tok.term, nil
+	default:
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+}
+
+func (t *SearchTerm) String() string {
+	value := t.Value
+	if value == "" || strings.ContainsAny(value, " \t()\"") || (t.Field == "" && t.isKeyword()) {
+		value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	if t.Field == "" {
+		return value
+	}
+	return t.Field + ":" + value
+}
+
+// isKeyword reports whether free text would be read back as an operator
+func (t *SearchTerm) isKeyword() bool {
+	switch t.Value {
+	case "OR", "AND", "NOT":
+		return true
+	}
+	field, _, found := strings.Cut(t.Value, ":")
+	return strings.HasPrefix(t.Value, "-") || (found && searchFields[strings.ToLower(field)])
+}
+
+func (a *SearchAnd) String() string {
+	parts := make([]string, len(a.Terms))
+	for i, term := range a.Terms {
+		parts[i] = term.String()
+		if _, ok := term.(*SearchAnd); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (o *SearchOr) String() string {
+	parts := make([]string, len(o.Terms))
+	for i, term := range o.Terms {
+		parts[i] = term.String()
+		switch term.(type) {
+		case *SearchAnd, *SearchOr:
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " OR ")
+}
+
+func (n *SearchNot) String() string {
+	switch n.Term.(type) {
+	case *SearchAnd, *SearchOr:
+		return "-(" + n.Term.String() + ")"
+	}
+	return "-" + n.Term.String()
+}
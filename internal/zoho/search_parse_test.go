@@ -0,0 +1,184 @@
+package zoho
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearch(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string // canonical form
+	}{
+		{"invoice", "invoice"},
+		{"from:a OR from:b -subject:digest in:Support larger:5M is:flagged",
+			"from:a OR from:b -subject:digest in:Support larger:5M is:flagged"},
+		{"a b OR c", "a b OR c"},
+		{"(a b) OR c", "(a b) OR c"},
+		{"NOT (a OR b)", "-(a OR b)"},
+		{"a AND b", "a b"},
+		{`subject:"weekly digest" "exact phrase"`, `subject:"weekly digest" "exact phrase"`},
+		{"FROM:Alice HAS:Attachment", "from:Alice has:attachment"},
+		{"meet at 10:30 https://example.com", "meet at 10:30 https://example.com"},
+		{"e-mail --flag", "e-mail --flag"},
+		{`"from:x"`, `"from:x"`},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := ParseSearch(tt.query)
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, expr)
+				return
+			}
+			assert.Equal(t, tt.expected, expr.String())
+
+			// The canonical form parses back to itself
+			again, err := ParseSearch(expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, expr.String(), again.String())
+		})
+	}
+}
+
+func TestParseSearchPrecedence(t *testing.T) {
+	expr, err := ParseSearch("a b OR c -d")
+	require.NoError(t, err)
+	and, ok := expr.(*SearchAnd)
+	require.True(t, ok)
+	require.Len(t, and.Terms, 3)
+	assert.IsType(t, &SearchTerm{}, and.Terms[0])
+	assert.IsType(t, &SearchOr{}, and.Terms[1])
+	assert.IsType(t, &SearchNot{}, and.Terms[2])
+}
+
+func TestParseSearchErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		msg   string
+		col   int
+	}{
+		{"from:", "missing value after from:", 6},
+		{"a OR", "OR needs a term on both sides", 3},
+		{"OR a", "OR needs a term on both sides", 1},
+		{"a AND", "AND needs a term on both sides", 3},
+		{"(a b", "unclosed parenthesis", 1},
+		{"a b)", `unexpected ")"`, 4},
+		{"()", "empty parentheses", 1},
+		{"a - b", "nothing to negate after -", 3},
+		{"a NOT", "nothing to negate after NOT", 3},
+		{`subject:"weekly`, "unterminated quote", 9},
+		{"after:yesterday", `invalid date "yesterday" (use YYYY-MM-DD)`, 7},
+		{"larger:big", `invalid size "big" (use e.g. 500K, 5M or 1G)`, 8},
+		{"larger:nan", `invalid size "nan" (use e.g. 500K, 5M or 1G)`, 8},
+		{"larger:inf", `size "inf" out of range (use e.g. 500K, 5M or 1G)`, 8},
+		{"smaller:1e30", `size "1e30" out of range (use e.g. 500K, 5M or 1G)`, 9},
+		{"larger:8589934592G", `size "8589934592G" out of range (use e.g. 500K, 5M or 1G)`, 8},
+		{"is:important", "unknown is:important (use is:unread, is:read or is:flagged)", 4},
+		{"has:pdf", "unknown has:pdf (use has:attachment)", 5},
+		{"from:(a OR b)", "grouped values are not supported; write (from:a OR from:b)", 6},
+		{"café from:", "missing value after from:", 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseSearch(tt.query)
+			var syntaxErr *SearchSyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+			assert.Equal(t, tt.msg, syntaxErr.Msg)
+			assert.Equal(t, tt.col, syntaxErr.column())
+		})
+	}
+
+	_, err := ParseSearch("a OR")
+	assert.EqualError(t, err, "OR needs a term on both sides at column 3")
+	assert.Equal(t, "a OR\n  ^", err.(*SearchSyntaxError).Pointer())
+}
+
+func TestCompileSearch(t *testing.T) {
+	resolve := func(field, name string) (string, error) {
+		return field + "-" + name, nil
+	}
+	tests := []struct {
+		name   string
+		query  string
+		keys   []string
+		folder string
+		filter string
+	}{
+		{"all server side", "from:alice subject:report has:attachment is:unread",
+			[]string{"from:alice subject:report has:attachment is:unread"}, "", ""},
+		{"phrase and dates", `"quarterly report" after:2024-06-01 before:2024/07/01`,
+			[]string{`"quarterly report" after:2024/06/01 before:2024/07/01`}, "", ""},
+		{"or fans out", "from:a OR from:b invoice",
+			[]string{"invoice from:a", "invoice from:b"}, "", ""},
+		{"request example", "from:a OR from:b -subject:digest in:Support larger:5M is:flagged",
+			[]string{"from:a", "from:b"}, "", "-subject:digest in:Support larger:5M is:flagged"},
+		{"or with client-side branch", "invoice (from:a OR label:VIP)",
+			[]string{"invoice"}, "", "from:a OR label:VIP"},
+		{"only folder scope", "in:Support is:flagged",
+			nil, "in-Support", "is:flagged"},
+		{"no scope lists the inbox", "-from:a is:flagged",
+			nil, "in-Inbox", "-from:a is:flagged"},
+		{"wide or lists the inbox", "from:a OR from:b OR from:c OR from:d OR from:e OR from:f",
+			nil, "in-Inbox", "from:a OR from:b OR from:c OR from:d OR from:e OR from:f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseSearch(tt.query)
+			require.NoError(t, err)
+			plan, err := CompileSearch(expr, resolve)
+			require.NoError(t, err)
+			assert.Equal(t, tt.keys, plan.Keys)
+			assert.Equal(t, tt.folder, plan.Folder)
+			if tt.filter == "" {
+				assert.Nil(t, plan.Filter)
+			} else {
+				require.NotNil(t, plan.Filter)
+				assert.Equal(t, tt.filter, plan.Filter.String())
+			}
+		})
+	}
+}
+
+func TestSearchPlanMatch(t *testing.T) {
+	msg := &MessageSummary{
+		FolderID:      "in-Support",
+		Subject:       "Weekly digest",
+		FromAddress:   "alerts@example.com",
+		ToAddress:     "ops@example.com",
+		ReceivedTime:  "1718000000000", // 2024-06-10
+		Status:        "1",
+		HasAttachment: "0",
+		Size:          "6291456", // 6 MB
+		FlagID:        FlagImportant,
+		LabelIDs:      []string{"label-Ops"},
+	}
+	resolve := func(field, name string) (string, error) {
+		return field + "-" + name, nil
+	}
+	tests := []struct {
+		query string
+		match bool
+	}{
+		{"in:Support larger:5M is:flagged", true},
+		{"in:Support smaller:5M", false},
+		{"in:Support -subject:digest", false},
+		{"in:Support label:Ops is:read -to:ceo", true},
+		{"in:Support (smaller:1K OR label:Other)", false},
+		{"in:Support -before:2024-06-01 -after:2024-06-11", true},
+		{"in:Support -(from:alerts OR label:Other)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := ParseSearch(tt.query)
+			require.NoError(t, err)
+			plan, err := CompileSearch(expr, resolve)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, plan.Match(msg))
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package zoho
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchBranches caps how many searches one OR may fan out into
+const maxSearchBranches = 5
+
+// DefaultSearchFolder is listed for queries with nothing Zoho can search for
+// and no in: scope
+const DefaultSearchFolder = "Inbox"
+
+// SearchPlan is a compiled query: the Zoho searches to run and the
+// client-side filter their results must pass
+type SearchPlan struct {
+	// Keys are Zoho search keys; messages matching any of them are candidates
+	Keys []string
+
+	// Folder is a folder ID to list instead, when no part of the query can be searched for
+	Folder string
+
+	// Filter holds what Zoho cannot evaluate; nil when the searches are exact
+	Filter SearchExpr
+}
+
+// SearchResolver maps the name in an in: term (field "in") or label: term
+// (field "label") to the folder or label ID
+type SearchResolver func(field, name string) (string, error)
+
+// CompileSearch turns a parsed query into a SearchPlan. Top-level terms Zoho
+// can search for (free text, from:, to:, subject:, after:, before:,
+// has:attachment, is:unread) form the search key; an OR of such terms fans
+// out into one search per alternative. Everything else (negation, other OR
+// groups, in:, label:, larger:, smaller:, is:read, is:flagged) becomes the
+// client-side filter. A query with nothing to search for lists its in:
+// folder, or DefaultSearchFolder, and filters that.
+func CompileSearch(expr SearchExpr, resolve SearchResolver) (*SearchPlan, error) {
+	if expr == nil {
+		return nil, fmt.Errorf("empty search query")
+	}
+	if err := resolveTerms(expr, resolve); err != nil {
+		return nil, err
+	}
+
+	conjuncts := []SearchExpr{expr}
+	if and, ok := expr.(*SearchAnd); ok {
+		conjuncts = flattenAnd(and)
+	}
+
+	var server []*SearchTerm
+	var rest []SearchExpr
+	for _, c := range conjuncts {
+		if t, ok := c.(*SearchTerm); ok && t.serverSide() {
+			server = append(server, t)
+		} else {
+			rest = append(rest, c)
+		}
+	}
+
+	plan := &SearchPlan{}
+
+	// An OR whose alternatives Zoho can each search for runs as several searches
+	for i, c := range rest {
+		branches, ok := serverBranches(c)
+		if !ok {
+			continue
+		}
+		for _, branch := range branches {
+			plan.Keys = append(plan.Keys, searchKey(append(slices.Clone(server), branch...)))
+		}
+		rest = slices.Delete(rest, i, i+1)
+		break
+	}
+	if len(plan.Keys) == 0 && len(server) > 0 {
+		plan.Keys = []string{searchKey(server)}
+	}
+
+	// Nothing to search for: list the folder the query is scoped to instead
+	if len(plan.Keys) == 0 {
+		for i, c := range rest {
+			if t, ok := c.(*SearchTerm); ok && t.Field == "in" {
+				plan.Folder = t.id
+				rest = slices.Delete(rest, i, i+1)
+				break
+			}
+		}
+	}
+	if len(plan.Keys) == 0 && plan.Folder == "" {
+		if resolve == nil {
+			return nil, fmt.Errorf("cannot resolve in:%s", DefaultSearchFolder)
+		}
+		id, err := resolve("in", DefaultSearchFolder)
+		if err != nil {
+			return nil, err
+		}
+		plan.Folder = id
+	}
+
+	switch len(rest) {
+	case 0:
+	case 1:
+		plan.Filter = rest[0]
+	default:
+		plan.Filter = &SearchAnd{Terms: rest}
+	}
+	return plan, nil
+}
+
+// Match reports whether a message passes the plan's client-side filter.
+// Free text is matched against the subject, sender and summary, since
+// search results carry no full body.
+func (p *SearchPlan) Match(msg *MessageSummary) bool {
+	return p.Filter == nil || matchSearchExpr(p.Filter, msg)
+}
+
+// ResolveSearch looks up the folder and label IDs of a query's in: and
+// label: terms, for queries evaluated with MatchSearch
+func ResolveSearch(expr SearchExpr, resolve SearchResolver) error {
+	return resolveTerms(expr, resolve)
+}
+
+// MatchSearch evaluates a whole resolved query against a message summary,
+// without any server-side search. Free text is matched as in Match.
+func MatchSearch(expr SearchExpr, msg *MessageSummary) bool {
+	return matchSearchExpr(expr, msg)
+}
+
+// resolveTerms looks up the folder and label IDs of in: and label: terms
+func resolveTerms(expr SearchExpr, resolve SearchResolver) error {
+	switch e := expr.(type) {
+	case *SearchTerm:
+		if e.Field != "in" && e.Field != "label" {
+			return nil
+		}
+		if resolve == nil {
+			return fmt.Errorf("cannot resolve %s", e)
+		}
+		id, err := resolve(e.Field, e.Value)
+		if err != nil {
+			return err
+		}
+		e.id = id
+	case *SearchAnd:
+		for _, t := range e.Terms {
+			if err := resolveTerms(t, resolve); err != nil {
+				return err
+			}
+		}
+	case *SearchOr:
+		for _, t := range e.Terms {
+			if err := resolveTerms(t, resolve); err != nil {
+				return err
+			}
+		}
+	case *SearchNot:
+		return resolveTerms(e.Term, resolve)
+	}
+	return nil
+}
+
+// flattenAnd lists the terms of nested ANDs
+func flattenAnd(and *SearchAnd) []SearchExpr {
+	var terms []SearchExpr
+	for _, t := range and.Terms {
+		if inner, ok := t.(*SearchAnd); ok {
+			terms = append(terms, flattenAnd(inner)...)
+		} else {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// serverBranches returns the alternatives of an OR when Zoho can search for each one
+func serverBranches(expr SearchExpr) ([][]*SearchTerm, bool) {
+	or, ok := expr.(*SearchOr)
+	if !ok || len(or.Terms) > maxSearchBranches {
+		return nil, false
+	}
+	var branches [][]*SearchTerm
+	for _, alt := range or.Terms {
+		alts := []SearchExpr{alt}
+		if and, ok := alt.(*SearchAnd); ok {
+			alts = flattenAnd(and)
+		}
+		branch := make([]*SearchTerm, 0, len(alts))
+		for _, a := range alts {
+			t, ok := a.(*SearchTerm)
+			if !ok || !t.serverSide() {
+				return nil, false
+			}
+			branch = append(branch, t)
+		}
+		branches = append(branches, branch)
+	}
+	return branches, true
+}
+
+// serverSide reports whether Zoho can search for the term
+func (t *SearchTerm) serverSide() bool {
+	switch t.Field {
+	case "", "from", "to", "subject", "after", "before", "has":
+		return true
+	case "is":
+		return t.Value == "unread"
+	}
+	return false
+}
+
+// searchKey builds the Zoho search key for terms that must all match
+func searchKey(terms []*SearchTerm) string {
+	sq := NewSearchQuery()
+	for _, t := range terms {
+		value := t.Value
+		if strings.ContainsAny(value, " \t") {
+			value = `"` + value + `"`
+		}
+		switch t.Field {
+		case "":
+			sq.Text(value)
+		case "from":
+			sq.From(value)
+		case "to":
+			sq.To(value)
+		case "subject":
+			sq.Subject(value)
+		case "after":
+			sq.DateAfter(t.day)
+		case "before":
+			sq.DateBefore(t.day)
+		case "has":
+			sq.HasAttachment()
+		case "is":
+			sq.IsUnread()
+		}
+	}
+	return sq.Build()
+}
+
+// matchSearchExpr evaluates a query against a message summary
+func matchSearchExpr(expr SearchExpr, msg *MessageSummary) bool {
+	switch e := expr.(type) {
+	case *SearchTerm:
+		return e.match(msg)
+	case *SearchAnd:
+		for _, t := range e.Terms {
+			if !matchSearchExpr(t, msg) {
+				return false
+			}
+		}
+		return true
+	case *SearchOr:
+		for _, t := range e.Terms {
+			if matchSearchExpr(t, msg) {
+				return true
+			}
+		}
+		return false
+	case *SearchNot:
+		return !matchSearchExpr(e.Term, msg)
+	}
+	return false
+}
+
+// match evaluates a single term against a message summary
+func (t *SearchTerm) match(msg *MessageSummary) bool {
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(t.Value))
+	}
+	switch t.Field {
+	case "":
+		return contains(msg.Subject + "\n" + msg.FromAddress + "\n" + msg.Sender + "\n" + msg.Summary)
+	case "from":
+		return contains(msg.FromAddress + "\n" + msg.Sender)
+	case "to":
+		return contains(msg.ToAddress + "\n" + msg.CcAddress)
+	case "subject":
+		return contains(msg.Subject)
+	case "after", "before":
+		ms, err := strconv.ParseInt(msg.ReceivedTime, 10, 64)
+		if err != nil {
+			return false
+		}
+		received := time.UnixMilli(ms).UTC()
+		if t.Field == "after" {
+			return !received.Before(t.day)
+		}
+		return received.Before(t.day)
+	case "has":
+		return msg.HasAttachment == "1" || msg.HasAttachment == "true"
+	case "is":
+		switch t.Value {
+		case "unread":
+			return msg.Status == "0"
+		case "read":
+			return msg.Status == "1"
+		default:
+			return msg.FlagID != "" && msg.FlagID != FlagNone
+		}
+	case "in":
+		return msg.FolderID == t.id
+	case "label":
+		return slices.Contains(msg.LabelIDs, t.id)
+	case "larger", "smaller":
+		size, err := strconv.ParseInt(msg.Size, 10, 64)
+		if err != nil {
+			return false
+		}
+		if t.Field == "larger" {
+			return size > t.size
+		}
+		return size < t.size
+	}
+	return false
+}
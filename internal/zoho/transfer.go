@@ -0,0 +1,218 @@
+package zoho
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTransferTimeout bounds a single attachment upload or download.
+// Transfers do not use the 30s API timeout, so large files are not cut off midway.
+const DefaultTransferTimeout = 30 * time.Minute
+
+// MaxAttachmentSize is the largest attachment Zoho Mail accepts through the API
+const MaxAttachmentSize int64 = 20 << 20
+
+// AttachmentTooLargeError reports a file over MaxAttachmentSize
+type AttachmentTooLargeError struct {
+	Name string
+	Size int64 // -1 when the limit was hit while streaming
+}
+
+func (e *AttachmentTooLargeError) Error() string {
+	limit := MaxAttachmentSize >> 20
+	if e.Size < 0 {
+		return fmt.Sprintf("%s is larger than %d MB; Zoho Mail accepts attachments up to %d MB", e.Name, limit, limit)
+	}
+	// Round up so a file just over the limit never reads as "20.0 MB"
+	mb := math.Ceil(float64(e.Size)*10/(1<<20)) / 10
+	return fmt.Sprintf("%s is %.1f MB; Zoho Mail accepts attachments up to %d MB", e.Name, mb, limit)
+}
+
+// CheckAttachmentSize returns an *AttachmentTooLargeError if a file of size
+// bytes cannot be uploaded as an attachment
+func CheckAttachmentSize(name string, size int64) error {
+	if size > MaxAttachmentSize {
+		return &AttachmentTooLargeError{Name: name, Size: size}
+	}
+	return nil
+}
+
+// ProgressFunc receives the bytes transferred so far and the total size,
+// which is -1 when the server did not report it
+type ProgressFunc func(done, total int64)
+
+type progressKey struct{}
+
+// WithProgress returns a context that reports the progress of attachment
+// uploads and downloads made with it to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressFrom returns the ProgressFunc attached to ctx, or nil
+func progressFrom(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
+
+// progressReader counts the bytes read through it, reporting them to fn and
+// failing once more than limit bytes have been read (0 = no limit)
+type progressReader struct {
+	r     io.Reader
+	name  string
+	done  int64
+	total int64
+	limit int64
+	fn    ProgressFunc
+
+	mu sync.Mutex
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += int64(n)
+	if p.limit > 0 && p.done > p.limit {
+		return n, &AttachmentTooLargeError{Name: p.name, Size: -1}
+	}
+	if p.fn != nil && n > 0 {
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// transferContext bounds an upload or download with the transfer deadline
+func (c *Client) transferContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.transferTimeout
+	if timeout <= 0 {
+		timeout = DefaultTransferTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// readerSize returns how many bytes r will yield, or -1 when it cannot tell
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// partialDownload identifies the attachment a .part file holds. It is saved
+// next to the file, so a download only resumes a partial file of the same
+// attachment and content.
+type partialDownload struct {
+	Source       string `json:"source"` // the attachment's API path
+	Size         int64  `json:"size"`   // full size, -1 if unknown
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// partialMetaPath returns where the partialDownload of partPath is saved
+func partialMetaPath(partPath string) string {
+	return partPath + ".json"
+}
+
+// newPartialDownload describes the content of a download response
+func newPartialDownload(source string, resp *http.Response, total int64) *partialDownload {
+	return &partialDownload{
+		Source:       source,
+		Size:         total,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// loadPartialDownload returns the partial download of source left at
+// partPath and the offset to resume from. A partial file without a record,
+// or recorded for other content, is not resumed (nil, 0).
+func loadPartialDownload(partPath, source string) (*partialDownload, int64) {
+	info, err := os.Stat(partPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+		return nil, 0
+	}
+	data, err := os.ReadFile(partialMetaPath(partPath))
+	if err != nil {
+		return nil, 0
+	}
+	var partial partialDownload
+	if err := json.Unmarshal(data, &partial); err != nil || partial.Source != source {
+		return nil, 0
+	}
+	if partial.Size >= 0 && info.Size() >= partial.Size {
+		return nil, 0
+	}
+	return &partial, info.Size()
+}
+
+// save records the partial download of partPath
+func (p *partialDownload) save(partPath string) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(partialMetaPath(partPath), data, 0o644)
+}
+
+// validator returns the If-Range value that resumes p only while the
+// content is unchanged, or "" when the server gave none. Weak ETags cannot be
+// used for ranges.
+func (p *partialDownload) validator() string {
+	if p.ETag != "" && !strings.HasPrefix(p.ETag, "W/") {
+		return p.ETag
+	}
+	return p.LastModified
+}
+
+// continues reports whether a 206 response of total bytes continues p
+func (p *partialDownload) continues(resp *http.Response, total int64) bool {
+	if p.Size >= 0 && total >= 0 && p.Size != total {
+		return false
+	}
+	etag := resp.Header.Get("ETag")
+	return p.ETag == "" || etag == "" || etag == p.ETag
+}
+
+// resumed reports whether a 206 response continues a download at offset,
+// and the full size of the content when the server gave it (-1 otherwise)
+func resumed(resp *http.Response, offset int64) (ok bool, total int64) {
+	// Content-Range: bytes <first>-<last>/<total>
+	unit, spec, found := strings.Cut(resp.Header.Get("Content-Range"), " ")
+	if !found || unit != "bytes" {
+		return false, -1
+	}
+	span, size, _ := strings.Cut(spec, "/")
+	first, _, _ := strings.Cut(span, "-")
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start != offset {
+		return false, -1
+	}
+	total, err = strconv.ParseInt(size, 10, 64)
+	if err != nil && resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	} else if err != nil {
+		total = -1
+	}
+	return true, total
+}
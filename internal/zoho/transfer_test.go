@@ -0,0 +1,191 @@
+package zoho_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/SeMmyT/zohcli/internal/config"
+	"github.com/SeMmyT/zohcli/internal/zoho"
+	"github.com/SeMmyT/zohcli/internal/zoho/zohotest"
+)
+
+// cutWriter passes on the first left bytes of a response and drops the rest
+type cutWriter struct {
+	http.ResponseWriter
+	left int
+}
+
+func (w *cutWriter) Write(p []byte) (int, error) {
+	n := min(len(p), w.left)
+	w.left -= n
+	if _, err := w.ResponseWriter.Write(p[:n]); err != nil || n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+func TestMailClientDownloadAttachmentResumes(t *testing.T) {
+	fake := zohotest.New()
+	fake.AccessToken = "test-token"
+	// cut > 0 cuts the next response off after that many bytes
+	var cut atomic.Int64
+	var lastRange atomic.Value // Range header of the latest request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRange.Store(r.Header.Get("Range"))
+		if n := cut.Swap(0); n > 0 {
+			w = &cutWriter{ResponseWriter: w, left: int(n)}
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	unthrottle(t)
+	mc, err := zoho.NewMailClient(&config.Config{Region: "us", APIBase: srv.URL},
+		oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}))
+	require.NoError(t, err)
+
+	content := []byte("id,amount\n1,10\n2,20\n")
+	big := bytes.Repeat([]byte("0123456789abcdef"), 64)
+	other := bytes.Repeat([]byte("x"), len(big))
+	id := fake.AddMessage(zohotest.Message{
+		MessageMetadata: zoho.MessageMetadata{Subject: "Report"},
+		Attachments: []zohotest.StoredAttachment{
+			{Attachment: zoho.Attachment{AttachmentName: "r.csv"}, Data: content},
+			{Attachment: zoho.Attachment{AttachmentName: "big.bin"}, Data: big},
+			{Attachment: zoho.Attachment{AttachmentName: "other.bin"}, Data: other},
+		},
+	})
+	inbox := fake.FolderID("Inbox")
+	ctx := context.Background()
+	atts, err := mc.ListAttachments(ctx, inbox, id)
+	require.NoError(t, err)
+	require.Len(t, atts, 3)
+
+	// interrupt leaves the .part file of a download cut off midway
+	interrupt := func(t *testing.T, attachmentID, dest string) {
+		cut.Store(100)
+		require.Error(t, mc.DownloadAttachment(ctx, inbox, id, attachmentID, dest))
+		require.FileExists(t, dest+".part")
+	}
+
+	tests := []struct {
+		name    string
+		want    []byte
+		prepare func(t *testing.T, dest string)
+		resumed bool
+	}{
+		{name: "fresh", want: content},
+		{
+			name: "resumed",
+			want: big,
+			prepare: func(t *testing.T, dest string) {
+				interrupt(t, atts[1].AttachmentID, dest)
+			},
+			resumed: true,
+		},
+		{
+			name: "partial of another attachment restarts",
+			want: big,
+			prepare: func(t *testing.T, dest string) {
+				interrupt(t, atts[2].AttachmentID, dest)
+			},
+		},
+		{
+			name: "unrecorded partial restarts",
+			want: content,
+			prepare: func(t *testing.T, dest string) {
+				require.NoError(t, os.WriteFile(dest+".part", content[:7], 0o644))
+			},
+		},
+		{
+			name: "stale partial restarts",
+			want: content,
+			prepare: func(t *testing.T, dest string) {
+				require.NoError(t, os.WriteFile(dest+".part", bytes.Repeat([]byte("x"), 64), 0o644))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachmentID := atts[0].AttachmentID
+			if len(tt.want) == len(big) {
+				attachmentID = atts[1].AttachmentID
+			}
+			dest := filepath.Join(t.TempDir(), "download")
+			if tt.prepare != nil {
+				tt.prepare(t, dest)
+			}
+
+			var done, total int64
+			progressCtx := zoho.WithProgress(ctx, func(d, t int64) { done, total = d, t })
+			require.NoError(t, mc.DownloadAttachment(progressCtx, inbox, id, attachmentID, dest))
+
+			data, err := os.ReadFile(dest)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.want, data), "downloaded content differs")
+			assert.NoFileExists(t, dest+".part")
+			assert.NoFileExists(t, dest+".part.json")
+			assert.Equal(t, int64(len(tt.want)), done)
+			assert.Equal(t, int64(len(tt.want)), total)
+			// Only a resumed download fetches just the rest
+			assert.Equal(t, tt.resumed, lastRange.Load() == "bytes=100-", "range %q", lastRange.Load())
+		})
+	}
+}
+
+func TestMailClientDownloadAttachmentKeepsDestOnError(t *testing.T) {
+	fake, mc := newMailClient(t)
+	id := fake.AddMessage(zohotest.Message{MessageMetadata: zoho.MessageMetadata{Subject: "Empty"}})
+	dest := filepath.Join(t.TempDir(), "r.csv")
+	require.NoError(t, os.WriteFile(dest, []byte("old"), 0o644))
+
+	err := mc.DownloadAttachment(context.Background(), fake.FolderID("Inbox"), id, "missing", dest)
+	require.Error(t, err)
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+}
+
+func TestMailClientUploadAttachmentTooLarge(t *testing.T) {
+	_, mc := newMailClient(t)
+	ctx := context.Background()
+
+	// Known size: rejected before the upload starts
+	path := filepath.Join(t.TempDir(), "big.iso")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(zoho.MaxAttachmentSize+1))
+	require.NoError(t, f.Close())
+
+	_, err = mc.UploadAttachment(ctx, path)
+	var tooLarge *zoho.AttachmentTooLargeError
+	require.True(t, errors.As(err, &tooLarge), "got %v", err)
+	assert.Equal(t, "big.iso", tooLarge.Name)
+	assert.Equal(t, zoho.MaxAttachmentSize+1, tooLarge.Size)
+	assert.Contains(t, err.Error(), "up to 20 MB")
+
+	// Unknown size: stopped once the stream passes the limit
+	stream := struct{ io.Reader }{bytes.NewReader(make([]byte, zoho.MaxAttachmentSize+1))}
+	_, err = mc.UploadAttachmentData(ctx, "stream.bin", stream, false)
+	require.True(t, errors.As(err, &tooLarge), "got %v", err)
+	assert.Equal(t, int64(-1), tooLarge.Size)
+
+	// Within the limit: uploaded, with progress reported
+	var progress int64
+	progressCtx := zoho.WithProgress(ctx, func(done, _ int64) { progress = done })
+	ref, err := mc.UploadAttachmentData(progressCtx, "ok.bin", bytes.NewReader(make([]byte, 1024)), false)
+	require.NoError(t, err)
+	assert.Equal(t, "ok.bin", ref.AttachmentName)
+	assert.Equal(t, int64(1024), progress)
+}
@@ -49,6 +49,7 @@ type User struct {
 	IMAPAccessEnabled bool           `json:"imapAccessEnabled"`
 	POPAccessEnabled  bool           `json:"popAccessEnabled"`
 	LastLogin         int64          `json:"lastLogin"` // Unix timestamp in milliseconds
+	TimeZone          string         `json:"timeZone"`  // IANA time zone of the mailbox; set in user details
 }
 
 // PrimaryEmail returns the primary email address string for the user
@@ -0,0 +1,801 @@
+package zohotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// adminRoutes registers the organization admin and mail admin endpoints
+func (s *Server) adminRoutes() {
+	s.mux.HandleFunc("GET /api/organization/{zoid}/accounts", s.forOrg(s.handleListUsers))
+	s.mux.HandleFunc("POST /api/organization/{zoid}/accounts", s.forOrg(s.handleCreateUser))
+	s.mux.HandleFunc("PUT /api/organization/{zoid}/accounts", s.forOrg(s.handleUpdateUser))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/accounts/{id}", s.forOrg(s.handleGetUser))
+	s.mux.HandleFunc("DELETE /api/organization/{zoid}/accounts/{id}", s.forOrg(s.handleDeleteUser))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/accounts/reports/loginHistory", s.forOrg(s.handleLoginHistory))
+
+	s.mux.HandleFunc("GET /api/organization/{zoid}/groups", s.forOrg(s.handleListGroups))
+	s.mux.HandleFunc("POST /api/organization/{zoid}/groups", s.forOrg(s.handleCreateGroup))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/groups/{zgid}", s.forOrg(s.handleGetGroup))
+	s.mux.HandleFunc("PUT /api/organization/{zoid}/groups/{zgid}", s.forOrg(s.handleUpdateGroup))
+	s.mux.HandleFunc("DELETE /api/organization/{zoid}/groups/{zgid}", s.forOrg(s.handleDeleteGroup))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/groups/{zgid}/members", s.forOrg(s.handleGroupMembers))
+
+	s.mux.HandleFunc("GET /api/organization/{zoid}/domains", s.forOrg(s.handleListDomains))
+	s.mux.HandleFunc("POST /api/organization/{zoid}/domains", s.forOrg(s.handleAddDomain))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/domains/{domain}", s.forOrg(s.handleGetDomain))
+	s.mux.HandleFunc("PUT /api/organization/{zoid}/domains/{domain}", s.forOrg(s.handleUpdateDomain))
+
+	s.mux.HandleFunc("GET /api/organization/{zoid}/activity", s.forOrg(s.handleAuditLogs))
+	s.mux.HandleFunc("POST /api/organization/{zoid}/smtplogs", s.forOrg(s.handleSMTPLogs))
+
+	s.mux.HandleFunc("GET /api/organization/{zoid}/antispam/data", s.forOrg(s.handleGetSpam))
+	s.mux.HandleFunc("PUT /api/organization/{zoid}/antispam/data", s.forOrg(s.handleUpdateSpam))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/mailpolicy/retention", s.forOrg(s.handleRetention))
+	s.mux.HandleFunc("GET /api/organization/{zoid}/deliverylog", s.forOrg(s.handleDeliveryLogs))
+}
+
+// forOrg wraps a handler so it only serves the fake's organization ID
+func (s *Server) forOrg(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("zoid") != strconv.FormatInt(ZOID, 10) {
+			writeError(w, http.StatusNotFound, "ORGANIZATION_NOT_EXIST")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// AddUser adds a user to the organization and returns its ZUID.
+// The user's account ID is the same number, so either identifier works for lookups.
+func (s *Server) AddUser(u zoho.User) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(u)
+}
+
+func (s *Server) addUser(u zoho.User) int64 {
+	if u.ZUID == 0 {
+		u.ZUID, _ = strconv.ParseInt(s.newID(), 10, 64)
+	}
+	if u.AccountID == "" {
+		u.AccountID = strconv.FormatInt(u.ZUID, 10)
+	}
+	if u.PrimaryEmailID != "" && len(u.EmailAddress) == 0 {
+		u.EmailAddress = []zoho.EmailAddress{{MailID: u.PrimaryEmailID, IsPrimary: true}}
+	}
+	if u.Role == "" {
+		u.Role = "member"
+	}
+	if u.MailboxStatus == "" {
+		u.MailboxStatus = "enabled"
+	}
+	if u.DisplayName == "" {
+		u.DisplayName = strings.TrimSpace(u.FirstName + " " + u.LastName)
+	}
+	s.users = append(s.users, u)
+	return u.ZUID
+}
+
+// User returns a copy of the user with the given ZUID or account ID, or nil
+func (s *Server) User(id int64) *zoho.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.userByID(id); u != nil {
+		c := *u
+		return &c
+	}
+	return nil
+}
+
+// UserVacation returns the vacation reply an admin set for a user, or nil
+func (s *Server) UserVacation(zuid int64) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userVacations[zuid]
+}
+
+func (s *Server) userByID(id int64) *zoho.User {
+	for i := range s.users {
+		if s.users[i].ZUID == id || s.users[i].AccountID == strconv.FormatInt(id, 10) {
+			return &s.users[i]
+		}
+	}
+	return nil
+}
+
+// AddGroup adds a group with the given member addresses and returns its ZGID
+func (s *Server) AddGroup(g zoho.Group, members ...string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g.ZGID == 0 {
+		g.ZGID, _ = strconv.ParseInt(s.newID(), 10, 64)
+	}
+	grp := &group{Group: g}
+	for _, m := range members {
+		grp.Members = append(grp.Members, zoho.GroupMember{MemberEmailID: m, Role: "member"})
+	}
+	s.groups = append(s.groups, grp)
+	return g.ZGID
+}
+
+// GroupMembers returns the member addresses of a group
+func (s *Server) GroupMembers(zgid int64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	if g := s.groupByID(zgid); g != nil {
+		for _, m := range g.Members {
+			out = append(out, m.MemberEmailID)
+		}
+	}
+	return out
+}
+
+func (s *Server) groupByID(zgid int64) *group {
+	for _, g := range s.groups {
+		if g.ZGID == zgid {
+			return g
+		}
+	}
+	return nil
+}
+
+// AddDomain adds a domain to the organization
+func (s *Server) AddDomain(d zoho.Domain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.DomainID == "" {
+		d.DomainID = s.newID()
+	}
+	s.domains = append(s.domains, d)
+}
+
+// Domain returns a copy of the named domain, or nil
+func (s *Server) Domain(name string) *zoho.Domain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.domainByName(name); d != nil {
+		c := *d
+		return &c
+	}
+	return nil
+}
+
+func (s *Server) domainByName(name string) *zoho.Domain {
+	for i := range s.domains {
+		if strings.EqualFold(s.domains[i].DomainName, name) {
+			return &s.domains[i]
+		}
+	}
+	return nil
+}
+
+// AddAuditLog adds an admin audit log entry
+func (s *Server) AddAuditLog(e zoho.AuditLog) {
+	s.withLock(func() { s.auditLogs = append(s.auditLogs, e) })
+}
+
+// AddLoginHistory adds a login history entry
+func (s *Server) AddLoginHistory(e zoho.LoginHistoryEntry) {
+	s.withLock(func() { s.loginHistory = append(s.loginHistory, e) })
+}
+
+// AddSMTPLog adds an SMTP transaction log entry
+func (s *Server) AddSMTPLog(e zoho.SMTPLogEntry) {
+	s.withLock(func() { s.smtpLogs = append(s.smtpLogs, e) })
+}
+
+// AddDeliveryLog adds a mail delivery log entry
+func (s *Server) AddDeliveryLog(e zoho.DeliveryLog) {
+	s.withLock(func() { s.deliveryLogs = append(s.deliveryLogs, e) })
+}
+
+// SpamList returns the values stored for a spam category
+func (s *Server) SpamList(category zoho.SpamCategory) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.spam[string(category)]...)
+}
+
+// SetRetentionPolicy replaces the raw retention policy response body
+func (s *Server) SetRetentionPolicy(body json.RawMessage) {
+	s.withLock(func() { s.retention = body })
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.withLock(func() {
+		writeData(w, http.StatusOK, offsetPage(s.users, q))
+	})
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid account ID")
+		return
+	}
+	s.withLock(func() {
+		u := s.userByID(id)
+		if u == nil {
+			writeError(w, http.StatusNotFound, "USER_NOT_EXIST")
+			return
+		}
+		writeData(w, http.StatusOK, u)
+	})
+}
+
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var req zoho.CreateUserRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.PrimaryEmailAddress == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "primaryEmailAddress and password are required")
+		return
+	}
+
+	s.withLock(func() {
+		for _, u := range s.users {
+			if strings.EqualFold(u.PrimaryEmail(), req.PrimaryEmailAddress) {
+				writeError(w, http.StatusConflict, "EMAIL_ALREADY_EXISTS")
+				return
+			}
+		}
+		zuid := s.addUser(zoho.User{
+			PrimaryEmailID: req.PrimaryEmailAddress,
+			FirstName:      req.FirstName,
+			LastName:       req.LastName,
+			DisplayName:    req.DisplayName,
+			Role:           req.Role,
+			TimeZone:       req.TimeZone,
+		})
+		writeData(w, http.StatusCreated, s.userByID(zuid))
+	})
+}
+
+func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode             string          `json:"mode"`
+		ZUID             int64           `json:"zuid"`
+		NewRole          string          `json:"newRole"`
+		VacationResponse json.RawMessage `json:"vacationResponse"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.userByID(req.ZUID)
+	if u == nil {
+		writeError(w, http.StatusNotFound, "USER_NOT_EXIST")
+		return
+	}
+
+	switch req.Mode {
+	case "changeRole":
+		if req.NewRole == "" {
+			writeError(w, http.StatusBadRequest, "newRole is required")
+			return
+		}
+		u.Role = req.NewRole
+	case "enableUser":
+		u.MailboxStatus = "enabled"
+	case "disableUser":
+		u.MailboxStatus = "disabled"
+	case "addVacationReply":
+		if len(req.VacationResponse) == 0 {
+			writeError(w, http.StatusBadRequest, "vacationResponse is required")
+			return
+		}
+		s.userVacations[u.ZUID] = req.VacationResponse
+	case "disableVacationReply":
+		delete(s.userVacations, u.ZUID)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid account ID")
+		return
+	}
+	s.withLock(func() {
+		u := s.userByID(id)
+		if u == nil {
+			writeError(w, http.StatusNotFound, "USER_NOT_EXIST")
+			return
+		}
+		for i := range s.users {
+			if &s.users[i] == u {
+				s.users = append(s.users[:i], s.users[i+1:]...)
+				break
+			}
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.withLock(func() {
+		groups := make([]zoho.Group, len(s.groups))
+		for i, g := range s.groups {
+			groups[i] = g.withCount()
+		}
+		writeData(w, http.StatusOK, map[string]any{
+			"groups": offsetPage(groups, q),
+			"count":  len(groups),
+		})
+	})
+}
+
+// withCount returns the group with MembersCount reflecting its members
+func (g *group) withCount() zoho.Group {
+	out := g.Group
+	out.MembersCount = len(g.Members)
+	return out
+}
+
+// pathGroup looks up the group addressed by the request path
+func (s *Server) pathGroup(w http.ResponseWriter, r *http.Request) *group {
+	zgid, err := strconv.ParseInt(r.PathValue("zgid"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid group ID")
+		return nil
+	}
+	g := s.groupByID(zgid)
+	if g == nil {
+		writeError(w, http.StatusNotFound, "GROUP_NOT_EXIST")
+	}
+	return g
+}
+
+func (s *Server) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		if g := s.pathGroup(w, r); g != nil {
+			writeData(w, http.StatusOK, g.withCount())
+		}
+	})
+}
+
+func (s *Server) handleGroupMembers(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		if g := s.pathGroup(w, r); g != nil {
+			writeData(w, http.StatusOK, append([]zoho.GroupMember{}, g.Members...))
+		}
+	})
+}
+
+func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req zoho.CreateGroupRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.GroupName == "" || req.GroupEmailAddress == "" {
+		writeError(w, http.StatusBadRequest, "groupName and groupEmailAddress are required")
+		return
+	}
+
+	s.withLock(func() {
+		zgid, _ := strconv.ParseInt(s.newID(), 10, 64)
+		g := &group{Group: zoho.Group{
+			ZGID:              zgid,
+			GroupName:         req.GroupName,
+			GroupEmailAddress: req.GroupEmailAddress,
+			Description:       req.Description,
+		}}
+		for _, m := range req.AdminsEmailList {
+			g.Members = append(g.Members, zoho.GroupMember{MemberEmailID: m, Role: "moderator"})
+		}
+		for _, m := range req.MembersEmailList {
+			g.Members = append(g.Members, zoho.GroupMember{MemberEmailID: m, Role: "member"})
+		}
+		s.groups = append(s.groups, g)
+		writeData(w, http.StatusCreated, g.withCount())
+	})
+}
+
+func (s *Server) handleUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode                string `json:"mode"`
+		GroupName           string `json:"groupName"`
+		Description         string `json:"description"`
+		MailGroupMemberList []struct {
+			MemberEmailID string `json:"memberEmailID"`
+			Role          string `json:"role"`
+		} `json:"mailGroupMemberList"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.pathGroup(w, r)
+	if g == nil {
+		return
+	}
+
+	switch req.Mode {
+	case "updateMailGroup":
+		if req.GroupName != "" {
+			g.GroupName = req.GroupName
+		}
+		if req.Description != "" {
+			g.Description = req.Description
+		}
+	case "addMailGroupMember":
+		for _, m := range req.MailGroupMemberList {
+			role := m.Role
+			if role == "" {
+				role = "member"
+			}
+			g.Members = append(g.Members, zoho.GroupMember{MemberEmailID: m.MemberEmailID, Role: role})
+		}
+	case "removeMailGroupMember":
+		for _, m := range req.MailGroupMemberList {
+			for i, existing := range g.Members {
+				if strings.EqualFold(existing.MemberEmailID, m.MemberEmailID) {
+					g.Members = append(g.Members[:i], g.Members[i+1:]...)
+					break
+				}
+			}
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		g := s.pathGroup(w, r)
+		if g == nil {
+			return
+		}
+		for i := range s.groups {
+			if s.groups[i] == g {
+				s.groups = append(s.groups[:i], s.groups[i+1:]...)
+				break
+			}
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) handleListDomains(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		writeData(w, http.StatusOK, map[string]any{
+			"domainVO": append([]zoho.Domain{}, s.domains...),
+		})
+	})
+}
+
+func (s *Server) handleGetDomain(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		d := s.domainByName(r.PathValue("domain"))
+		if d == nil {
+			writeError(w, http.StatusNotFound, "DOMAIN_NOT_EXIST")
+			return
+		}
+		writeData(w, http.StatusOK, d)
+	})
+}
+
+func (s *Server) handleAddDomain(w http.ResponseWriter, r *http.Request) {
+	var req zoho.AddDomainRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.DomainName == "" {
+		writeError(w, http.StatusBadRequest, "domainName is required")
+		return
+	}
+
+	s.withLock(func() {
+		if s.domainByName(req.DomainName) != nil {
+			writeError(w, http.StatusConflict, "DOMAIN_ALREADY_EXISTS")
+			return
+		}
+		d := zoho.Domain{
+			DomainName:          req.DomainName,
+			DomainID:            s.newID(),
+			MXStatus:            "disabled",
+			TXTVerificationCode: "zoho-verification=zb" + s.newID() + ".zmverify.zoho.com",
+		}
+		s.domains = append(s.domains, d)
+		writeData(w, http.StatusCreated, d)
+	})
+}
+
+func (s *Server) handleUpdateDomain(w http.ResponseWriter, r *http.Request) {
+	var req zoho.DomainModeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.domainByName(r.PathValue("domain"))
+	if d == nil {
+		writeError(w, http.StatusNotFound, "DOMAIN_NOT_EXIST")
+		return
+	}
+
+	switch req.Mode {
+	case "verifyDomainByTXT", "verifyDomainByCName", "verifyDomainByHTML":
+		d.VerificationStatus = true
+		d.VerifiedDate = baseTime.UnixMilli()
+	case "enableHosting":
+		d.MailHostingEnabled = true
+	case "disableHosting":
+		d.MailHostingEnabled = false
+	case "enableDkim":
+		d.DKIMStatus = true
+	case "disableDkim":
+		d.DKIMStatus = false
+	case "setPrimary":
+		for i := range s.domains {
+			s.domains[i].Primary = false
+		}
+		d.Primary = true
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleAuditLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	startTime := int64(intParam(q, "startTime", 0))
+	endTime := int64(intParam(q, "endTime", 0))
+	limit := intParam(q, "limit", 100)
+	searchKey := strings.ToLower(q.Get("searchKey"))
+
+	// lastEntityId is the index of the last entry returned on the previous page
+	from := 0
+	if last := q.Get("lastEntityId"); last != "" {
+		n, err := strconv.Atoi(last)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid lastEntityId")
+			return
+		}
+		from = n + 1
+	}
+
+	s.withLock(func() {
+		var matched []zoho.AuditLog
+		for _, e := range s.auditLogs {
+			if startTime != 0 && e.RequestTime < startTime || endTime != 0 && e.RequestTime > endTime {
+				continue
+			}
+			if searchKey != "" && !strings.Contains(strings.ToLower(e.PerformedBy+" "+e.Operation+" "+e.PerformedOn), searchKey) {
+				continue
+			}
+			matched = append(matched, e)
+		}
+
+		data := map[string]any{"audit": []zoho.AuditLog{}}
+		if from < len(matched) {
+			to := min(from+limit, len(matched))
+			data["audit"] = matched[from:to]
+			if to < len(matched) {
+				data["lastEntityId"] = strconv.Itoa(to - 1)
+				data["lastIndexTime"] = strconv.FormatInt(matched[to-1].RequestTime, 10)
+			}
+		}
+		writeData(w, http.StatusOK, data)
+	})
+}
+
+func (s *Server) handleLoginHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	fromTime := int64(intParam(q, "fromTime", 0))
+	toTime := int64(intParam(q, "toTime", 0))
+	batchSize := intParam(q, "batchSize", 100)
+
+	// scrollId is the offset of the next entry to return
+	from := 0
+	if id := q.Get("scrollId"); id != "" {
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid scrollId")
+			return
+		}
+		from = n
+	}
+
+	s.withLock(func() {
+		var matched []zoho.LoginHistoryEntry
+		for _, e := range s.loginHistory {
+			if fromTime != 0 && e.LoginTime < fromTime || toTime != 0 && e.LoginTime > toTime {
+				continue
+			}
+			matched = append(matched, e)
+		}
+
+		data := map[string]any{"loginHistory": []zoho.LoginHistoryEntry{}}
+		if from < len(matched) {
+			to := min(from+batchSize, len(matched))
+			data["loginHistory"] = matched[from:to]
+			if to < len(matched) {
+				data["scrollId"] = strconv.Itoa(to)
+			}
+		}
+		writeData(w, http.StatusOK, data)
+	})
+}
+
+func (s *Server) handleSMTPLogs(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FromDateTime   int64  `json:"fromDateTime"`
+		ToDateTime     int64  `json:"toDateTime"`
+		SearchCriteria string `json:"searchCriteria"`
+		SearchKey      string `json:"searchKey"`
+		Limit          int    `json:"limit"`
+		IsNext         bool   `json:"isNext"`
+		PageKey        string `json:"pageKey"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 100
+	}
+
+	// pageKey is the offset of the next entry to return
+	from := 0
+	if req.IsNext && req.PageKey != "" {
+		n, err := strconv.Atoi(req.PageKey)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid pageKey")
+			return
+		}
+		from = n
+	}
+
+	key := strings.ToLower(req.SearchKey)
+
+	s.withLock(func() {
+		var matched []zoho.SMTPLogEntry
+		for _, e := range s.smtpLogs {
+			if req.FromDateTime != 0 && e.Timestamp < req.FromDateTime || req.ToDateTime != 0 && e.Timestamp > req.ToDateTime {
+				continue
+			}
+			if key != "" {
+				var field string
+				switch req.SearchCriteria {
+				case "fromAddr":
+					field = e.FromAddress
+				case "toAddr":
+					field = strings.Join(e.ToAddresses, " ")
+				case "subject":
+					field = e.Subject
+				default:
+					field = e.FromAddress + " " + strings.Join(e.ToAddresses, " ") + " " + e.Subject + " " + e.MessageID
+				}
+				if !strings.Contains(strings.ToLower(field), key) {
+					continue
+				}
+			}
+			matched = append(matched, e)
+		}
+
+		data := map[string]any{
+			"hnxt":        false,
+			"hasPrevious": from > 0,
+			"response":    []zoho.SMTPLogEntry{},
+		}
+		if from < len(matched) {
+			to := min(from+req.Limit, len(matched))
+			data["response"] = matched[from:to]
+			if to < len(matched) {
+				data["hnxt"] = true
+				data["pagekey"] = strconv.Itoa(to)
+			}
+		}
+		writeData(w, http.StatusOK, data)
+	})
+}
+
+func (s *Server) handleGetSpam(w http.ResponseWriter, r *http.Request) {
+	category := r.URL.Query().Get("spamCategory")
+	if _, ok := validSpamCategories[category]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid Input: spamCategory %q", category))
+		return
+	}
+	s.withLock(func() {
+		writeData(w, http.StatusOK, append([]string{}, s.spam[category]...))
+	})
+}
+
+func (s *Server) handleUpdateSpam(w http.ResponseWriter, r *http.Request) {
+	var req zoho.SpamUpdateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if _, ok := validSpamCategories[req.SpamCategory]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid Input: spamCategory %q", req.SpamCategory))
+		return
+	}
+
+	s.withLock(func() {
+		existing := s.spam[req.SpamCategory]
+		for _, v := range req.Value {
+			dup := false
+			for _, e := range existing {
+				if strings.EqualFold(e, v) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				existing = append(existing, v)
+			}
+		}
+		s.spam[req.SpamCategory] = existing
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+// validSpamCategories holds the Zoho API spam category enum values
+var validSpamCategories = func() map[string]struct{} {
+	m := make(map[string]struct{}, len(zoho.SpamCategoryMap))
+	for _, c := range zoho.SpamCategoryMap {
+		m[string(c)] = struct{}{}
+	}
+	return m
+}()
+
+func (s *Server) handleRetention(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(s.retention)
+	})
+}
+
+func (s *Server) handleDeliveryLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.withLock(func() {
+		writeData(w, http.StatusOK, offsetPage(s.deliveryLogs, q))
+	})
+}
+
+// offsetPage applies 0-based start and limit query parameters to items
+func offsetPage[T any](items []T, q map[string][]string) []T {
+	start := intParam(q, "start", 0)
+	if start < 0 {
+		start = 0
+	}
+	limit := intParam(q, "limit", 50)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	out := []T{}
+	if start < len(items) {
+		out = append(out, items[start:min(start+limit, len(items))]...)
+	}
+	return out
+}
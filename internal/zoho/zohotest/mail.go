@@ -0,0 +1,1514 @@
+package zohotest
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// mailRoutes registers the Zoho Mail endpoints
+func (s *Server) mailRoutes() {
+	s.mux.HandleFunc("GET /api/accounts", s.handleAccounts)
+	s.mux.HandleFunc("GET /api/accounts/signature", s.handleListSignatures)
+	s.mux.HandleFunc("POST /api/accounts/signature", s.handleAddSignature)
+	s.mux.HandleFunc("GET /api/accounts/{account}", s.forAccount(s.handleAccountDetails))
+	s.mux.HandleFunc("PUT /api/accounts/{account}", s.forAccount(s.handleUpdateAccount))
+
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders", s.forAccount(s.handleListFolders))
+	s.mux.HandleFunc("POST /api/accounts/{account}/folders", s.forAccount(s.handleCreateFolder))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/folders/{folder}", s.forAccount(s.handleUpdateFolder))
+	s.mux.HandleFunc("DELETE /api/accounts/{account}/folders/{folder}", s.forAccount(s.handleDeleteFolder))
+	s.mux.HandleFunc("GET /api/accounts/{account}/labels", s.forAccount(s.handleListLabels))
+	s.mux.HandleFunc("POST /api/accounts/{account}/labels", s.forAccount(s.handleCreateLabel))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/labels/{label}", s.forAccount(s.handleUpdateLabel))
+	s.mux.HandleFunc("DELETE /api/accounts/{account}/labels/{label}", s.forAccount(s.handleDeleteLabel))
+
+	s.mux.HandleFunc("GET /api/accounts/{account}/messages/view", s.forAccount(s.handleListMessages))
+	s.mux.HandleFunc("GET /api/accounts/{account}/messages/search", s.forAccount(s.handleSearchMessages))
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders/{folder}/messages/{message}/details", s.forAccount(s.handleMessageDetails))
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders/{folder}/messages/{message}/content", s.forAccount(s.handleMessageContent))
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders/{folder}/messages/{message}/originalmessage", s.forAccount(s.handleOriginalMessage))
+	s.mux.HandleFunc("POST /api/accounts/{account}/folders/{folder}/messages/import", s.forAccount(s.handleImportMessage))
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders/{folder}/messages/{message}/attachments", s.forAccount(s.handleListAttachments))
+	s.mux.HandleFunc("GET /api/accounts/{account}/folders/{folder}/messages/{message}/attachments/{attachment}", s.forAccount(s.handleDownloadAttachment))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/updatemessage", s.forAccount(s.handleUpdateMessages))
+	s.mux.HandleFunc("DELETE /api/accounts/{account}/folders/{folder}/messages/{message}", s.forAccount(s.handleDeleteMessage))
+
+	s.mux.HandleFunc("POST /api/accounts/{account}/messages/attachments", s.forAccount(s.handleUploadAttachment))
+	s.mux.HandleFunc("POST /api/accounts/{account}/messages", s.forAccount(s.handleSend))
+	s.mux.HandleFunc("POST /api/accounts/{account}/messages/{message}", s.forAccount(s.handleSend))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/messages/{message}", s.forAccount(s.handleUpdateDraft))
+
+	s.mux.HandleFunc("GET /api/accounts/{account}/filters", s.forAccount(s.handleListFilters))
+	s.mux.HandleFunc("POST /api/accounts/{account}/filters", s.forAccount(s.handleCreateFilter))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/filters", s.forAccount(s.handleReorderFilters))
+	s.mux.HandleFunc("GET /api/accounts/{account}/filters/{filter}", s.forAccount(s.handleGetFilter))
+	s.mux.HandleFunc("PUT /api/accounts/{account}/filters/{filter}", s.forAccount(s.handleUpdateFilter))
+	s.mux.HandleFunc("DELETE /api/accounts/{account}/filters/{filter}", s.forAccount(s.handleDeleteFilter))
+}
+
+// forAccount wraps a handler so it only serves the fake's account ID
+func (s *Server) forAccount(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("account") != AccountID {
+			writeError(w, http.StatusNotFound, "ACCOUNT_NOT_EXIST")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// AddFolder adds a user folder and returns its ID
+func (s *Server) AddFolder(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addFolder(name, "/"+name, "")
+}
+
+func (s *Server) addFolder(name, path, folderType string) string {
+	id := s.newID()
+	s.folders = append(s.folders, zoho.Folder{
+		FolderID:   id,
+		FolderName: name,
+		FolderType: folderType,
+		Path:       path,
+	})
+	return id
+}
+
+// FolderID returns the ID of the folder with the given name, or "" if none exists
+func (s *Server) FolderID(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f := s.folderByName(name); f != nil {
+		return f.FolderID
+	}
+	return ""
+}
+
+func (s *Server) folderByName(name string) *zoho.Folder {
+	for i := range s.folders {
+		if strings.EqualFold(s.folders[i].FolderName, name) {
+			return &s.folders[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) folderByID(id string) *zoho.Folder {
+	for i := range s.folders {
+		if s.folders[i].FolderID == id {
+			return &s.folders[i]
+		}
+	}
+	return nil
+}
+
+// AddLabel adds a label and returns its ID
+func (s *Server) AddLabel(name, color string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newID()
+	s.labels = append(s.labels, zoho.Label{LabelID: id, LabelName: name, LabelColor: color})
+	return id
+}
+
+func (s *Server) labelByID(id string) *zoho.Label {
+	for i := range s.labels {
+		if s.labels[i].LabelID == id {
+			return &s.labels[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) labelByName(name string) *zoho.Label {
+	for i := range s.labels {
+		if strings.EqualFold(s.labels[i].LabelName, name) {
+			return &s.labels[i]
+		}
+	}
+	return nil
+}
+
+// LabelID returns the ID of the label with the given name, or "" if none exists
+func (s *Server) LabelID(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l := s.labelByName(name); l != nil {
+		return l.LabelID
+	}
+	return ""
+}
+
+// AddMessage stores a message and returns its ID.
+// Unset fields are filled with defaults: the Inbox folder, a fresh thread,
+// an unread status and a receive time one minute after the previous message.
+func (s *Server) AddMessage(m Message) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addMessage(m)
+}
+
+func (s *Server) addMessage(m Message) string {
+	if m.MessageID == "" {
+		m.MessageID = s.newID()
+	}
+	if m.ThreadID == "" {
+		m.ThreadID = m.MessageID
+	}
+	if m.FolderID == "" {
+		m.FolderID = s.folderByName("Inbox").FolderID
+	}
+	if m.ReceivedTime == "" {
+		m.ReceivedTime = millis(baseTime.Add(time.Duration(len(s.messages)) * time.Minute))
+	}
+	if m.SentDateInGMT == "" {
+		m.SentDateInGMT = m.ReceivedTime
+	}
+	if m.Sender == "" {
+		m.Sender = m.FromAddress
+	}
+	if m.Status == "" {
+		m.Status = "0"
+	}
+	if m.Priority == "" {
+		m.Priority = "3"
+	}
+	if m.Summary == "" {
+		m.Summary = stripTags(m.Content)
+	}
+	if m.MessageSize == "" {
+		m.MessageSize = strconv.Itoa(len(m.Content))
+	}
+	for i := range m.Attachments {
+		a := &m.Attachments[i]
+		if a.AttachmentID == "" {
+			a.AttachmentID = s.newID()
+		}
+		if a.AttachmentSize == 0 {
+			a.AttachmentSize = int64(len(a.Data))
+		}
+		if a.AttachmentType == "" {
+			a.AttachmentType = "application/octet-stream"
+		}
+	}
+	m.HasAttachment = "0"
+	if len(m.Attachments) > 0 {
+		m.HasAttachment = "1"
+	}
+
+	msg := m
+	s.messages = append(s.messages, &msg)
+	return m.MessageID
+}
+
+// Message returns a copy of a stored message, or nil if it does not exist
+func (s *Server) Message(id string) *Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m := s.messageByID(id); m != nil {
+		c := *m
+		return &c
+	}
+	return nil
+}
+
+// FolderMessages returns the IDs of the messages in a folder, newest first
+func (s *Server) FolderMessages(name string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder := s.folderByName(name)
+	if folder == nil {
+		return nil
+	}
+	var matched []*Message
+	for _, m := range s.messages {
+		if m.FolderID == folder.FolderID {
+			matched = append(matched, m)
+		}
+	}
+	var ids []string
+	for _, m := range sortNewestFirst(matched) {
+		ids = append(ids, m.MessageID)
+	}
+	return ids
+}
+
+func (s *Server) messageByID(id string) *Message {
+	for _, m := range s.messages {
+		if m.MessageID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// Drafts returns the saved draft requests keyed by draft message ID
+func (s *Server) Drafts() map[string]zoho.SendEmailRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]zoho.SendEmailRequest, len(s.drafts))
+	for id, d := range s.drafts {
+		if s.messageByID(id) != nil {
+			out[id] = d.req
+		}
+	}
+	return out
+}
+
+// Sent returns every send, reply and forward request received so far
+func (s *Server) Sent() []zoho.SendEmailRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]zoho.SendEmailRequest(nil), s.sent...)
+}
+
+// DisplayName returns the account display name
+func (s *Server) DisplayName() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.displayName
+}
+
+// AddFilter stores an incoming mail filter after the existing ones and returns its ID
+func (s *Server) AddFilter(f zoho.MailFilter) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.FilterID = s.newID()
+	f.FilterOrder = len(s.filters) + 1
+	s.filters = append(s.filters, f)
+	return f.FilterID
+}
+
+// Filters returns the stored filters in the order they run
+func (s *Server) Filters() []zoho.MailFilter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]zoho.MailFilter(nil), s.filters...)
+}
+
+// SetTimeZone sets the time zone account details report for the mailbox
+func (s *Server) SetTimeZone(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeZone = name
+}
+
+// Vacation returns the active vacation reply, or nil when disabled
+func (s *Server) Vacation() json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.vacation
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		acct := s.account
+		acct.DisplayName = s.displayName
+		writeData(w, http.StatusOK, []any{struct {
+			zoho.AccountData
+			EmailAddress       []zoho.EmailAddress `json:"emailAddress"`
+			AccountDisplayName string              `json:"accountDisplayName"`
+			Type               string              `json:"type"`
+		}{
+			AccountData:        acct,
+			EmailAddress:       []zoho.EmailAddress{{MailID: Email, IsPrimary: true}},
+			AccountDisplayName: s.displayName,
+			Type:               "ZOHO_ACCOUNT",
+		}})
+	})
+}
+
+func (s *Server) handleAccountDetails(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		writeData(w, http.StatusOK, zoho.AccountDetails{
+			AccountDisplayName:  s.displayName,
+			DisplayName:         s.displayName,
+			PrimaryEmailAddress: Email,
+			EmailAddress:        []zoho.EmailAddress{{MailID: Email, IsPrimary: true}},
+			TimeZone:            s.timeZone,
+			VacationResponse:    s.vacation,
+			ForwardDetails:      s.forwardDetails,
+		})
+	})
+}
+
+func (s *Server) handleUpdateAccount(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode             string          `json:"mode"`
+		DisplayName      string          `json:"displayName"`
+		VacationResponse json.RawMessage `json:"vacationResponse"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Mode {
+	case "updateDisplayName":
+		if req.DisplayName == "" {
+			writeError(w, http.StatusBadRequest, "displayName is required")
+			return
+		}
+		s.displayName = req.DisplayName
+	case "addVacationReply":
+		if len(req.VacationResponse) == 0 {
+			writeError(w, http.StatusBadRequest, "vacationResponse is required")
+			return
+		}
+		s.vacation = req.VacationResponse
+	case "disableVacationReply":
+		s.vacation = nil
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleListSignatures(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		writeData(w, http.StatusOK, append([]zoho.Signature{}, s.signatures...))
+	})
+}
+
+func (s *Server) handleAddSignature(w http.ResponseWriter, r *http.Request) {
+	var sig zoho.Signature
+	if !decodeBody(w, r, &sig) {
+		return
+	}
+	if sig.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	s.withLock(func() {
+		sig.ID = s.newID()
+		s.signatures = append(s.signatures, sig)
+		writeData(w, http.StatusOK, map[string]string{"id": sig.ID, "name": sig.Name})
+	})
+}
+
+func (s *Server) handleListFolders(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		folders := make([]zoho.Folder, len(s.folders))
+		for i, f := range s.folders {
+			f.MessageCount, f.UnreadCount = 0, 0
+			for _, m := range s.messages {
+				if m.FolderID == f.FolderID {
+					f.MessageCount++
+					if m.Status == "0" {
+						f.UnreadCount++
+					}
+				}
+			}
+			folders[i] = f
+		}
+		writeData(w, http.StatusOK, folders)
+	})
+}
+
+func (s *Server) handleCreateFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FolderName     string `json:"folderName"`
+		ParentFolderID string `json:"parentFolderId"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.FolderName == "" || strings.Contains(req.FolderName, "/") {
+		writeError(w, http.StatusBadRequest, "invalid folderName")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := "/" + req.FolderName
+	if req.ParentFolderID != "" {
+		parent := s.folderByID(req.ParentFolderID)
+		if parent == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+		path = parent.Path + path
+	}
+	if s.folderByPath(path) != nil {
+		writeError(w, http.StatusBadRequest, "FOLDER_ALREADY_EXIST")
+		return
+	}
+
+	id := s.addFolder(req.FolderName, path, "")
+	f := s.folderByID(id)
+	f.ParentFolderID = req.ParentFolderID
+	writeData(w, http.StatusOK, *f)
+}
+
+func (s *Server) handleUpdateFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode           string `json:"mode"`
+		FolderName     string `json:"folderName"`
+		ParentFolderID string `json:"parentFolderId"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := s.folderByID(r.PathValue("folder"))
+	if f == nil {
+		writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+		return
+	}
+
+	switch req.Mode {
+	case "rename":
+		if req.FolderName == "" || strings.Contains(req.FolderName, "/") {
+			writeError(w, http.StatusBadRequest, "invalid folderName")
+			return
+		}
+		if f.FolderType != "" {
+			writeError(w, http.StatusBadRequest, "system folders cannot be renamed")
+			return
+		}
+		f.FolderName = req.FolderName
+		s.refreshFolderPaths()
+	case "move":
+		parent := s.folderByID(req.ParentFolderID)
+		if parent == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+		if f.FolderType != "" || parent.FolderID == f.FolderID || strings.HasPrefix(parent.Path+"/", f.Path+"/") {
+			writeError(w, http.StatusBadRequest, "invalid parentFolderId")
+			return
+		}
+		f.ParentFolderID = parent.FolderID
+		s.refreshFolderPaths()
+	case "emptyFolder":
+		s.messages = slices.DeleteFunc(s.messages, func(m *Message) bool {
+			return m.FolderID == f.FolderID
+		})
+	case "markAsRead":
+		for _, m := range s.messages {
+			if m.FolderID == f.FolderID {
+				m.Status = "1"
+			}
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	writeData(w, http.StatusOK, nil)
+}
+
+// handleDeleteFolder deletes a user folder with its subfolders and messages
+func (s *Server) handleDeleteFolder(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		f := s.folderByID(r.PathValue("folder"))
+		if f == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+		if f.FolderType != "" {
+			writeError(w, http.StatusBadRequest, "system folders cannot be deleted")
+			return
+		}
+
+		prefix := f.Path + "/"
+		removed := make(map[string]bool)
+		s.folders = slices.DeleteFunc(s.folders, func(folder zoho.Folder) bool {
+			if folder.Path+"/" == prefix || strings.HasPrefix(folder.Path, prefix) {
+				removed[folder.FolderID] = true
+				return true
+			}
+			return false
+		})
+		s.messages = slices.DeleteFunc(s.messages, func(m *Message) bool {
+			return removed[m.FolderID]
+		})
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) folderByPath(path string) *zoho.Folder {
+	for i := range s.folders {
+		if strings.EqualFold(s.folders[i].Path, path) {
+			return &s.folders[i]
+		}
+	}
+	return nil
+}
+
+// refreshFolderPaths recomputes every folder path from its parent chain
+func (s *Server) refreshFolderPaths() {
+	var pathOf func(f zoho.Folder) string
+	pathOf = func(f zoho.Folder) string {
+		if parent := s.folderByID(f.ParentFolderID); parent != nil {
+			return pathOf(*parent) + "/" + f.FolderName
+		}
+		return "/" + f.FolderName
+	}
+	for i := range s.folders {
+		s.folders[i].Path = pathOf(s.folders[i])
+	}
+}
+
+func (s *Server) handleListLabels(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		writeData(w, http.StatusOK, append([]zoho.Label{}, s.labels...))
+	})
+}
+
+func (s *Server) handleCreateLabel(w http.ResponseWriter, r *http.Request) {
+	var label zoho.Label
+	if !decodeBody(w, r, &label) {
+		return
+	}
+	if label.LabelName == "" {
+		writeError(w, http.StatusBadRequest, "labelName is required")
+		return
+	}
+
+	s.withLock(func() {
+		if s.labelByName(label.LabelName) != nil {
+			writeError(w, http.StatusBadRequest, "LABEL_ALREADY_EXIST")
+			return
+		}
+		label.LabelID = s.newID()
+		s.labels = append(s.labels, label)
+		writeData(w, http.StatusOK, label)
+	})
+}
+
+func (s *Server) handleUpdateLabel(w http.ResponseWriter, r *http.Request) {
+	var req zoho.Label
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.withLock(func() {
+		label := s.labelByID(r.PathValue("label"))
+		if label == nil {
+			writeError(w, http.StatusNotFound, "LABEL_NOT_EXIST")
+			return
+		}
+		if req.LabelName != "" {
+			if other := s.labelByName(req.LabelName); other != nil && other != label {
+				writeError(w, http.StatusBadRequest, "LABEL_ALREADY_EXIST")
+				return
+			}
+			label.LabelName = req.LabelName
+		}
+		if req.LabelColor != "" {
+			label.LabelColor = req.LabelColor
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+// handleDeleteLabel deletes a label and removes it from every message
+func (s *Server) handleDeleteLabel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("label")
+
+	s.withLock(func() {
+		if s.labelByID(id) == nil {
+			writeError(w, http.StatusNotFound, "LABEL_NOT_EXIST")
+			return
+		}
+		s.labels = slices.DeleteFunc(s.labels, func(l zoho.Label) bool { return l.LabelID == id })
+		for _, m := range s.messages {
+			m.LabelIDs = slices.DeleteFunc(m.LabelIDs, func(l string) bool { return l == id })
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) handleListMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	folderID := q.Get("folderId")
+
+	s.withLock(func() {
+		if folderID != "" && s.folderByID(folderID) == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+
+		var matched []*Message
+		for _, m := range s.messages {
+			if folderID == "" || m.FolderID == folderID {
+				matched = append(matched, m)
+			}
+		}
+		writeData(w, http.StatusOK, summaries(page(sortNewestFirst(matched), q)))
+	})
+}
+
+func (s *Server) handleSearchMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	key := q.Get("searchKey")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "searchKey is required")
+		return
+	}
+
+	s.withLock(func() {
+		var matched []*Message
+		for _, m := range s.messages {
+			if matchSearch(m, key) {
+				matched = append(matched, m)
+			}
+		}
+		writeData(w, http.StatusOK, summaries(page(sortNewestFirst(matched), q)))
+	})
+}
+
+// folderMessage looks up the message addressed by the request path
+func (s *Server) folderMessage(w http.ResponseWriter, r *http.Request) *Message {
+	m := s.messageByID(r.PathValue("message"))
+	if m == nil || m.FolderID != r.PathValue("folder") {
+		writeError(w, http.StatusNotFound, "MESSAGE_NOT_EXIST")
+		return nil
+	}
+	return m
+}
+
+func (s *Server) handleMessageDetails(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		if m := s.folderMessage(w, r); m != nil {
+			writeData(w, http.StatusOK, m.MessageMetadata)
+		}
+	})
+}
+
+func (s *Server) handleMessageContent(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		if m := s.folderMessage(w, r); m != nil {
+			writeData(w, http.StatusOK, map[string]string{
+				"messageId": m.MessageID,
+				"content":   m.Content,
+			})
+		}
+	})
+}
+
+func (s *Server) handleOriginalMessage(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		if m := s.folderMessage(w, r); m != nil {
+			w.Header().Set("Content-Type", "message/rfc822")
+			w.WriteHeader(http.StatusOK)
+			w.Write(rawMessage(m))
+		}
+	})
+}
+
+// rawMessage returns the RFC 822 source of a stored message
+func rawMessage(m *Message) []byte {
+	if len(m.Raw) > 0 {
+		return m.Raw
+	}
+
+	var buf bytes.Buffer
+	received, _ := strconv.ParseInt(m.ReceivedTime, 10, 64)
+	fmt.Fprintf(&buf, "From: %s\r\n", m.FromAddress)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.ToAddress)
+	if m.CcAddress != "" {
+		fmt.Fprintf(&buf, "Cc: %s\r\n", m.CcAddress)
+	}
+	if m.BccAddress != "" {
+		fmt.Fprintf(&buf, "Bcc: %s\r\n", m.BccAddress)
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.UnixMilli(received).UTC().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@zohotest.invalid>\r\n", m.MessageID)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	bodyType, body := messageBody(m)
+	if len(m.Attachments) == 0 {
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", bodyType)
+		buf.Write(body)
+		return buf.Bytes()
+	}
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {bodyType}})
+	part.Write(body)
+	for _, a := range m.Attachments {
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.AttachmentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.AttachmentName})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		part.Write([]byte(base64.StdEncoding.EncodeToString(a.Data)))
+	}
+	mw.Close()
+	return buf.Bytes()
+}
+
+// messageBody returns the content type and body of a message's text: its
+// HTML, together with the plain-text version as multipart/alternative if set
+func messageBody(m *Message) (string, []byte) {
+	if m.TextContent == "" {
+		return "text/html; charset=utf-8", []byte(m.Content)
+	}
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	part.Write([]byte(m.TextContent))
+	part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=utf-8"}})
+	part.Write([]byte(m.Content))
+	mw.Close()
+	return "multipart/alternative; boundary=" + mw.Boundary(), buf.Bytes()
+}
+
+func (s *Server) handleImportMessage(w http.ResponseWriter, r *http.Request) {
+	if ct := r.Header.Get("Content-Type"); ct != "message/rfc822" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unexpected Content-Type: %s", ct))
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_MESSAGE")
+		return
+	}
+	body, _ := io.ReadAll(parsed.Body)
+
+	m := Message{
+		MessageMetadata: zoho.MessageMetadata{
+			FolderID:    r.PathValue("folder"),
+			FromAddress: parsed.Header.Get("From"),
+			ToAddress:   parsed.Header.Get("To"),
+			CcAddress:   parsed.Header.Get("Cc"),
+			Status:      "0",
+		},
+		Content: string(body),
+		Raw:     data,
+	}
+	if subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject")); err == nil {
+		m.Subject = subject
+	}
+	if date, err := parsed.Header.Date(); err == nil {
+		m.ReceivedTime = millis(date)
+	}
+	if r.URL.Query().Get("isUnread") == "false" {
+		m.Status = "1"
+	}
+
+	s.withLock(func() {
+		if s.folderByID(m.FolderID) == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+		writeData(w, http.StatusOK, map[string]string{"messageId": s.addMessage(m)})
+	})
+}
+
+func (s *Server) handleUpdateMessages(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode         string   `json:"mode"`
+		MessageID    []string `json:"messageId"`
+		FlagID       string   `json:"flagid"`
+		DestFolderID string   `json:"destfolderId"`
+		LabelID      []string `json:"labelId"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.MessageID) == 0 {
+		writeError(w, http.StatusBadRequest, "messageId is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make([]*Message, len(req.MessageID))
+	for i, id := range req.MessageID {
+		if msgs[i] = s.messageByID(id); msgs[i] == nil {
+			writeError(w, http.StatusNotFound, "MESSAGE_NOT_EXIST")
+			return
+		}
+	}
+
+	var apply func(m *Message)
+	switch req.Mode {
+	case "markAsRead":
+		apply = func(m *Message) { m.Status = "1" }
+	case "markAsUnread":
+		apply = func(m *Message) { m.Status = "0" }
+	case "setFlag":
+		switch req.FlagID {
+		case zoho.FlagInfo, zoho.FlagImportant, zoho.FlagFollowUp, zoho.FlagNone:
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid flagid: %s", req.FlagID))
+			return
+		}
+		apply = func(m *Message) { m.FlagID = req.FlagID }
+	case "moveMessage":
+		if s.folderByID(req.DestFolderID) == nil {
+			writeError(w, http.StatusNotFound, "FOLDER_NOT_EXIST")
+			return
+		}
+		apply = func(m *Message) { m.FolderID = req.DestFolderID }
+	case "applyLabel":
+		for _, id := range req.LabelID {
+			if s.labelByID(id) == nil {
+				writeError(w, http.StatusNotFound, "LABEL_NOT_EXIST")
+				return
+			}
+		}
+		apply = func(m *Message) {
+			for _, id := range req.LabelID {
+				if !slices.Contains(m.LabelIDs, id) {
+					m.LabelIDs = append(m.LabelIDs, id)
+				}
+			}
+		}
+	case "removeLabel":
+		apply = func(m *Message) {
+			m.LabelIDs = slices.DeleteFunc(m.LabelIDs, func(id string) bool {
+				return slices.Contains(req.LabelID, id)
+			})
+		}
+	case "archiveMails":
+		apply = func(m *Message) { m.Archived = true }
+	case "markAsSpam":
+		spam := s.folderByName("Spam").FolderID
+		apply = func(m *Message) { m.FolderID = spam }
+	case "markAsNotSpam":
+		inbox := s.folderByName("Inbox").FolderID
+		apply = func(m *Message) { m.FolderID = inbox }
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+		return
+	}
+
+	for _, m := range msgs {
+		apply(m)
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+// handleDeleteMessage moves a message to Trash, or removes it when it is
+// already in Trash or expunge=true is set
+func (s *Server) handleDeleteMessage(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		m := s.folderMessage(w, r)
+		if m == nil {
+			return
+		}
+
+		trash := s.folderByName("Trash").FolderID
+		if m.FolderID != trash && r.URL.Query().Get("expunge") != "true" {
+			m.FolderID = trash
+			writeData(w, http.StatusOK, nil)
+			return
+		}
+
+		for i, stored := range s.messages {
+			if stored == m {
+				s.messages = append(s.messages[:i], s.messages[i+1:]...)
+				break
+			}
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) handleListAttachments(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		m := s.folderMessage(w, r)
+		if m == nil {
+			return
+		}
+		atts := make([]zoho.Attachment, len(m.Attachments))
+		for i, a := range m.Attachments {
+			atts[i] = a.Attachment
+		}
+		writeData(w, http.StatusOK, atts)
+	})
+}
+
+func (s *Server) handleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		m := s.folderMessage(w, r)
+		if m == nil {
+			return
+		}
+		for _, a := range m.Attachments {
+			if a.AttachmentID == r.PathValue("attachment") {
+				w.Header().Set("Content-Type", a.AttachmentType)
+				w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.AttachmentName))
+				// ServeContent answers Range and If-Range requests, so clients can resume downloads
+				w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(a.Data)))
+				http.ServeContent(w, r, a.AttachmentName, time.Time{}, bytes.NewReader(a.Data))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "ATTACHMENT_NOT_EXIST")
+	})
+}
+
+func (s *Server) handleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("fileName")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "fileName is required")
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/octet-stream" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unexpected Content-Type: %s", ct))
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.withLock(func() {
+		storeName := s.newID()
+		s.uploads[storeName] = StoredAttachment{
+			Attachment: zoho.Attachment{
+				AttachmentName: name,
+				AttachmentSize: int64(len(data)),
+				AttachmentType: http.DetectContentType(data),
+			},
+			Data: data,
+		}
+		writeData(w, http.StatusOK, zoho.AttachmentReference{
+			StoreName:      storeName,
+			AttachmentName: name,
+			AttachmentPath: "/" + storeName + "/" + name,
+		})
+	})
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	var req zoho.SendEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	origID := r.PathValue("message")
+	if origID != "" {
+		if s.messageByID(origID) == nil {
+			writeError(w, http.StatusNotFound, "MESSAGE_NOT_EXIST")
+			return
+		}
+		switch req.Action {
+		case "reply", "replyall", "forward":
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported action: %s", req.Action))
+			return
+		}
+	}
+
+	switch {
+	case req.Mode == "" && req.IsSchedule:
+		s.schedule(w, req, origID)
+	case req.Mode == "":
+		s.deliver(w, req, origID)
+	case req.Mode == "draft":
+		atts, ok := s.resolveUploads(w, req)
+		if !ok {
+			return
+		}
+		id := s.addMessage(draftMessage(req, s.folderByName("Drafts").FolderID, atts))
+		s.drafts[id] = draft{req: req, origID: origID}
+		writeData(w, http.StatusOK, map[string]string{"messageId": id})
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+	}
+}
+
+// schedule files a scheduled message in Outbox. It is kept as a draft so that
+// cancelling (moving it to Drafts) lets it be edited and sent later.
+func (s *Server) schedule(w http.ResponseWriter, req zoho.SendEmailRequest, origID string) {
+	if req.ScheduleType != zoho.ScheduleTypeCustom {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported scheduleType: %d", req.ScheduleType))
+		return
+	}
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid timeZone: %s", req.TimeZone))
+		return
+	}
+	at, err := time.ParseInLocation(zoho.ScheduleTimeLayout, req.ScheduleTime, loc)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid scheduleTime: %s", req.ScheduleTime))
+		return
+	}
+	if req.ToAddress == "" && req.Action != "reply" && req.Action != "replyall" {
+		writeError(w, http.StatusBadRequest, "toAddress is required")
+		return
+	}
+	atts, ok := s.resolveUploads(w, req)
+	if !ok {
+		return
+	}
+
+	msg := draftMessage(req, s.folderByName("Outbox").FolderID, atts)
+	msg.ReceivedTime = millis(at)
+	id := s.addMessage(msg)
+
+	req.IsSchedule, req.ScheduleType, req.TimeZone, req.ScheduleTime = false, 0, "", ""
+	s.drafts[id] = draft{req: req, origID: origID}
+	writeData(w, http.StatusOK, map[string]string{"messageId": id})
+}
+
+// handleUpdateDraft replaces the content of a draft (mode=draft) or sends it (mode=sendDraft)
+func (s *Server) handleUpdateDraft(w http.ResponseWriter, r *http.Request) {
+	var req zoho.SendEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("message")
+	d, ok := s.drafts[id]
+	msg := s.messageByID(id)
+	if !ok || msg == nil || msg.FolderID != s.folderByName("Drafts").FolderID {
+		writeError(w, http.StatusNotFound, "DRAFT_NOT_EXIST")
+		return
+	}
+
+	switch req.Mode {
+	case "draft":
+		// Like Zoho, an update replaces the whole draft, attachments included
+		atts, ok := s.resolveUploads(w, req)
+		if !ok {
+			return
+		}
+		req.Action = d.req.Action
+		updated := draftMessage(req, msg.FolderID, atts)
+		updated.MessageID = msg.MessageID
+		updated.ThreadID = msg.ThreadID
+		updated.ReceivedTime = msg.ReceivedTime
+		s.messages = slices.DeleteFunc(s.messages, func(m *Message) bool { return m.MessageID == id })
+		s.addMessage(updated)
+		s.drafts[id] = draft{req: req, origID: d.origID}
+		writeData(w, http.StatusOK, map[string]string{"messageId": id})
+	case "sendDraft":
+		d.req.Mode = ""
+		if s.deliver(w, d.req, d.origID) {
+			s.messages = slices.DeleteFunc(s.messages, func(m *Message) bool { return m.MessageID == id })
+			delete(s.drafts, id)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported mode: %s", req.Mode))
+	}
+}
+
+// deliver records a sent message and files a copy in Sent, reporting whether it succeeded
+func (s *Server) deliver(w http.ResponseWriter, req zoho.SendEmailRequest, origID string) bool {
+	if req.ToAddress == "" && req.Action != "reply" && req.Action != "replyall" {
+		writeError(w, http.StatusBadRequest, "toAddress is required")
+		return false
+	}
+	for _, addr := range strings.Split(req.ToAddress+","+req.CcAddress+","+req.BccAddress, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" && !strings.Contains(addr, "@") {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("INVALID_EMAIL: %s", addr))
+			return false
+		}
+	}
+
+	atts, ok := s.resolveUploads(w, req)
+	if !ok {
+		return false
+	}
+
+	s.sent = append(s.sent, req)
+
+	// Keep a copy in Sent so the message is visible to list/search
+	msg := Message{
+		MessageMetadata: zoho.MessageMetadata{
+			FolderID:    s.folderByName("Sent").FolderID,
+			Subject:     req.Subject,
+			FromAddress: cmp.Or(req.FromAddress, Email),
+			ToAddress:   req.ToAddress,
+			CcAddress:   req.CcAddress,
+			Status:      "1",
+		},
+		Content:     req.Content,
+		Attachments: atts,
+	}
+	if orig := s.messageByID(origID); orig != nil {
+		msg.ThreadID = orig.ThreadID
+	}
+	id := s.addMessage(msg)
+
+	writeData(w, http.StatusOK, map[string]string{"messageId": id})
+	return true
+}
+
+// resolveUploads looks up the uploaded attachments a send request refers to
+func (s *Server) resolveUploads(w http.ResponseWriter, req zoho.SendEmailRequest) ([]StoredAttachment, bool) {
+	var atts []StoredAttachment
+	for _, ref := range req.Attachments {
+		up, ok := s.uploads[ref.StoreName]
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown attachment: %s", ref.StoreName))
+			return nil, false
+		}
+		atts = append(atts, up)
+	}
+	return atts, true
+}
+
+// draftMessage builds the stored copy of a draft
+func draftMessage(req zoho.SendEmailRequest, folderID string, atts []StoredAttachment) Message {
+	return Message{
+		MessageMetadata: zoho.MessageMetadata{
+			FolderID:    folderID,
+			Subject:     req.Subject,
+			FromAddress: cmp.Or(req.FromAddress, Email),
+			ToAddress:   req.ToAddress,
+			CcAddress:   req.CcAddress,
+			Status:      "1",
+		},
+		Content:     req.Content,
+		TextContent: req.TextContent,
+		Attachments: atts,
+		BccAddress:  req.BccAddress,
+	}
+}
+
+// summaries converts stored messages to list-view summaries
+func summaries(msgs []*Message) []zoho.MessageSummary {
+	out := make([]zoho.MessageSummary, len(msgs))
+	for i, m := range msgs {
+		out[i] = zoho.MessageSummary{
+			MessageID:     m.MessageID,
+			ThreadID:      m.ThreadID,
+			FolderID:      m.FolderID,
+			Subject:       m.Subject,
+			FromAddress:   m.FromAddress,
+			Sender:        m.Sender,
+			ToAddress:     m.ToAddress,
+			CcAddress:     m.CcAddress,
+			ReceivedTime:  m.ReceivedTime,
+			Status:        m.Status,
+			HasAttachment: m.HasAttachment,
+			Size:          m.MessageSize,
+			FlagID:        m.FlagID,
+			Priority:      m.Priority,
+			Summary:       m.Summary,
+			LabelIDs:      slices.Clone(m.LabelIDs),
+		}
+	}
+	return out
+}
+
+// sortNewestFirst orders messages by received time, newest first, like Zoho does
+func sortNewestFirst(msgs []*Message) []*Message {
+	sort.SliceStable(msgs, func(i, j int) bool {
+		a, _ := strconv.ParseInt(msgs[i].ReceivedTime, 10, 64)
+		b, _ := strconv.ParseInt(msgs[j].ReceivedTime, 10, 64)
+		return a > b
+	})
+	return msgs
+}
+
+// page applies the start and limit query parameters. start is read as the
+// 0-based offset MailClient sends.
+func page(msgs []*Message, q map[string][]string) []*Message {
+	from := intParam(q, "start", 0)
+	if from < 0 {
+		from = 0
+	}
+	limit := intParam(q, "limit", 10)
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if from >= len(msgs) {
+		return nil
+	}
+	to := from + limit
+	if to > len(msgs) {
+		to = len(msgs)
+	}
+	return msgs[from:to]
+}
+
+// matchSearch reports whether m matches a Zoho search key.
+// Supports the operators produced by zoho.SearchQuery plus free text,
+// which matches subject, sender and body. Values may be "quoted phrases".
+func matchSearch(m *Message, key string) bool {
+	for _, term := range searchTerms(key) {
+		op, val, hasOp := strings.Cut(term, ":")
+		if !hasOp || strings.HasPrefix(term, `"`) {
+			op, val = "", term
+		}
+		val = strings.ToLower(strings.Trim(val, `"`))
+
+		ok := true
+		switch op {
+		case "from":
+			ok = strings.Contains(strings.ToLower(m.FromAddress), val)
+		case "to":
+			ok = strings.Contains(strings.ToLower(m.ToAddress+" "+m.CcAddress), val)
+		case "subject":
+			ok = strings.Contains(strings.ToLower(m.Subject), val)
+		case "has":
+			ok = val != "attachment" || m.HasAttachment == "1"
+		case "is":
+			ok = val != "unread" || m.Status == "0"
+		case "after", "before":
+			day, err := time.Parse("2006/01/02", val)
+			if err != nil {
+				return false
+			}
+			ms, _ := strconv.ParseInt(m.ReceivedTime, 10, 64)
+			received := time.UnixMilli(ms).UTC()
+			if op == "after" {
+				ok = !received.Before(day)
+			} else {
+				ok = received.Before(day)
+			}
+		default:
+			text := strings.ToLower(m.Subject + " " + m.FromAddress + " " + m.Content)
+			ok = strings.Contains(text, strings.ToLower(strings.Trim(term, `"`)))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// searchTerms splits a search key on spaces outside double quotes
+func searchTerms(key string) []string {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range key {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case r == ' ' && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
+
+// stripTags removes HTML tags for message summaries
+func stripTags(html string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// intParam parses an integer query parameter, returning def when absent or invalid
+func intParam(q map[string][]string, name string, def int) int {
+	vals := q[name]
+	if len(vals) == 0 {
+		return def
+	}
+	n, err := strconv.Atoi(vals[0])
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// readBody reads the request body and replaces it so handlers can read it again
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+func (s *Server) handleListFilters(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		writeData(w, http.StatusOK, append([]zoho.MailFilter{}, s.filters...))
+	})
+}
+
+func (s *Server) handleGetFilter(w http.ResponseWriter, r *http.Request) {
+	s.withLock(func() {
+		f := s.filterByID(r.PathValue("filter"))
+		if f == nil {
+			writeError(w, http.StatusNotFound, "FILTER_NOT_EXIST")
+			return
+		}
+		writeData(w, http.StatusOK, *f)
+	})
+}
+
+// handleCreateFilter adds a filter after the existing ones
+func (s *Server) handleCreateFilter(w http.ResponseWriter, r *http.Request) {
+	var f zoho.MailFilter
+	if !decodeBody(w, r, &f) {
+		return
+	}
+	if msg := validateFilter(f); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.withLock(func() {
+		f.FilterID = s.newID()
+		f.FilterOrder = len(s.filters) + 1
+		s.filters = append(s.filters, f)
+		writeData(w, http.StatusOK, f)
+	})
+}
+
+// handleUpdateFilter replaces a filter, keeping its ID and position
+func (s *Server) handleUpdateFilter(w http.ResponseWriter, r *http.Request) {
+	var req zoho.MailFilter
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if msg := validateFilter(req); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.withLock(func() {
+		f := s.filterByID(r.PathValue("filter"))
+		if f == nil {
+			writeError(w, http.StatusNotFound, "FILTER_NOT_EXIST")
+			return
+		}
+		req.FilterID, req.FilterOrder = f.FilterID, f.FilterOrder
+		*f = req
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) handleDeleteFilter(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("filter")
+
+	s.withLock(func() {
+		if s.filterByID(id) == nil {
+			writeError(w, http.StatusNotFound, "FILTER_NOT_EXIST")
+			return
+		}
+		s.filters = slices.DeleteFunc(s.filters, func(f zoho.MailFilter) bool { return f.FilterID == id })
+		for i := range s.filters {
+			s.filters[i].FilterOrder = i + 1
+		}
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+// handleReorderFilters sets the filter order; every filter must be listed once
+func (s *Server) handleReorderFilters(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode      string   `json:"mode"`
+		FilterIDs []string `json:"filterIds"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Mode != "reorder" {
+		writeError(w, http.StatusBadRequest, "INVALID_MODE")
+		return
+	}
+
+	s.withLock(func() {
+		if len(req.FilterIDs) != len(s.filters) {
+			writeError(w, http.StatusBadRequest, "FILTER_ORDER_INCOMPLETE")
+			return
+		}
+		reordered := make([]zoho.MailFilter, 0, len(s.filters))
+		for i, id := range req.FilterIDs {
+			f := s.filterByID(id)
+			if f == nil || slices.ContainsFunc(reordered, func(o zoho.MailFilter) bool { return o.FilterID == id }) {
+				writeError(w, http.StatusBadRequest, "FILTER_NOT_EXIST")
+				return
+			}
+			c := *f
+			c.FilterOrder = i + 1
+			reordered = append(reordered, c)
+		}
+		s.filters = reordered
+		writeData(w, http.StatusOK, nil)
+	})
+}
+
+func (s *Server) filterByID(id string) *zoho.MailFilter {
+	for i := range s.filters {
+		if s.filters[i].FilterID == id {
+			return &s.filters[i]
+		}
+	}
+	return nil
+}
+
+// validateFilter returns the error Zoho reports for an incomplete filter, or ""
+func validateFilter(f zoho.MailFilter) string {
+	switch {
+	case f.FilterName == "":
+		return "filterName is required"
+	case f.ConditionType != "all" && f.ConditionType != "any":
+		return "conditionType must be all or any"
+	case len(f.Conditions) == 0:
+		return "conditions are required"
+	case len(f.Actions) == 0:
+		return "actions are required"
+	}
+	return ""
+}
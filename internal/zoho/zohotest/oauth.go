@@ -0,0 +1,85 @@
+package zohotest
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Credentials accepted by the fake accounts server
+const (
+	AuthCode     = "fake-auth-code"
+	RefreshToken = "fake-refresh-token"
+)
+
+// oauthRoutes registers the Zoho Accounts OAuth endpoints.
+// Any client ID and secret are accepted; the issued access token is the
+// server's AccessToken (or "fake-access-token" when unset).
+func (s *Server) oauthRoutes() {
+	s.mux.HandleFunc("GET /oauth/v2/auth", s.handleAuthorize)
+	s.mux.HandleFunc("POST /oauth/v2/token", s.handleToken)
+}
+
+// issuedToken returns the access token handed out by the token endpoint
+func (s *Server) issuedToken() string {
+	if s.AccessToken != "" {
+		return s.AccessToken
+	}
+	return "fake-access-token"
+}
+
+// handleAuthorize approves every request immediately by redirecting back
+// to redirect_uri with a code, like a user clicking "Accept"
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	params := redirect.Query()
+	params.Set("code", AuthCode)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// handleToken implements the authorization_code and refresh_token grants.
+// Like Zoho, errors are reported in the body of an HTTP 200 response.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusOK, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("client_id") == "" {
+		writeJSON(w, http.StatusOK, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	resp := map[string]any{
+		"access_token": s.issuedToken(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"api_domain":   "https://www.zohoapis.com",
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		if r.PostForm.Get("code") != AuthCode {
+			writeJSON(w, http.StatusOK, map[string]string{"error": "invalid_code"})
+			return
+		}
+		resp["refresh_token"] = RefreshToken
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != RefreshToken {
+			writeJSON(w, http.StatusOK, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
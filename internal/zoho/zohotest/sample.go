@@ -0,0 +1,113 @@
+package zohotest
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// LoadSampleData seeds a small, realistic mailbox and organization.
+// Log entries are timestamped relative to now so they fall inside the
+// default time windows of the audit and login-history commands.
+func (s *Server) LoadSampleData() {
+	s.AddLabel("Important", "#e74c3c")
+	s.AddLabel("Receipts", "#3498db")
+	projects := s.AddFolder("Projects")
+
+	welcome := s.AddMessage(Message{
+		MessageMetadata: zoho.MessageMetadata{
+			Subject:     "Welcome to Zoho Mail",
+			FromAddress: "support@zohomail.com",
+			ToAddress:   Email,
+			Status:      "1",
+		},
+		Content: "<p>Your mailbox is ready.</p>",
+	})
+	s.AddMessage(Message{
+		MessageMetadata: zoho.MessageMetadata{
+			Subject:     "Q3 report",
+			FromAddress: "alice@example.com",
+			ToAddress:   Email,
+		},
+		Content: "<p>Report attached.</p>",
+		Attachments: []StoredAttachment{{
+			Attachment: zoho.Attachment{AttachmentName: "report.csv", AttachmentType: "text/csv"},
+			Data:       []byte("quarter,revenue\nQ3,42\n"),
+		}},
+	})
+	s.AddMessage(Message{
+		MessageMetadata: zoho.MessageMetadata{
+			Subject:     "Re: Welcome to Zoho Mail",
+			FromAddress: "support@zohomail.com",
+			ToAddress:   Email,
+			ThreadID:    welcome,
+		},
+		Content: "<p>Let us know if you have questions.</p>",
+	})
+	s.AddMessage(Message{
+		MessageMetadata: zoho.MessageMetadata{
+			FolderID:    projects,
+			Subject:     "Launch checklist",
+			FromAddress: "bob@example.com",
+			ToAddress:   Email,
+		},
+		Content: "<ul><li>DNS</li><li>DKIM</li></ul>",
+	})
+
+	alice := s.AddUser(zoho.User{
+		PrimaryEmailID: "alice@example.com",
+		FirstName:      "Alice",
+		LastName:       "Smith",
+	})
+	s.AddUser(zoho.User{
+		PrimaryEmailID: "bob@example.com",
+		FirstName:      "Bob",
+		LastName:       "Jones",
+		MailboxStatus:  "disabled",
+	})
+	s.AddGroup(zoho.Group{
+		GroupName:         "Sales",
+		GroupEmailAddress: "sales@example.com",
+		Description:       "Sales team",
+	}, "alice@example.com")
+
+	now := time.Now().Truncate(time.Minute)
+	s.AddAuditLog(zoho.AuditLog{
+		Category:      "Users",
+		Operation:     "addUser",
+		OperationType: "Add",
+		PerformedBy:   Email,
+		PerformedOn:   "alice@example.com",
+		ClientIP:      "192.0.2.10",
+		RequestTime:   now.Add(-48 * time.Hour).UnixMilli(),
+		Data:          json.RawMessage(`{}`),
+	})
+	s.AddLoginHistory(zoho.LoginHistoryEntry{
+		UserID:       alice,
+		EmailAddress: "alice@example.com",
+		IPAddress:    "192.0.2.20",
+		LoginTime:    now.Add(-24 * time.Hour).UnixMilli(),
+		Status:       "success",
+		AccessType:   "web",
+		ClientInfo:   "Firefox",
+	})
+	s.AddSMTPLog(zoho.SMTPLogEntry{
+		MessageID:     "<q3-report@example.com>",
+		FromAddress:   "alice@example.com",
+		ToAddresses:   []string{Email},
+		Subject:       "Q3 report",
+		TransactionID: "smtp-1",
+		Timestamp:     now.Add(-2 * time.Hour).UnixMilli(),
+		Status:        "delivered",
+	})
+	s.AddDeliveryLog(zoho.DeliveryLog{
+		MessageID:    "<q3-report@example.com>",
+		Subject:      "Q3 report",
+		FromAddress:  "alice@example.com",
+		ToAddress:    Email,
+		Status:       "delivered",
+		SentTime:     millis(now.Add(-2 * time.Hour)),
+		DeliveryTime: millis(now.Add(-2*time.Hour + time.Second)),
+	})
+}
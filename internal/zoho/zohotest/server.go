@@ -0,0 +1,260 @@
+// Package zohotest provides an in-memory fake of the Zoho Mail and Admin APIs.
+//
+// The fake implements the endpoints used by zoho.MailClient, zoho.AdminClient and
+// zoho.MailAdminClient so the CLI can be exercised hermetically, either in go test
+// via httptest or as a standalone server (see cmd/zoh-fakeserver) that the CLI is
+// pointed at with ZOH_API_BASE.
+package zohotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SeMmyT/zohcli/internal/zoho"
+)
+
+// Fixed identifiers of the primary account served by the fake
+const (
+	AccountID = "1000000000001"
+	ZOID      = int64(2000000000001)
+	ZUID      = int64(3000000000001)
+	Email     = "admin@example.com"
+	Domain    = "example.com"
+)
+
+// baseTime anchors generated timestamps so fixtures are deterministic
+var baseTime = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// Message is a message stored by the fake server
+type Message struct {
+	zoho.MessageMetadata
+	Summary     string
+	Content     string
+	TextContent string // plain-text alternative of Content, if any
+	Attachments []StoredAttachment
+	LabelIDs    []string
+	Archived    bool
+	Raw         []byte // original RFC 822 source; built from the other fields when empty
+	BccAddress  string // set on drafts; only visible in the RFC 822 source
+}
+
+// draft is a saved draft request together with the message it replies to or forwards
+type draft struct {
+	req    zoho.SendEmailRequest
+	origID string
+}
+
+// StoredAttachment is an attachment together with its content
+type StoredAttachment struct {
+	zoho.Attachment
+	Data []byte
+}
+
+// Request records a request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// group is a group together with its members
+type group struct {
+	zoho.Group
+	Members []zoho.GroupMember
+}
+
+// Server is an in-memory fake of the Zoho Mail and Admin APIs.
+// It implements http.Handler; wrap it with httptest.NewServer in tests.
+type Server struct {
+	// AccessToken, when set, is the only bearer token the API endpoints accept
+	AccessToken string
+
+	mu  sync.Mutex
+	mux *http.ServeMux
+
+	nextID   int64
+	requests []Request
+
+	account        zoho.AccountData
+	displayName    string
+	vacation       json.RawMessage
+	timeZone       string
+	userVacations  map[int64]json.RawMessage
+	forwardDetails json.RawMessage
+	signatures     []zoho.Signature
+	filters        []zoho.MailFilter
+
+	folders  []zoho.Folder
+	labels   []zoho.Label
+	messages []*Message
+	uploads  map[string]StoredAttachment
+	sent     []zoho.SendEmailRequest
+	drafts   map[string]draft
+
+	users        []zoho.User
+	groups       []*group
+	domains      []zoho.Domain
+	auditLogs    []zoho.AuditLog
+	loginHistory []zoho.LoginHistoryEntry
+	smtpLogs     []zoho.SMTPLogEntry
+	deliveryLogs []zoho.DeliveryLog
+	spam         map[string][]string
+	retention    json.RawMessage
+}
+
+// New creates a fake server holding one admin account, its primary domain and
+// the standard system folders. Use the Add* methods to seed further data.
+func New() *Server {
+	s := &Server{
+		mux:           http.NewServeMux(),
+		nextID:        100,
+		displayName:   "Admin User",
+		uploads:       make(map[string]StoredAttachment),
+		drafts:        make(map[string]draft),
+		spam:          make(map[string][]string),
+		userVacations: make(map[int64]json.RawMessage),
+		retention:     json.RawMessage(`{"status":{"code":200,"description":"success"},"data":{"retentionPeriod":0,"enabled":false}}`),
+	}
+
+	s.account.AccountID = AccountID
+	s.account.ZUID = ZUID
+	s.account.PolicyID.Zoid = ZOID
+	s.account.PrimaryEmailAddress = Email
+	s.account.DisplayName = s.displayName
+	s.account.Role = "super_admin"
+
+	for _, name := range []string{"Inbox", "Drafts", "Templates", "Sent", "Spam", "Trash", "Outbox"} {
+		s.addFolder(name, "/"+name, strings.ToLower(name))
+	}
+
+	s.users = append(s.users, zoho.User{
+		ZUID:           ZUID,
+		AccountID:      AccountID,
+		EmailAddress:   []zoho.EmailAddress{{MailID: Email, IsPrimary: true}},
+		PrimaryEmailID: Email,
+		FirstName:      "Admin",
+		LastName:       "User",
+		DisplayName:    s.displayName,
+		Role:           "super_admin",
+		MailboxStatus:  "enabled",
+		PlanStorage:    5 * 1024 * 1024 * 1024,
+	})
+
+	s.domains = append(s.domains, zoho.Domain{
+		DomainName:         Domain,
+		DomainID:           s.newID(),
+		VerificationStatus: true,
+		MXStatus:           "enabled",
+		MailHostingEnabled: true,
+		Primary:            true,
+		SPFStatus:          true,
+	})
+
+	s.routes()
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := readBody(r)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   body,
+	})
+	s.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/api/") && s.AccessToken != "" {
+		if r.Header.Get("Authorization") != "Bearer "+s.AccessToken {
+			writeError(w, http.StatusUnauthorized, "INVALID_OAUTHTOKEN")
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// routes registers every fake endpoint
+func (s *Server) routes() {
+	s.oauthRoutes()
+	s.mailRoutes()
+	s.adminRoutes()
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("URL_RULE_NOT_CONFIGURED: %s %s", r.Method, r.URL.Path))
+	})
+}
+
+// newID returns a fresh numeric identifier. Callers must hold s.mu or be in New.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%d", 1700000000000000+s.nextID)
+}
+
+// withLock runs fn while holding the server lock
+func (s *Server) withLock(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+// statusBody is the status envelope of every Zoho API response
+type statusBody struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// writeData writes a successful Zoho response envelope around data
+func writeData(w http.ResponseWriter, httpStatus int, data any) {
+	resp := map[string]any{
+		"status": statusBody{Code: 200, Description: "success"},
+	}
+	if data != nil {
+		resp["data"] = data
+	}
+	writeJSON(w, httpStatus, resp)
+}
+
+// writeError writes a Zoho error envelope
+func writeError(w http.ResponseWriter, httpStatus int, moreInfo string) {
+	writeJSON(w, httpStatus, map[string]any{
+		"status": statusBody{Code: httpStatus, Description: http.StatusText(httpStatus)},
+		"data":   map[string]string{"moreInfo": moreInfo},
+	})
+}
+
+// writeJSON encodes v as the response body
+func writeJSON(w http.ResponseWriter, httpStatus int, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes the JSON request body into v, writing a 400 on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("JSON_PARSE_ERROR: %v", err))
+		return false
+	}
+	return true
+}
+
+// millis formats t as a Unix-milliseconds string, the way Zoho returns timestamps
+func millis(t time.Time) string {
+	return fmt.Sprintf("%d", t.UnixMilli())
+}
@@ -3,6 +3,10 @@
 # Exercises all read-only endpoints and safe write operations
 # Usage: ./scripts/e2e-smoke.sh [email]
 #   email: address to send test email to (optional, skips send tests if omitted)
+#
+# To run offline against the fake server instead of a live tenant:
+#   go run ./cmd/zoh-fakeserver &
+#   ZOH_API_BASE=http://127.0.0.1:8787 ZOH_ACCESS_TOKEN=test ./scripts/e2e-smoke.sh
 
 set -uo pipefail
 